@@ -372,6 +372,27 @@ func TestComlileAndInvokeFunction(t *testing.T) {
 		e.checkNextLine(t, h.StringLE())
 	})
 
+	t.Run("check diff", func(t *testing.T) {
+		e.Run(t, "neo-go", "contract", "diff",
+			"--rpc-endpoint", "http://"+e.RPC.Addr,
+			"--old-hash", h.StringLE(), "--new-manifest", manifestName)
+		e.checkEOF(t)
+	})
+
+	t.Run("verify source", func(t *testing.T) {
+		cmd := []string{"neo-go", "contract", "verify-source",
+			"--rpc-endpoint", "http://" + e.RPC.Addr, "--hash", h.StringLE()}
+		t.Run("missing input", func(t *testing.T) {
+			e.RunWithError(t, cmd...)
+		})
+		t.Run("different source", func(t *testing.T) {
+			e.RunWithError(t, append(cmd, "--in", "testdata/deploy/updated.go")...)
+		})
+		e.Run(t, append(cmd, "--in", "testdata/deploy/main.go")...)
+		e.checkNextLine(t, "^Contract 0x"+h.StringLE()+" matches the sources")
+		e.checkEOF(t)
+	})
+
 	cmd := []string{"neo-go", "contract", "testinvokefunction",
 		"--rpc-endpoint", "http://" + e.RPC.Addr}
 	t.Run("missing hash", func(t *testing.T) {
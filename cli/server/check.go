@@ -0,0 +1,330 @@
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/dao"
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/core/stateroot"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+)
+
+// headerBatchCount is the number of header hashes stored in a single
+// IXHeaderHashList item.
+const headerBatchCount = 2000
+
+// checkLogInterval is the number of blocks between check progress messages.
+const checkLogInterval = 100000
+
+type (
+	// dbIssue is a single DB inconsistency found at the given height.
+	dbIssue struct {
+		Height uint32
+		Err    error
+	}
+
+	// dbChecker walks over DB contents and collects inconsistencies.
+	dbChecker struct {
+		store  storage.Store
+		dao    *dao.Simple
+		cfg    config.ProtocolConfiguration
+		log    *zap.Logger
+		issues []dbIssue
+	}
+)
+
+// checkDB checks the integrity of the DB specified by the node configuration.
+func checkDB(ctx *cli.Context) error {
+	cfg, err := getConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	log, err := handleLoggingParams(ctx, cfg.ApplicationConfiguration)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	store, err := storage.NewStore(cfg.ApplicationConfiguration.DBConfiguration)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to open DB: %w", err), 1)
+	}
+	defer store.Close()
+
+	issues, err := checkStore(newGraceContext(), store, cfg.ProtocolConfiguration, !ctx.Bool("skip-mpt"), log)
+	for _, issue := range issues {
+		log.Error("inconsistency found", zap.Uint32("height", issue.Height), zap.Error(issue.Err))
+	}
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if len(issues) != 0 {
+		return cli.NewExitError(fmt.Errorf("%d inconsistencies found", len(issues)), 1)
+	}
+	log.Info("no inconsistencies found")
+	return nil
+}
+
+// checkStore checks header chain, blocks, transactions and (if checkMPT is
+// set) the current state root of the given store. It returns the list of
+// inconsistencies found and an error if the check can't be completed.
+func checkStore(ctx context.Context, store storage.Store, cfg config.ProtocolConfiguration, checkMPT bool, log *zap.Logger) ([]dbIssue, error) {
+	c := &dbChecker{
+		store: store,
+		dao:   dao.NewSimple(store, cfg.StateRootInHeader, cfg.P2PSigExtensions),
+		cfg:   cfg,
+		log:   log,
+	}
+	blockHeight, err := c.dao.GetCurrentBlockHeight()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current block height: %w", err)
+	}
+	hashes := c.headerHashes()
+	if len(hashes) == 0 {
+		return c.issues, errors.New("no headers found")
+	}
+	log.Info("checking blocks",
+		zap.Uint32("blockHeight", blockHeight),
+		zap.Int("headerHeight", len(hashes)-1))
+	if err := c.checkBlocks(ctx, hashes, blockHeight); err != nil {
+		return c.issues, err
+	}
+	log.Info("checking transactions")
+	if err := c.checkTransactions(ctx, blockHeight); err != nil {
+		return c.issues, err
+	}
+	if checkMPT {
+		log.Info("checking state root", zap.Uint32("height", blockHeight))
+		if err := c.checkStateRoot(ctx, blockHeight); err != nil {
+			return c.issues, err
+		}
+	}
+	return c.issues, nil
+}
+
+func (c *dbChecker) report(height uint32, format string, args ...interface{}) {
+	c.issues = append(c.issues, dbIssue{Height: height, Err: fmt.Errorf(format, args...)})
+}
+
+// headerHashes returns the list of header hashes restored from
+// IXHeaderHashList items and the chain of headers starting from the current
+// one. The list is truncated at the first inconsistency.
+func (c *dbChecker) headerHashes() []util.Uint256 {
+	var hashes []util.Uint256
+	c.store.Seek(storage.IXHeaderHashList.Bytes(), func(k, v []byte) {
+		if len(k) != 5 {
+			c.report(uint32(len(hashes)), "invalid header hash list key %x", k)
+			return
+		}
+		start := binary.LittleEndian.Uint32(k[1:])
+		if int(start) != len(hashes) || len(hashes)%headerBatchCount != 0 {
+			c.report(start, "header hash list is not contiguous: expected batch at %d", len(hashes))
+			return
+		}
+		var batch []util.Uint256
+		r := io.NewBinReaderFromBuf(v)
+		r.ReadArray(&batch)
+		if r.Err != nil || len(batch) != headerBatchCount {
+			c.report(start, "invalid header hash list batch (%d hashes): %v", len(batch), r.Err)
+			return
+		}
+		hashes = append(hashes, batch...)
+	})
+
+	currHeight, currHash, err := c.dao.GetCurrentHeaderHeight()
+	if err != nil {
+		c.report(uint32(len(hashes)), "failed to get current header: %w", err)
+		return hashes
+	}
+	if int(currHeight) < len(hashes) {
+		c.report(currHeight, "current header is below stored header hash list (%d)", len(hashes))
+		return hashes[:currHeight+1]
+	}
+	// Headers after the last stored batch are only available via PrevHash
+	// links starting from the current one.
+	var tail = make([]util.Uint256, currHeight+1-uint32(len(hashes)))
+	var hash = currHash
+	for i := len(tail) - 1; i >= 0; i-- {
+		tail[i] = hash
+		b, err := c.dao.GetBlock(hash)
+		if err != nil {
+			c.report(uint32(len(hashes)+i), "failed to get header %s: %w", hash.StringLE(), err)
+			return hashes
+		}
+		hash = b.PrevHash
+	}
+	return append(hashes, tail...)
+}
+
+// checkBlocks checks header chain continuity, block hashes and transactions
+// referenced by blocks.
+func (c *dbChecker) checkBlocks(ctx context.Context, hashes []util.Uint256, blockHeight uint32) error {
+	for i, h := range hashes {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		index := uint32(i)
+		if index%checkLogInterval == 0 && index != 0 {
+			c.log.Info("blocks checked", zap.Uint32("height", index))
+		}
+		b, err := c.dao.GetBlock(h)
+		if err != nil {
+			c.report(index, "failed to get block %s: %w", h.StringLE(), err)
+			continue
+		}
+		if !b.Hash().Equals(h) {
+			c.report(index, "block hash mismatch: stored %s, computed %s", h.StringLE(), b.Hash().StringLE())
+		}
+		if b.Index != index {
+			c.report(index, "block index mismatch: got %d", b.Index)
+		}
+		if index > 0 && !b.PrevHash.Equals(hashes[i-1]) {
+			c.report(index, "previous block hash mismatch: expected %s, got %s", hashes[i-1].StringLE(), b.PrevHash.StringLE())
+		}
+		if index <= blockHeight {
+			c.checkBlockTransactions(b)
+		}
+	}
+	return nil
+}
+
+// checkBlockTransactions checks the merkle root of the block and the
+// presence of its transactions.
+func (c *dbChecker) checkBlockTransactions(b *block.Block) {
+	if len(b.Transactions) == 0 && !b.MerkleRoot.Equals(util.Uint256{}) {
+		// Transactions of untraceable blocks are removed.
+		if !c.cfg.RemoveUntraceableBlocks {
+			c.report(b.Index, "block transactions are missing")
+		}
+		return
+	}
+	if root := b.ComputeMerkleRoot(); !root.Equals(b.MerkleRoot) {
+		c.report(b.Index, "merkle root mismatch: expected %s, computed %s", b.MerkleRoot.StringLE(), root.StringLE())
+	}
+	for _, t := range b.Transactions {
+		tx, height, err := c.dao.GetTransaction(t.Hash())
+		if err != nil {
+			c.report(b.Index, "failed to get transaction %s: %w", t.Hash().StringLE(), err)
+			continue
+		}
+		if !tx.Hash().Equals(t.Hash()) {
+			c.report(b.Index, "transaction hash mismatch: stored %s, computed %s", t.Hash().StringLE(), tx.Hash().StringLE())
+		}
+		if height != b.Index {
+			c.report(b.Index, "transaction %s is stored with height %d", t.Hash().StringLE(), height)
+		}
+	}
+}
+
+// checkTransactions walks over all stored transactions and checks their
+// hashes and heights.
+func (c *dbChecker) checkTransactions(ctx context.Context, blockHeight uint32) error {
+	var err error
+	c.store.Seek(storage.DataTransaction.Bytes(), func(k, v []byte) {
+		if err != nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		default:
+		}
+		if len(k) != util.Uint256Size+1 || len(v) < 5 {
+			c.report(blockHeight, "invalid transaction entry %x", k)
+			return
+		}
+		r := io.NewBinReaderFromBuf(v)
+		height := r.ReadU32LE()
+		if v[4] == transaction.DummyVersion {
+			// Conflict record, there is no transaction.
+			return
+		}
+		tx := &transaction.Transaction{}
+		tx.DecodeBinary(r)
+		if r.Err != nil {
+			c.report(height, "failed to decode transaction %x: %w", k[1:], r.Err)
+			return
+		}
+		if expected, _ := util.Uint256DecodeBytesBE(k[1:]); !tx.Hash().Equals(expected) {
+			c.report(height, "transaction hash mismatch: stored %s, computed %s", expected.StringLE(), tx.Hash().StringLE())
+		}
+		if height > blockHeight {
+			c.report(height, "transaction %s is above the current block height %d", tx.Hash().StringLE(), blockHeight)
+		}
+	})
+	return err
+}
+
+// checkStateRoot walks the stored MPT starting from the local state root of
+// the given height. It checks that every node can be resolved and matches its
+// hash and that the set of trie leaves matches contract storage items.
+func (c *dbChecker) checkStateRoot(ctx context.Context, height uint32) error {
+	sr, err := stateroot.GetLocalStateRoot(c.store, height)
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			c.log.Info("no state root stored, skipping MPT check", zap.Uint32("height", height))
+			return nil
+		}
+		c.report(height, "failed to get state root: %w", err)
+		return nil
+	}
+	if sr.Index != height {
+		c.report(height, "state root index mismatch: got %d", sr.Index)
+	}
+
+	var leaves int
+	if !sr.Root.Equals(util.Uint256{}) {
+		var ctxErr error
+		b := mpt.NewBillet(sr.Root, c.cfg.KeepOnlyLatestState, storage.NewMemCachedStore(c.store))
+		err = b.Traverse(func(path []byte, n mpt.Node, nodeBytes []byte) bool {
+			select {
+			case <-ctx.Done():
+				ctxErr = ctx.Err()
+				return true
+			default:
+			}
+			if h := hash.DoubleSha256(nodeBytes); !h.Equals(n.Hash()) {
+				c.report(height, "MPT node %s has invalid contents (hash %s)", n.Hash().StringLE(), h.StringLE())
+			}
+			if _, ok := n.(*mpt.LeafNode); !ok {
+				return false
+			}
+			leaves++
+			v, err := c.store.Get(append([]byte{byte(storage.STStorage)}, path...))
+			if err != nil {
+				c.report(height, "contract storage item %x is missing: %w", path, err)
+			} else if !mpt.NewLeafNode(v).Hash().Equals(n.Hash()) {
+				c.report(height, "contract storage item %x doesn't match MPT leaf %s", path, n.Hash().StringLE())
+			}
+			return false
+		}, false)
+		if ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			c.report(height, "failed to traverse MPT from state root %s: %w", sr.Root.StringLE(), err)
+			return nil
+		}
+	}
+
+	var items int
+	c.store.Seek(storage.STStorage.Bytes(), func(_, _ []byte) {
+		items++
+	})
+	if items != leaves {
+		c.report(height, "contract storage has %d items, MPT has %d leaves", items, leaves)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/util/slice"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCheckStore(t *testing.T) {
+	cfg, err := config.Load(path.Join(serverTestWD, "../../config"), netmode.PrivNet)
+	require.NoError(t, err)
+	cfg.ApplicationConfiguration.DBConfiguration = storage.DBConfiguration{
+		Type: "leveldb",
+		LevelDBOptions: storage.LevelDBOptions{
+			DataDirectoryPath: t.TempDir(),
+		},
+	}
+	chain, err := initBlockChain(cfg, zap.NewNop())
+	require.NoError(t, err)
+	go chain.Run()
+	chain.Close()
+
+	store, err := storage.NewStore(cfg.ApplicationConfiguration.DBConfiguration)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, store.Close()) })
+
+	check := func(t *testing.T, expected int) []dbIssue {
+		issues, err := checkStore(context.Background(), store, cfg.ProtocolConfiguration, true, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, expected, len(issues), issues)
+		return issues
+	}
+
+	t.Run("good", func(t *testing.T) {
+		check(t, 0)
+	})
+	t.Run("bad transaction", func(t *testing.T) {
+		key := storage.AppendPrefix(storage.DataTransaction, make([]byte, 32))
+		require.NoError(t, store.Put(key, []byte{5, 0, 0, 0, 1, 2, 3}))
+		issues := check(t, 1)
+		require.Equal(t, uint32(5), issues[0].Height)
+		require.NoError(t, store.Delete(key))
+		check(t, 0)
+	})
+	t.Run("missing MPT node", func(t *testing.T) {
+		var value []byte
+		store.Seek(storage.STStorage.Bytes(), func(k, v []byte) {
+			if value == nil {
+				value = slice.Copy(v)
+			}
+		})
+		require.NotNil(t, value)
+		key := append([]byte{byte(storage.DataMPT)}, mpt.NewLeafNode(value).Hash().BytesBE()...)
+		node, err := store.Get(key)
+		require.NoError(t, err)
+		require.NoError(t, store.Delete(key))
+		issues := check(t, 1)
+		require.Equal(t, uint32(0), issues[0].Height)
+		require.NoError(t, store.Put(key, node))
+		check(t, 0)
+	})
+	t.Run("bad state", func(t *testing.T) {
+		var key, value []byte
+		store.Seek(storage.STStorage.Bytes(), func(k, v []byte) {
+			if key == nil {
+				key, value = slice.Copy(k), slice.Copy(v)
+			}
+		})
+		require.NotNil(t, key)
+		require.NoError(t, store.Put(key, append(value, 1)))
+		issues := check(t, 1)
+		require.Equal(t, uint32(0), issues[0].Height)
+
+		issues, err := checkStore(context.Background(), store, cfg.ProtocolConfiguration, false, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, 0, len(issues))
+	})
+	t.Run("missing header", func(t *testing.T) {
+		require.NoError(t, store.Delete(storage.SYSCurrentHeader.Bytes()))
+		_, err := checkStore(context.Background(), store, cfg.ProtocolConfiguration, true, zap.NewNop())
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/cli/options"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/util/slice"
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+)
+
+// defaultConvertBatchSize is the default number of key-value pairs flushed
+// to the target DB at once during conversion.
+const defaultConvertBatchSize = 10000
+
+// convertDB copies all key-value pairs from the DB specified by the node
+// configuration to the DB specified by the target configuration.
+func convertDB(ctx *cli.Context) error {
+	cfg, err := getConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	outPath := ctx.String("out-config-path")
+	if outPath == "" {
+		return cli.NewExitError(errors.New("target configuration path is not specified"), 1)
+	}
+	outCfg, err := config.Load(outPath, options.GetNetwork(ctx))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to load target configuration: %w", err), 1)
+	}
+	log, err := handleLoggingParams(ctx, cfg.ApplicationConfiguration)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	batchSize := ctx.Int("batch-size")
+	if batchSize <= 0 {
+		batchSize = defaultConvertBatchSize
+	}
+
+	from, err := storage.NewStore(cfg.ApplicationConfiguration.DBConfiguration)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to open source DB: %w", err), 1)
+	}
+	defer from.Close()
+	to, err := storage.NewStore(outCfg.ApplicationConfiguration.DBConfiguration)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to open target DB: %w", err), 1)
+	}
+	defer to.Close()
+
+	err = convertStore(newGraceContext(), from, to, ctx.String("checkpoint"), batchSize, log)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
+// convertStore streams all key-value pairs from one Store to another one
+// prefix by prefix and then checks that the number of keys for every prefix
+// matches. If checkpoint file name is given, the last flushed key is saved
+// there, so that interrupted conversion can be resumed from it, the file is
+// removed after successful conversion.
+func convertStore(ctx context.Context, from, to storage.Store, checkpoint string, batchSize int, log *zap.Logger) error {
+	var last []byte
+	if checkpoint != "" {
+		data, err := ioutil.ReadFile(checkpoint)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to read checkpoint: %w", err)
+		}
+		if err == nil {
+			last, err = hex.DecodeString(strings.TrimSpace(string(data)))
+			if err != nil || len(last) == 0 {
+				return fmt.Errorf("invalid checkpoint %s", checkpoint)
+			}
+			log.Info("resuming conversion", zap.String("key", hex.EncodeToString(last)))
+		}
+	}
+	if last == nil && countKeys(to).total() != 0 {
+		return errors.New("target DB is not empty")
+	}
+
+	var (
+		err   error
+		total int
+		puts  = make(map[string][]byte, batchSize)
+	)
+	flush := func() error {
+		if len(puts) == 0 {
+			return nil
+		}
+		if err := to.PutChangeSet(puts, nil); err != nil {
+			return fmt.Errorf("failed to write to target DB: %w", err)
+		}
+		total += len(puts)
+		if checkpoint != "" {
+			if err := writeCheckpoint(checkpoint, last); err != nil {
+				return err
+			}
+		}
+		log.Info("keys copied", zap.Int("total", total), zap.String("last", hex.EncodeToString(last)))
+		puts = make(map[string][]byte, batchSize)
+		return nil
+	}
+	start := 0
+	if last != nil {
+		start = int(last[0])
+	}
+	for p := start; p <= 0xff && err == nil; p++ {
+		var (
+			prefix = []byte{byte(p)}
+			resume []byte
+		)
+		if last != nil && p == start {
+			resume = last[1:]
+		}
+		storage.SeekRange(from, prefix, resume, func(k, v []byte) bool {
+			// The checkpoint key itself is already copied.
+			if resume != nil && bytes.Equal(k, last) {
+				return true
+			}
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+				return false
+			default:
+			}
+			puts[string(k)] = slice.Copy(v)
+			last = slice.Copy(k)
+			if len(puts) >= batchSize {
+				err = flush()
+			}
+			return err == nil
+		})
+	}
+	// Everything before the interruption is still written, so that it can be
+	// resumed from the checkpoint.
+	if ferr := flush(); err == nil {
+		err = ferr
+	}
+	if err != nil {
+		return err
+	}
+
+	fromCount, toCount := countKeys(from), countKeys(to)
+	for p := range fromCount {
+		if fromCount[p] != toCount[p] {
+			return fmt.Errorf("key count mismatch for prefix 0x%02x: %d in source, %d in target", p, fromCount[p], toCount[p])
+		}
+		if fromCount[p] != 0 {
+			log.Info("prefix verified", zap.String("prefix", fmt.Sprintf("0x%02x", p)), zap.Int("keys", fromCount[p]))
+		}
+	}
+	if checkpoint != "" {
+		if err := os.Remove(checkpoint); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove checkpoint: %w", err)
+		}
+	}
+	log.Info("conversion completed", zap.Int("keys", fromCount.total()))
+	return nil
+}
+
+// prefixCounts holds the number of keys for every KeyPrefix.
+type prefixCounts [256]int
+
+func (c *prefixCounts) total() int {
+	var n int
+	for i := range c {
+		n += c[i]
+	}
+	return n
+}
+
+// countKeys returns the number of keys for every KeyPrefix in the Store.
+func countKeys(s storage.Store) *prefixCounts {
+	var res prefixCounts
+	for p := 0; p <= 0xff; p++ {
+		prefix := []byte{byte(p)}
+		s.Seek(prefix, func(k, _ []byte) {
+			if len(k) != 0 && k[0] == prefix[0] {
+				res[p]++
+			}
+		})
+	}
+	return &res
+}
+
+// writeCheckpoint atomically saves the key to the checkpoint file.
+func writeCheckpoint(name string, key []byte) error {
+	tmp := name + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(hex.EncodeToString(key)), 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newConvertSource(t *testing.T) storage.Store {
+	s := storage.NewMemoryStore()
+	for _, p := range []storage.KeyPrefix{storage.DataBlock, storage.STStorage, storage.SYSCurrentBlock} {
+		for i := 0; i < 10; i++ {
+			require.NoError(t, s.Put(storage.AppendPrefixInt(p, i), []byte{byte(i)}))
+		}
+	}
+	require.NoError(t, s.Put(storage.SYSCurrentBlock.Bytes(), []byte{1, 2, 3}))
+	return s
+}
+
+func requireStoresEqual(t *testing.T, expected, actual storage.Store) {
+	for p := 0; p <= 0xff; p++ {
+		expected.Seek([]byte{byte(p)}, func(k, v []byte) {
+			actualV, err := actual.Get(k)
+			require.NoError(t, err)
+			require.Equal(t, v, actualV)
+		})
+	}
+	require.Equal(t, countKeys(expected), countKeys(actual))
+}
+
+// seekCounter counts the items visited by SeekRange.
+type seekCounter struct {
+	*storage.MemoryStore
+	visited int
+}
+
+func (s *seekCounter) SeekRange(prefix, start []byte, f func(k, v []byte) bool) {
+	s.MemoryStore.SeekRange(prefix, start, func(k, v []byte) bool {
+		s.visited++
+		return f(k, v)
+	})
+}
+
+func TestConvertStore(t *testing.T) {
+	log := zap.NewNop()
+
+	t.Run("good", func(t *testing.T) {
+		from, to := newConvertSource(t), storage.NewMemoryStore()
+		checkpoint := filepath.Join(t.TempDir(), "checkpoint")
+		require.NoError(t, convertStore(context.Background(), from, to, checkpoint, 3, log))
+		requireStoresEqual(t, from, to)
+		_, err := os.Stat(checkpoint)
+		require.True(t, os.IsNotExist(err))
+	})
+	t.Run("not empty target", func(t *testing.T) {
+		from, to := newConvertSource(t), storage.NewMemoryStore()
+		require.NoError(t, to.Put([]byte{1}, []byte{2}))
+		require.Error(t, convertStore(context.Background(), from, to, "", 3, log))
+	})
+	t.Run("bad checkpoint", func(t *testing.T) {
+		from, to := newConvertSource(t), storage.NewMemoryStore()
+		checkpoint := filepath.Join(t.TempDir(), "checkpoint")
+		require.NoError(t, ioutil.WriteFile(checkpoint, []byte("not a hex"), 0644))
+		require.Error(t, convertStore(context.Background(), from, to, checkpoint, 3, log))
+	})
+	t.Run("resume", func(t *testing.T) {
+		from, to := newConvertSource(t), storage.NewMemoryStore()
+		checkpoint := filepath.Join(t.TempDir(), "checkpoint")
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.ErrorIs(t, convertStore(ctx, from, to, checkpoint, 3, log), context.Canceled)
+
+		// Partially copy the data and save the progress.
+		key := storage.AppendPrefixInt(storage.STStorage, 4)
+		for p := 0; p <= int(storage.STStorage); p++ {
+			from.Seek([]byte{byte(p)}, func(k, v []byte) {
+				if string(k) <= string(key) {
+					require.NoError(t, to.Put(k, v))
+				}
+			})
+		}
+		require.NoError(t, writeCheckpoint(checkpoint, key))
+
+		require.NoError(t, convertStore(context.Background(), from, to, checkpoint, 3, log))
+		_, err := os.Stat(checkpoint)
+		require.True(t, os.IsNotExist(err))
+		require.Equal(t, countKeys(from), countKeys(to))
+	})
+	t.Run("seek range", func(t *testing.T) {
+		src := newConvertSource(t).(*storage.MemoryStore)
+		from, to := &seekCounter{MemoryStore: src}, storage.NewMemoryStore()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.ErrorIs(t, convertStore(ctx, from, to, "", 3, log), context.Canceled)
+		require.Equal(t, 1, from.visited)
+
+		// Resuming starts right from the checkpoint key (the result doesn't
+		// pass the check, the target lacks keys before it).
+		from.visited = 0
+		checkpoint := filepath.Join(t.TempDir(), "checkpoint")
+		require.NoError(t, writeCheckpoint(checkpoint, storage.AppendPrefixInt(storage.STStorage, 4)))
+		require.Error(t, convertStore(context.Background(), from, to, checkpoint, 3, log))
+		require.Equal(t, 6+11, from.visited)
+	})
+	t.Run("count mismatch", func(t *testing.T) {
+		from, to := newConvertSource(t), storage.NewMemoryStore()
+		checkpoint := filepath.Join(t.TempDir(), "checkpoint")
+		// Resuming after the last key doesn't copy anything.
+		require.NoError(t, writeCheckpoint(checkpoint, []byte{0xff, 0xff}))
+		require.Error(t, convertStore(context.Background(), from, to, checkpoint, 3, log))
+	})
+}
@@ -65,6 +65,51 @@ func NewCommands() []cli.Command {
 			Usage: "use if dump is incremental",
 		},
 	)
+	var cfgConvertFlags = make([]cli.Flag, len(cfgFlags))
+	copy(cfgConvertFlags, cfgFlags)
+	cfgConvertFlags = append(cfgConvertFlags,
+		cli.StringFlag{
+			Name:  "out-config-path",
+			Usage: "path to the directory with configuration of the target DB",
+		},
+		cli.StringFlag{
+			Name:  "checkpoint",
+			Usage: "file to store conversion progress in to be able to resume it",
+		},
+		cli.IntFlag{
+			Name:  "batch-size",
+			Usage: "number of keys written to the target DB at once",
+			Value: defaultConvertBatchSize,
+		},
+	)
+	var cfgCheckFlags = make([]cli.Flag, len(cfgFlags))
+	copy(cfgCheckFlags, cfgFlags)
+	cfgCheckFlags = append(cfgCheckFlags,
+		cli.BoolFlag{
+			Name:  "skip-mpt",
+			Usage: "don't check MPT nodes against contract storage items",
+		},
+	)
+	var cfgSnapshotExportFlags = make([]cli.Flag, len(cfgFlags))
+	copy(cfgSnapshotExportFlags, cfgFlags)
+	cfgSnapshotExportFlags = append(cfgSnapshotExportFlags,
+		cli.UintFlag{
+			Name:  "height",
+			Usage: "state sync point to export snapshot at (default: the latest one)",
+		},
+		cli.StringFlag{
+			Name:  "out, o",
+			Usage: "Output file",
+		},
+	)
+	var cfgSnapshotImportFlags = make([]cli.Flag, len(cfgFlags))
+	copy(cfgSnapshotImportFlags, cfgFlags)
+	cfgSnapshotImportFlags = append(cfgSnapshotImportFlags,
+		cli.StringFlag{
+			Name:  "in, i",
+			Usage: "Input file",
+		},
+	)
 	return []cli.Command{
 		{
 			Name:   "node",
@@ -88,6 +133,36 @@ func NewCommands() []cli.Command {
 					Action: restoreDB,
 					Flags:  cfgCountInFlags,
 				},
+				{
+					Name:   "convert",
+					Usage:  "copy all data from the configured DB to another DB",
+					Action: convertDB,
+					Flags:  cfgConvertFlags,
+				},
+				{
+					Name:   "check",
+					Usage:  "check the integrity of the DB",
+					Action: checkDB,
+					Flags:  cfgCheckFlags,
+				},
+				{
+					Name:  "snapshot",
+					Usage: "state snapshot export/import",
+					Subcommands: []cli.Command{
+						{
+							Name:   "export",
+							Usage:  "export headers, blocks and MPT state at the state sync point to the file",
+							Action: exportSnapshot,
+							Flags:  cfgSnapshotExportFlags,
+						},
+						{
+							Name:   "import",
+							Usage:  "bootstrap a fresh node from the snapshot file",
+							Action: importSnapshot,
+							Flags:  cfgSnapshotImportFlags,
+						},
+					},
+				},
 			},
 		},
 	}
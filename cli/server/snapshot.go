@@ -0,0 +1,95 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/chaindump"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+)
+
+// exportSnapshot writes state snapshot for the given (or the latest available)
+// state sync point to the file.
+func exportSnapshot(ctx *cli.Context) error {
+	cfg, err := getConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	log, err := handleLoggingParams(ctx, cfg.ApplicationConfiguration)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	out := ctx.String("out")
+	if out == "" {
+		return cli.NewExitError(errors.New("output file is not specified"), 1)
+	}
+
+	chain, err := initBlockChain(cfg, log)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer chain.Close()
+
+	height := uint32(ctx.Uint("height"))
+	if !ctx.IsSet("height") {
+		interval := uint32(chain.GetConfig().StateSyncInterval)
+		if interval == 0 {
+			return cli.NewExitError(errors.New("StateSyncInterval is not set, specify snapshot height explicitly"), 1)
+		}
+		if chain.HeaderHeight() == 0 {
+			return cli.NewExitError(errors.New("chain is too low to export snapshot"), 1)
+		}
+		height = (chain.HeaderHeight() - 1) / interval * interval
+	}
+
+	outStream, err := os.Create(out)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer outStream.Close()
+	writer := io.NewBinWriterFromIO(outStream)
+
+	log.Info("exporting snapshot", zap.Uint32("height", height))
+	if err := chaindump.DumpSnapshot(chain, writer, height); err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to export snapshot: %w", err), 1)
+	}
+	log.Info("snapshot exported", zap.Uint32("height", height), zap.String("file", out))
+	return nil
+}
+
+// importSnapshot bootstraps a fresh node from the state snapshot file.
+func importSnapshot(ctx *cli.Context) error {
+	cfg, err := getConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	log, err := handleLoggingParams(ctx, cfg.ApplicationConfiguration)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	in := ctx.String("in")
+	if in == "" {
+		return cli.NewExitError(errors.New("input file is not specified"), 1)
+	}
+	inStream, err := os.Open(in)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer inStream.Close()
+	reader := io.NewBinReaderFromIO(inStream)
+
+	chain, err := initBlockChain(cfg, log)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer chain.Close()
+
+	if err := chaindump.RestoreSnapshot(chain, reader); err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to import snapshot: %w", err), 1)
+	}
+	log.Info("snapshot imported", zap.Uint32("height", chain.BlockHeight()))
+	return nil
+}
@@ -0,0 +1,98 @@
+package smartcontract
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/cli/flags"
+	"github.com/nspcc-dev/neo-go/cli/options"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/urfave/cli"
+)
+
+var diffCmd = cli.Command{
+	Name:      "diff",
+	Usage:     "check whether contract update is backwards-compatible",
+	UsageText: "neo-go contract diff {--old-manifest <file> | --old-hash <hash> -r <endpoint>} --new-manifest <file>",
+	Description: `Compares manifest of the deployed contract with the one it's going to be
+   updated with and prints all differences found. Old manifest is either read
+   from the file or fetched from the RPC node by contract hash (or address).
+
+   Changes that can break other contracts or clients using the contract are
+   marked as breaking, these are:
+    * contract name change (update is not possible in this case)
+    * removed methods and events
+    * changed types of method or event parameters and method return types
+    * methods no longer marked as safe
+    * removed groups and supported standards
+
+   Other changes (added methods, renamed parameters, changed permissions
+   and trusts) are printed too, but they don't affect the result. The command
+   exits with non-zero code if any breaking changes are found.
+
+   Storage layout comparison is not supported: contract storage keys and
+   values are not described by the manifest or debug info (static variables
+   listed there are not persisted), so storage compatibility has to be
+   checked manually.
+`,
+	Action: contractDiff,
+	Flags: append([]cli.Flag{
+		cli.StringFlag{
+			Name:  "old-manifest",
+			Usage: "Manifest of the deployed contract",
+		},
+		cli.StringFlag{
+			Name:  "old-hash",
+			Usage: "Hash or address of the deployed contract to get manifest from RPC node",
+		},
+		cli.StringFlag{
+			Name:  "new-manifest",
+			Usage: "Manifest the contract is going to be updated with",
+		},
+	}, options.RPC...),
+}
+
+func contractDiff(ctx *cli.Context) error {
+	oldFile, oldHash := ctx.String("old-manifest"), ctx.String("old-hash")
+	if (len(oldFile) == 0) == (len(oldHash) == 0) {
+		return cli.NewExitError(errors.New("exactly one of --old-manifest and --old-hash should be specified"), 1)
+	}
+	upd, _, err := readManifest(ctx.String("new-manifest"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't read new manifest: %w", err), 1)
+	}
+
+	var old *manifest.Manifest
+	if len(oldFile) != 0 {
+		old, _, err = readManifest(oldFile)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("can't read old manifest: %w", err), 1)
+		}
+	} else {
+		h, err := flags.ParseAddress(oldHash)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("invalid contract hash: %w", err), 1)
+		}
+		gctx, cancel := options.GetTimeoutContext(ctx)
+		defer cancel()
+
+		c, exitErr := options.GetRPCClient(gctx, ctx)
+		if exitErr != nil {
+			return exitErr
+		}
+		cs, err := c.GetContractStateByHash(h)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("can't get contract state: %w", err), 1)
+		}
+		old = &cs.Manifest
+	}
+
+	changes := manifest.Diff(old, upd)
+	for _, c := range changes {
+		fmt.Fprintln(ctx.App.Writer, c)
+	}
+	if manifest.HasBreaking(changes) {
+		return cli.NewExitError(errors.New("breaking changes found"), 1)
+	}
+	return nil
+}
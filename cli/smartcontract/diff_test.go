@@ -0,0 +1,76 @@
+package smartcontract
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func TestContractDiff(t *testing.T) {
+	d := t.TempDir()
+	writeManifest := func(name string, m *manifest.Manifest) string {
+		bs, err := json.Marshal(m)
+		require.NoError(t, err)
+		name = filepath.Join(d, name)
+		require.NoError(t, ioutil.WriteFile(name, bs, 0644))
+		return name
+	}
+	old := manifest.DefaultManifest("Foo")
+	old.ABI.Methods = []manifest.Method{
+		{Name: "get", ReturnType: smartcontract.IntegerType, Safe: true},
+		{Name: "put", Parameters: []manifest.Parameter{manifest.NewParameter("value", smartcontract.IntegerType)}, ReturnType: smartcontract.VoidType},
+	}
+	oldFile := writeManifest("old.json", old)
+
+	compatible := manifest.DefaultManifest("Foo")
+	compatible.ABI.Methods = append([]manifest.Method{}, old.ABI.Methods...)
+	compatible.ABI.Methods = append(compatible.ABI.Methods, manifest.Method{Name: "reset", ReturnType: smartcontract.VoidType})
+	compatibleFile := writeManifest("compatible.json", compatible)
+
+	breaking := manifest.DefaultManifest("Foo")
+	breaking.ABI.Methods = old.ABI.Methods[:1]
+	breakingFile := writeManifest("breaking.json", breaking)
+
+	newContext := func(buf *bytes.Buffer, args ...string) *cli.Context {
+		set := flag.NewFlagSet("flagSet", flag.ContinueOnError)
+		set.String("old-manifest", "", "")
+		set.String("old-hash", "", "")
+		set.String("new-manifest", "", "")
+		require.NoError(t, set.Parse(args))
+		app := cli.NewApp()
+		app.Writer = buf
+		return cli.NewContext(app, set, nil)
+	}
+
+	t.Run("compatible", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.NoError(t, contractDiff(newContext(buf, "--old-manifest", oldFile, "--new-manifest", compatibleFile)))
+		require.Equal(t, "method reset/0 added\n", buf.String())
+	})
+	t.Run("breaking", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.Error(t, contractDiff(newContext(buf, "--old-manifest", oldFile, "--new-manifest", breakingFile)))
+		require.Equal(t, "breaking: method put/1 removed\n", buf.String())
+	})
+	t.Run("no old manifest", func(t *testing.T) {
+		require.Error(t, contractDiff(newContext(new(bytes.Buffer), "--new-manifest", breakingFile)))
+	})
+	t.Run("both old manifest and hash", func(t *testing.T) {
+		require.Error(t, contractDiff(newContext(new(bytes.Buffer), "--old-manifest", oldFile,
+			"--old-hash", "0x0102030405060708090a0b0c0d0e0f1011121314", "--new-manifest", breakingFile)))
+	})
+	t.Run("no new manifest", func(t *testing.T) {
+		require.Error(t, contractDiff(newContext(new(bytes.Buffer), "--old-manifest", oldFile)))
+	})
+	t.Run("invalid hash", func(t *testing.T) {
+		require.Error(t, contractDiff(newContext(new(bytes.Buffer), "--old-hash", "bad", "--new-manifest", breakingFile)))
+	})
+}
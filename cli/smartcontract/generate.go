@@ -0,0 +1,127 @@
+package smartcontract
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/cli/flags"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/binding"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/rpcbinding"
+	"github.com/urfave/cli"
+)
+
+var generatorFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "manifest, m",
+		Usage: "Read contract manifest (*.manifest.json) file",
+	},
+	cli.StringFlag{
+		Name:  "config, c",
+		Usage: "Configuration file (*.yml) to take type hints from",
+	},
+	cli.StringFlag{
+		Name:  "out, o",
+		Usage: "Output package directory, its name is used as a package name",
+	},
+	flags.AddressFlag{
+		Name:  "hash",
+		Usage: "Contract hash (LE) or address",
+	},
+}
+
+var generateWrapperCmd = cli.Command{
+	Name:      "generate-wrapper",
+	Usage:     "generate wrapper to use in other contracts",
+	UsageText: "neo-go contract generate-wrapper --manifest <file.json> --out <dir> --hash <hash> [--config <file.yml>]",
+	Description: `Generates Go package with typed wrappers for the contract methods to be
+   imported by other contracts. Every wrapper calls the contract with the given
+   hash via contract.Call, safe methods are called with ReadOnly flags and
+   other methods with All flags, so compiler is able to check calls made via
+   the wrapper against contract permissions. Parameter and return types are
+   taken from the manifest ABI and can be refined with 'typehints' section of
+   the contract configuration file (see generate-rpcwrapper command).
+`,
+	Action: contractGenerateWrapper,
+	Flags:  generatorFlags,
+}
+
+var generateRPCWrapperCmd = cli.Command{
+	Name:      "generate-rpcwrapper",
+	Usage:     "generate RPC wrapper to use for data reads",
+	UsageText: "neo-go contract generate-rpcwrapper --manifest <file.json> --out <dir> [--config <file.yml>] [--hash <hash>]",
+	Description: `Generates Go package with typed wrappers for the contract methods to be
+   used by RPC clients. Safe methods are performed as test invocations returning
+   Go values, other methods create, sign and send transactions invoking them.
+   Parameter and return types are taken from the manifest ABI, they can be
+   refined with 'typehints' section of the contract configuration file, like:
+
+     typehints:
+       symbol: String               # return type of 'symbol' method
+       setOwner.owner: PublicKey    # type of 'owner' parameter of 'setOwner'
+`,
+	Action: contractGenerateRPCWrapper,
+	Flags:  generatorFlags,
+}
+
+func contractGenerateWrapper(ctx *cli.Context) error {
+	if !ctx.Generic("hash").(*flags.Address).IsSet {
+		return cli.NewExitError("contract hash is not specified, use '--hash' flag", 1)
+	}
+	return contractGenerateSomething(ctx, "wrapper", binding.Generate)
+}
+
+func contractGenerateRPCWrapper(ctx *cli.Context) error {
+	return contractGenerateSomething(ctx, "rpcwrapper", rpcbinding.Generate)
+}
+
+// contractGenerateSomething reads generator parameters from the command line
+// and writes the code generated by cb into the output package directory.
+func contractGenerateSomething(ctx *cli.Context, fileName string, cb func(binding.Config) error) error {
+	m, _, err := readManifest(ctx.String("manifest"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't read contract manifest: %w", err), 1)
+	}
+	out := ctx.String("out")
+	if len(out) == 0 {
+		return cli.NewExitError("output package directory is not specified, use '--out' or '-o' flag", 1)
+	}
+	pkg := strings.ToLower(filepath.Base(out))
+	if !token.IsIdentifier(pkg) {
+		return cli.NewExitError(fmt.Errorf("invalid package name: %s", pkg), 1)
+	}
+	cfg := binding.Config{
+		Package:  pkg,
+		Manifest: m,
+	}
+	if h := ctx.Generic("hash").(*flags.Address); h.IsSet {
+		cfg.Hash = h.Uint160()
+	}
+	if confFile := ctx.String("config"); len(confFile) != 0 {
+		conf, err := ParseContractConfig(confFile)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("can't parse contract config: %w", err), 1)
+		}
+		cfg.TypeHints, err = binding.ParseTypeHints(conf.TypeHints)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	cfg.Output = buf
+	if err := cb(cfg); err != nil {
+		return cli.NewExitError(fmt.Errorf("error during generation: %w", err), 1)
+	}
+	if err := os.MkdirAll(out, os.ModePerm); err != nil {
+		return cli.NewExitError(fmt.Errorf("can't create output directory: %w", err), 1)
+	}
+	if err := ioutil.WriteFile(filepath.Join(out, fileName+".go"), buf.Bytes(), 0644); err != nil {
+		return cli.NewExitError(fmt.Errorf("can't write generated code: %w", err), 1)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package smartcontract
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/cli/flags"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func TestGenerateRPCWrapper(t *testing.T) {
+	d := t.TempDir()
+	m := manifest.NewManifest("Token")
+	m.ABI.Methods = []manifest.Method{{
+		Name:       "symbol",
+		ReturnType: smartcontract.ByteArrayType,
+		Safe:       true,
+	}}
+	rawM, err := json.Marshal(m)
+	require.NoError(t, err)
+	manifestFile := filepath.Join(d, "token.manifest.json")
+	require.NoError(t, ioutil.WriteFile(manifestFile, rawM, 0644))
+	confFile := filepath.Join(d, "token.yml")
+	require.NoError(t, ioutil.WriteFile(confFile, []byte("typehints:\n  symbol: String\n"), 0644))
+
+	newContext := func(out string) *cli.Context {
+		set := flag.NewFlagSet("flagSet", flag.ContinueOnError)
+		set.String("manifest", manifestFile, "")
+		set.String("config", confFile, "")
+		set.String("out", out, "")
+		flags.AddressFlag{Name: "hash"}.Apply(set)
+		require.NoError(t, set.Parse([]string{"--hash", "0x0102030000000000000000000000000000000000"}))
+		return cli.NewContext(cli.NewApp(), set, nil)
+	}
+
+	out := filepath.Join(d, "token")
+	require.NoError(t, contractGenerateRPCWrapper(newContext(out)))
+	src, err := ioutil.ReadFile(filepath.Join(out, "rpcwrapper.go"))
+	require.NoError(t, err)
+	require.True(t, strings.Contains(string(src), "package token\n"))
+	require.True(t, strings.Contains(string(src), "var Hash = util.Uint160{"))
+	require.True(t, strings.Contains(string(src), "func (c *Client) Symbol() (string, error) {"))
+
+	require.Error(t, contractGenerateRPCWrapper(newContext(filepath.Join(d, "bad-name"))))
+}
+
+func TestGenerateWrapper(t *testing.T) {
+	d := t.TempDir()
+	m := manifest.NewManifest("Token")
+	m.ABI.Methods = []manifest.Method{{
+		Name:       "symbol",
+		ReturnType: smartcontract.StringType,
+		Safe:       true,
+	}}
+	rawM, err := json.Marshal(m)
+	require.NoError(t, err)
+	manifestFile := filepath.Join(d, "token.manifest.json")
+	require.NoError(t, ioutil.WriteFile(manifestFile, rawM, 0644))
+
+	out := filepath.Join(d, "token")
+	newContext := func(args ...string) *cli.Context {
+		set := flag.NewFlagSet("flagSet", flag.ContinueOnError)
+		set.String("manifest", manifestFile, "")
+		set.String("config", "", "")
+		set.String("out", out, "")
+		flags.AddressFlag{Name: "hash"}.Apply(set)
+		require.NoError(t, set.Parse(args))
+		return cli.NewContext(cli.NewApp(), set, nil)
+	}
+
+	require.Error(t, contractGenerateWrapper(newContext()))
+
+	require.NoError(t, contractGenerateWrapper(newContext("--hash", "0x0000000000000000000000000000000000636261")))
+	src, err := ioutil.ReadFile(filepath.Join(out, "wrapper.go"))
+	require.NoError(t, err)
+	require.True(t, strings.Contains(string(src), "package token\n"))
+	require.True(t, strings.Contains(string(src), `const Hash = "\x61\x62\x63\x00`))
+	require.True(t, strings.Contains(string(src), "func Symbol() string {"))
+}
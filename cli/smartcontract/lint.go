@@ -0,0 +1,114 @@
+package smartcontract
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/urfave/cli"
+)
+
+var lintCmd = cli.Command{
+	Name:      "lint",
+	Usage:     "check contract source code for common problems",
+	UsageText: "neo-go contract lint -i <file.go|dir> [-c <config.yml>] [--json] [--skip <check>[,<check>...]]",
+	Description: `Loads contract the same way compile command does and checks its code
+   for contract-specific problems. Available checks are:
+
+     unchecked-witness         runtime.CheckWitness result is ignored
+     safe-storage-write        storage is modified in a method marked as safe
+                               in the configuration file
+     reentrancy                storage is modified after a call to other
+                               contract that is able to change the state
+     callflag-all              contract.Call is used with contract.All flags
+     unbounded-find            loop over storage.Find results can't be left
+                               before the iterator is exhausted
+     storage-prefix-collision  constant storage key prefix of some data is a
+                               prefix of the key used for other data
+
+   Checks are heuristic, so some reported issues may be false positives.
+   Found issues are printed one per line in the format used by Go tools or as
+   a JSON array if --json flag is given. The command exits with non-zero code
+   if any issues are found, so it can be used in CI.
+`,
+	Action: contractLint,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "in, i",
+			Usage: "Input file or directory with the smart contract",
+		},
+		cli.StringFlag{
+			Name:  "config, c",
+			Usage: "Configuration input file (*.yml) to get safe methods from",
+		},
+		cli.BoolFlag{
+			Name:  "json",
+			Usage: "Print issues in JSON format",
+		},
+		cli.StringFlag{
+			Name:  "skip",
+			Usage: "Comma-separated list of checks to skip",
+		},
+	},
+}
+
+func contractLint(ctx *cli.Context) error {
+	src := ctx.String("in")
+	if len(src) == 0 {
+		return cli.NewExitError(errNoInput, 1)
+	}
+	skip := make(map[string]bool)
+	if s := ctx.String("skip"); len(s) != 0 {
+		for _, check := range strings.Split(s, ",") {
+			check = strings.TrimSpace(check)
+			if !isLintCheck(check) {
+				return cli.NewExitError(fmt.Errorf("unknown check: %s", check), 1)
+			}
+			skip[check] = true
+		}
+	}
+	o := new(compiler.Options)
+	if confFile := ctx.String("config"); len(confFile) != 0 {
+		conf, err := ParseContractConfig(confFile)
+		if err != nil {
+			return err
+		}
+		o.SafeMethods = conf.SafeMethods
+	}
+
+	issues, err := compiler.Lint(src, nil, o)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	res := make([]compiler.LintIssue, 0, len(issues))
+	for _, issue := range issues {
+		if !skip[issue.Check] {
+			res = append(res, issue)
+		}
+	}
+	if ctx.Bool("json") {
+		bs, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		fmt.Fprintln(ctx.App.Writer, string(bs))
+	} else {
+		for _, issue := range res {
+			fmt.Fprintln(ctx.App.Writer, issue)
+		}
+	}
+	if len(res) != 0 {
+		return cli.NewExitError(fmt.Errorf("%d issue(s) found", len(res)), 1)
+	}
+	return nil
+}
+
+func isLintCheck(name string) bool {
+	for _, check := range compiler.LintChecks {
+		if check == name {
+			return true
+		}
+	}
+	return false
+}
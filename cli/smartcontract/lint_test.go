@@ -0,0 +1,87 @@
+package smartcontract
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func TestContractLint(t *testing.T) {
+	d := t.TempDir()
+	srcFile := filepath.Join(d, "foo.go")
+	src := `package foo
+	import (
+		"github.com/nspcc-dev/neo-go/pkg/interop"
+		"github.com/nspcc-dev/neo-go/pkg/interop/contract"
+		"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+		"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+	)
+	func Get(owner interop.Hash160) int {
+		runtime.CheckWitness(owner)
+		storage.Put(storage.GetContext(), "key", 1)
+		return 1
+	}
+	func Call(h interop.Hash160) {
+		contract.Call(h, "method", contract.All)
+	}`
+	require.NoError(t, ioutil.WriteFile(srcFile, []byte(src), 0644))
+	confFile := filepath.Join(d, "foo.yml")
+	require.NoError(t, ioutil.WriteFile(confFile, []byte("name: Foo\nsafemethods: [get]\n"), 0644))
+
+	newContext := func(buf *bytes.Buffer, args ...string) *cli.Context {
+		set := flag.NewFlagSet("flagSet", flag.ContinueOnError)
+		set.String("in", srcFile, "")
+		set.String("config", "", "")
+		set.Bool("json", false, "")
+		set.String("skip", "", "")
+		require.NoError(t, set.Parse(args))
+		app := cli.NewApp()
+		app.Writer = buf
+		return cli.NewContext(app, set, nil)
+	}
+
+	t.Run("text", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.Error(t, contractLint(newContext(buf, "--config", confFile)))
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Equal(t, 3, len(lines), buf.String())
+		require.True(t, strings.HasPrefix(lines[0], srcFile+":9:3: "), lines[0])
+		require.True(t, strings.HasSuffix(lines[0], "("+compiler.LintUncheckedWitness+")"), lines[0])
+		require.True(t, strings.HasSuffix(lines[1], "("+compiler.LintSafeStorageWrite+")"), lines[1])
+		require.True(t, strings.HasSuffix(lines[2], "("+compiler.LintCallFlagAll+")"), lines[2])
+	})
+	t.Run("json", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.Error(t, contractLint(newContext(buf, "--json")))
+		var issues []compiler.LintIssue
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &issues))
+		require.Equal(t, 2, len(issues))
+		require.Equal(t, compiler.LintIssue{
+			Check:   compiler.LintCallFlagAll,
+			File:    srcFile,
+			Line:    14,
+			Column:  3,
+			Message: issues[1].Message,
+		}, issues[1])
+	})
+	t.Run("skip", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.NoError(t, contractLint(newContext(buf, "--json",
+			"--skip", compiler.LintUncheckedWitness+", "+compiler.LintCallFlagAll)))
+		require.Equal(t, "[]\n", buf.String())
+	})
+	t.Run("unknown check", func(t *testing.T) {
+		require.Error(t, contractLint(newContext(new(bytes.Buffer), "--skip", "unknown")))
+	})
+	t.Run("no input", func(t *testing.T) {
+		require.Error(t, contractLint(newContext(new(bytes.Buffer), "--in", "")))
+	})
+}
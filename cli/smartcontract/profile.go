@@ -0,0 +1,184 @@
+package smartcontract
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/nspcc-dev/neo-go/cli/cmdargs"
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/core/fee"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/profile"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/urfave/cli"
+)
+
+var profileCmd = cli.Command{
+	Name:      "profile",
+	Usage:     "execute contract method and report GAS consumed by every line of code",
+	UsageText: "neo-go contract profile -i <file.nef> -m <file.json> -d <file.debug.json> [--cover <file>] <method> [<arg>...]",
+	Description: `Executes given method of the compiled contract with the given arguments
+   (see testinvokefunction command for their format) and prints the amount of
+   GAS consumed by every method and source line along with the number of
+   executions using the contract debug information. Optionally coverage
+   profile is written to the specified file in the format used by Go tooling,
+   so it can be inspected with 'go tool cover', like:
+
+     go tool cover -html=<file>
+
+   Contract is executed in a standalone VM, that only charges GAS for
+   instructions (with the default execution fee factor) and supports
+   System.Runtime.Log and System.Runtime.Notify syscalls only. Contracts
+   using other syscalls can be profiled in neotest-based tests (see
+   Executor.Profile).
+`,
+	Action: contractProfile,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "in, i",
+			Usage: "Input NEF file",
+		},
+		cli.StringFlag{
+			Name:  "manifest, m",
+			Usage: "Contract manifest (*.manifest.json) file",
+		},
+		cli.StringFlag{
+			Name:  "debug, d",
+			Usage: "Contract debug information (*.debug.json) file",
+		},
+		cli.StringFlag{
+			Name:  "cover",
+			Usage: "Output file for coverage profile",
+		},
+	},
+}
+
+func contractProfile(ctx *cli.Context) error {
+	args := ctx.Args()
+	if !args.Present() {
+		return cli.NewExitError(errNoMethod, 1)
+	}
+	nefFile, _, err := readNEFFile(ctx.String("in"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't read NEF file: %w", err), 1)
+	}
+	m, _, err := readManifest(ctx.String("manifest"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't read contract manifest: %w", err), 1)
+	}
+	di, err := readDebugInfo(ctx.String("debug"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't read debug info: %w", err), 1)
+	}
+	_, params, err := cmdargs.ParseParams(args[1:], true)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't parse arguments: %w", err), 1)
+	}
+	items := make([]stackitem.Item, len(params))
+	for i := range params {
+		items[i], err = paramToStackItem(params[i])
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("invalid argument #%d: %w", i, err), 1)
+		}
+	}
+	md := m.ABI.GetMethod(args[0], len(items))
+	if md == nil {
+		return cli.NewExitError(fmt.Errorf("method %s with %d parameters is not found", args[0], len(items)), 1)
+	}
+
+	v := vm.New()
+	v.GasLimit = -1
+	v.SetPriceGetter(func(op opcode.Opcode, _ []byte) int64 {
+		return fee.Opcode(interop.DefaultBaseExecFee, op)
+	})
+	p := profile.New()
+	v.SetOnExecHook(p.Hook)
+	v.LoadScriptWithFlags(nefFile.Script, callflag.All)
+	scriptHash := v.Context().ScriptHash()
+	for i := len(items) - 1; i >= 0; i-- {
+		v.Estack().PushVal(items[i])
+	}
+	v.Jump(v.Context(), md.Offset)
+	if initMD := m.ABI.GetMethod(manifest.MethodInit, 0); initMD != nil {
+		v.Call(v.Context(), initMD.Offset)
+	}
+
+	runErr := v.Run()
+	if runErr != nil {
+		fmt.Fprintf(ctx.App.Writer, "VM state: %s\nException: %s\n\n", v.State(), runErr)
+	} else {
+		fmt.Fprintf(ctx.App.Writer, "VM state: %s\nResult stack: %s\n\n", v.State(), v.DumpEStack())
+	}
+	if err := p.Report(scriptHash, di).Print(ctx.App.Writer); err != nil {
+		return cli.NewExitError(fmt.Errorf("can't print report: %w", err), 1)
+	}
+	if out := ctx.String("cover"); out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("can't create coverage file: %w", err), 1)
+		}
+		err = p.WriteCoverProfile(f, scriptHash, di)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("can't write coverage profile: %w", err), 1)
+		}
+	}
+	if runErr != nil {
+		return cli.NewExitError("contract execution has failed", 1)
+	}
+	return nil
+}
+
+// readDebugInfo reads contract debug information from the given file.
+func readDebugInfo(filename string) (*compiler.DebugInfo, error) {
+	if len(filename) == 0 {
+		return nil, errors.New("no debug info file was provided, specify it with '--debug' or '-d' flag")
+	}
+	bs, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	di := new(compiler.DebugInfo)
+	if err := json.Unmarshal(bs, di); err != nil {
+		return nil, err
+	}
+	return di, nil
+}
+
+// paramToStackItem converts contract parameter into the stack item passed
+// to the contract method.
+func paramToStackItem(p smartcontract.Parameter) (stackitem.Item, error) {
+	val, err := smartcontract.ExpandParameterToEmitable(p)
+	if err != nil {
+		return nil, err
+	}
+	return emitableToStackItem(val), nil
+}
+
+func emitableToStackItem(val interface{}) stackitem.Item {
+	switch v := val.(type) {
+	case util.Uint160:
+		return stackitem.NewByteArray(v.BytesBE())
+	case util.Uint256:
+		return stackitem.NewByteArray(v.BytesBE())
+	case []interface{}:
+		arr := make([]stackitem.Item, len(v))
+		for i := range v {
+			arr[i] = emitableToStackItem(v[i])
+		}
+		return stackitem.NewArray(arr)
+	default:
+		return stackitem.Make(v)
+	}
+}
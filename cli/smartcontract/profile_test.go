@@ -0,0 +1,77 @@
+package smartcontract
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func TestContractProfile(t *testing.T) {
+	d := t.TempDir()
+	srcFile := filepath.Join(d, "foo.go")
+	src := `package foo
+	import "github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+	func Sum(n int) int {
+		s := 0
+		for i := 0; i < n; i++ {
+			s += i
+		}
+		runtime.Log("done")
+		return s
+	}`
+	require.NoError(t, ioutil.WriteFile(srcFile, []byte(src), 0644))
+	nefFile := filepath.Join(d, "foo.nef")
+	debugFile := filepath.Join(d, "foo.debug.json")
+	manifestFile := filepath.Join(d, "foo.manifest.json")
+	_, err := compiler.CompileAndSave(srcFile, &compiler.Options{
+		Outfile:      nefFile,
+		DebugInfo:    debugFile,
+		ManifestFile: manifestFile,
+		Name:         "Foo",
+	})
+	require.NoError(t, err)
+
+	coverFile := filepath.Join(d, "cover.out")
+	newContext := func(buf *bytes.Buffer, args ...string) *cli.Context {
+		set := flag.NewFlagSet("flagSet", flag.ContinueOnError)
+		set.String("in", nefFile, "")
+		set.String("manifest", manifestFile, "")
+		set.String("debug", debugFile, "")
+		set.String("cover", coverFile, "")
+		require.NoError(t, set.Parse(args))
+		app := cli.NewApp()
+		app.Writer = buf
+		return cli.NewContext(app, set, nil)
+	}
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, contractProfile(newContext(buf, "sum", "3")))
+	out := buf.String()
+	require.True(t, strings.Contains(out, "VM state: HALT"), out)
+	require.True(t, strings.Contains(out, "foo.Sum"), out)
+	require.True(t, strings.Contains(out, srcFile+":6"), out)
+
+	cover, err := ioutil.ReadFile(coverFile)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(cover), "mode: count\n"))
+	require.True(t, strings.Contains(string(cover), srcFile+":6.4,6.10 1 3\n"), string(cover))
+
+	t.Run("no method", func(t *testing.T) {
+		require.Error(t, contractProfile(newContext(new(bytes.Buffer))))
+	})
+	t.Run("unknown method", func(t *testing.T) {
+		require.Error(t, contractProfile(newContext(new(bytes.Buffer), "sum")))
+	})
+	t.Run("fault", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.Error(t, contractProfile(newContext(buf, "sum", "[", "]")))
+		require.True(t, strings.Contains(buf.String(), "VM state: FAULT"), buf.String())
+	})
+}
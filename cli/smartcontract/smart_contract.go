@@ -163,6 +163,10 @@ func NewCommands() []cli.Command {
 						Name:  "no-permissions",
 						Usage: "do not check if invoked contracts are allowed in manifest",
 					},
+					cli.BoolFlag{
+						Name:  "optimize",
+						Usage: "optimize resulting bytecode",
+					},
 				},
 			},
 			{
@@ -370,6 +374,12 @@ func NewCommands() []cli.Command {
 					},
 				},
 			},
+			generateWrapperCmd,
+			generateRPCWrapperCmd,
+			profileCmd,
+			lintCmd,
+			diffCmd,
+			verifySourceCmd,
 			{
 				Name:  "manifest",
 				Usage: "manifest-related commands",
@@ -482,6 +492,8 @@ func contractCompile(ctx *cli.Context) error {
 		NoStandardCheck:    ctx.Bool("no-standards"),
 		NoEventsCheck:      ctx.Bool("no-events"),
 		NoPermissionsCheck: ctx.Bool("no-permissions"),
+
+		Optimize: ctx.Bool("optimize"),
 	}
 
 	if len(confFile) != 0 {
@@ -742,6 +754,7 @@ type ProjectConfig struct {
 	Events             []manifest.Event
 	Permissions        []permission
 	Overloads          map[string]string `yaml:"overloads,omitempty"`
+	TypeHints          map[string]string `yaml:"typehints,omitempty"`
 }
 
 func inspect(ctx *cli.Context) error {
@@ -0,0 +1,68 @@
+package smartcontract
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/cli/flags"
+	"github.com/nspcc-dev/neo-go/cli/options"
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/urfave/cli"
+)
+
+var verifySourceCmd = cli.Command{
+	Name:      "verify-source",
+	Usage:     "check that deployed contract is compiled from the given sources",
+	UsageText: "neo-go contract verify-source --hash <hash> --in <path> -r <endpoint>",
+	Description: `Fetches NEF of the deployed contract from the RPC node, compiles contract
+   sources (file or directory) with the same options and checks that the
+   resulting script and NEF checksum match the deployed ones.
+
+   Compiler version and options affecting the bytecode are stored in the
+   'compiler' field of NEF file by 'contract compile', so the contract
+   should be compiled by neo-go of the same version as the one used for
+   verification. Source URL is taken from the deployed NEF file.
+`,
+	Action: verifySource,
+	Flags: append([]cli.Flag{
+		cli.StringFlag{
+			Name:  "hash",
+			Usage: "Hash or address of the deployed contract",
+		},
+		cli.StringFlag{
+			Name:  "in, i",
+			Usage: "Input file or directory with the contract sources",
+		},
+	}, options.RPC...),
+}
+
+func verifySource(ctx *cli.Context) error {
+	src := ctx.String("in")
+	if len(src) == 0 {
+		return cli.NewExitError(errNoInput, 1)
+	}
+	if len(ctx.String("hash")) == 0 {
+		return cli.NewExitError(errors.New("no contract hash was provided, specify one with '--hash'"), 1)
+	}
+	h, err := flags.ParseAddress(ctx.String("hash"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("invalid contract hash: %w", err), 1)
+	}
+	gctx, cancel := options.GetTimeoutContext(ctx)
+	defer cancel()
+
+	c, exitErr := options.GetRPCClient(gctx, ctx)
+	if exitErr != nil {
+		return exitErr
+	}
+	cs, err := c.GetContractStateByHash(h)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't get contract state: %w", err), 1)
+	}
+	if err := compiler.VerifySource(src, &cs.NEF); err != nil {
+		return cli.NewExitError(fmt.Errorf("contract verification failed: %w", err), 1)
+	}
+	fmt.Fprintf(ctx.App.Writer, "Contract 0x%s matches the sources (compiler: %s, checksum: %d)\n",
+		h.StringLE(), cs.NEF.Compiler, cs.NEF.Checksum)
+	return nil
+}
@@ -1,29 +1,71 @@
 package vm
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/abiosoft/readline"
+	"github.com/nspcc-dev/neo-go/cli/options"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	vmcli "github.com/nspcc-dev/neo-go/pkg/vm/cli"
 	"github.com/urfave/cli"
+	"go.uber.org/zap"
 )
 
 // NewCommands returns 'vm' command.
 func NewCommands() []cli.Command {
+	var flags = []cli.Flag{
+		cli.BoolFlag{Name: "debug, d"},
+		cli.StringFlag{
+			Name:  "config-path",
+			Usage: "path to the node configuration directory to run scripts over the node's chain state",
+		},
+	}
+	flags = append(flags, options.Network...)
 	return []cli.Command{{
 		Name:   "vm",
 		Usage:  "start the virtual machine",
 		Action: startVMPrompt,
-		Flags: []cli.Flag{
-			cli.BoolFlag{Name: "debug, d"},
-		},
+		Flags:  flags,
 	}}
 }
 
 func startVMPrompt(ctx *cli.Context) error {
-	p := vmcli.NewWithConfig(true, os.Exit, &readline.Config{
+	rlCfg := &readline.Config{
 		Stdout: ctx.App.Writer,
 		Stderr: ctx.App.ErrWriter,
-	})
+	}
+	configPath := ctx.String("config-path")
+	if configPath == "" {
+		p := vmcli.NewWithConfig(true, os.Exit, rlCfg)
+		return p.Run()
+	}
+
+	cfg, err := config.Load(configPath, options.GetNetwork(ctx))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	store, err := storage.NewStore(cfg.ApplicationConfiguration.DBConfiguration)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("could not initialize storage: %w", err), 1)
+	}
+	defer store.Close()
+
+	// Nothing is ever persisted to the node's DB, all changes (including the
+	// ones made by the chain initialization) stay in memory.
+	chain, err := core.NewBlockchain(storage.NewMemCachedStore(store), cfg.ProtocolConfiguration, zap.NewNop())
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("could not initialize blockchain: %w", err), 1)
+	}
+	onExit := func(code int) {
+		_ = store.Close()
+		os.Exit(code)
+	}
+	p, err := vmcli.NewWithChain(true, onExit, rlCfg, chain)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
 	return p.Run()
 }
@@ -140,6 +140,11 @@ func (chain *FakeChain) GetNotaryBalance(acc util.Uint160) *big.Int {
 	panic("TODO")
 }
 
+// GetNotifications implements Blockchainer interface.
+func (chain *FakeChain) GetNotifications(contract util.Uint160, name string, start, end uint32, limit int) ([]state.IndexedNotificationEvent, error) {
+	panic("TODO")
+}
+
 // GetPolicer implements Blockchainer interface.
 func (chain *FakeChain) GetPolicer() blockchainer.Policer {
 	return chain
@@ -341,6 +346,11 @@ func (chain *FakeChain) GetTestVM(t trigger.Type, tx *transaction.Transaction, b
 	panic("TODO")
 }
 
+// GetTestHistoricVM implements Blockchainer interface.
+func (chain *FakeChain) GetTestHistoricVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) (*vm.VM, func(), error) {
+	panic("TODO")
+}
+
 // GetStorageItems implements Blockchainer interface.
 func (chain *FakeChain) GetStorageItems(id int32) ([]state.StorageItemWithKey, error) {
 	panic("TODO")
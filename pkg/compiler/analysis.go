@@ -9,12 +9,12 @@ import (
 
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
-	"golang.org/x/tools/go/loader" //nolint:staticcheck // SA1019: package golang.org/x/tools/go/loader is deprecated
+	"golang.org/x/tools/go/packages"
 )
 
 var (
 	// Go language builtin functions.
-	goBuiltins = []string{"len", "append", "panic", "make", "copy", "recover", "delete"}
+	goBuiltins = []string{"len", "append", "panic", "make", "copy", "recover", "delete", "new"}
 	// Custom builtin utility functions.
 	customBuiltins = []string{
 		"FromAddress",
@@ -75,18 +75,18 @@ func (c *codegen) traverseGlobals() bool {
 	emit.Instruction(c.prog.BinWriter, opcode.INITSLOT, []byte{0, 0})
 
 	lastCnt, maxCnt := -1, -1
-	c.ForEachPackage(func(pkg *loader.PackageInfo) {
+	c.ForEachPackage(func(pkg *packages.Package) {
 		if n+nConst > 0 {
-			for _, f := range pkg.Files {
-				c.fillImportMap(f, pkg.Pkg)
-				c.convertGlobals(f, pkg.Pkg)
+			for _, f := range pkg.Syntax {
+				c.fillImportMap(f, pkg.Types)
+				c.convertGlobals(f, pkg.Types)
 			}
 		}
-		for _, f := range pkg.Files {
-			c.fillImportMap(f, pkg.Pkg)
+		for _, f := range pkg.Syntax {
+			c.fillImportMap(f, pkg.Types)
 
 			var currMax int
-			lastCnt, currMax = c.convertInitFuncs(f, pkg.Pkg, lastCnt)
+			lastCnt, currMax = c.convertInitFuncs(f, pkg.Types, lastCnt)
 			if currMax > maxCnt {
 				maxCnt = currMax
 			}
@@ -214,8 +214,7 @@ func lastStmtIsReturn(body *ast.BlockStmt) (b bool) {
 //   that there can be no cyclic initialization dependencies.
 func (c *codegen) analyzePkgOrder() {
 	seen := make(map[string]bool)
-	info := c.buildInfo.program.Package(c.buildInfo.initialPackage)
-	c.visitPkg(info.Pkg, seen)
+	c.visitPkg(c.buildInfo.mainPkg.Types, seen)
 }
 
 func (c *codegen) visitPkg(pkg *types.Package, seen map[string]bool) {
@@ -231,7 +230,7 @@ func (c *codegen) visitPkg(pkg *types.Package, seen map[string]bool) {
 }
 
 func (c *codegen) fillDocumentInfo() {
-	fset := c.buildInfo.program.Fset
+	fset := c.buildInfo.fset
 	fset.Iterate(func(f *token.File) bool {
 		filePath := f.Position(f.Pos(0)).Filename
 		c.docIndex[filePath] = len(c.documents)
@@ -257,7 +256,7 @@ func (c *codegen) analyzeFuncUsage() funcUsage {
 	diff := funcUsage{}
 	c.ForEachFile(func(f *ast.File, pkg *types.Package) {
 		var pkgPath string
-		isMain := pkg == c.mainPkg.Pkg
+		isMain := pkg == c.mainPkg.Types
 		if !isMain {
 			pkgPath = pkg.Path()
 		}
@@ -277,6 +276,11 @@ func (c *codegen) analyzeFuncUsage() funcUsage {
 					return true
 				}
 				diff[name] = true
+			case *ast.Ident:
+				// functions used as values
+				if fn, ok := c.typeInfo.Uses[n].(*types.Func); ok {
+					diff[getFuncNameFromObj(fn)] = true
+				}
 			case *ast.FuncDecl:
 				name := c.getFuncNameFromDecl(pkgPath, n)
 
@@ -303,10 +307,10 @@ func (c *codegen) analyzeFuncUsage() funcUsage {
 
 			pkg := c.mainPkg
 			if fd.path != "" {
-				pkg = c.buildInfo.program.Package(fd.path)
+				pkg = c.buildInfo.program[fd.path]
 			}
-			c.typeInfo = &pkg.Info
-			c.currPkg = pkg.Pkg
+			c.typeInfo = pkg.TypesInfo
+			c.currPkg = pkg.Types
 			c.importMap = fd.importMap
 			ast.Inspect(fd.decl, func(node ast.Node) bool {
 				switch n := node.(type) {
@@ -318,6 +322,10 @@ func (c *codegen) analyzeFuncUsage() funcUsage {
 						name, _ := c.getFuncNameFromSelector(t)
 						nextDiff[name] = true
 					}
+				case *ast.Ident:
+					if fn, ok := c.typeInfo.Uses[n].(*types.Func); ok {
+						nextDiff[getFuncNameFromObj(fn)] = true
+					}
 				}
 				return true
 			})
@@ -327,6 +335,25 @@ func (c *codegen) analyzeFuncUsage() funcUsage {
 	return usage
 }
 
+// getFuncNameFromObj returns the name of function fn in the same format
+// getFuncNameFromDecl does.
+func getFuncNameFromObj(fn *types.Func) string {
+	name := fn.Name()
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		typ := recv.Type()
+		if t, ok := typ.(*types.Pointer); ok {
+			typ = t.Elem()
+		}
+		if t, ok := typ.(*types.Named); ok {
+			name = t.Obj().Name() + "." + name
+		}
+	}
+	if fn.Pkg() == nil {
+		return name
+	}
+	return fn.Pkg().Path() + "." + name
+}
+
 func isGoBuiltin(name string) bool {
 	for i := range goBuiltins {
 		if name == goBuiltins[i] {
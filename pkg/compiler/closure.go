@@ -0,0 +1,149 @@
+package compiler
+
+import (
+	"encoding/binary"
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// Closures are implemented via boxing of captured variables. Every local
+// variable (or argument) captured by some function literal is stored in a
+// one-element array (box) allocated when the variable is declared, so all
+// loads and stores of this variable go through the box (the same boxes are
+// used for variables having their address taken, see pointer.go). A function literal
+// capturing variables is then represented by an array containing pointer to
+// the function followed by boxes of captured variables. When it's invoked,
+// boxes are passed as additional (first) arguments, so the function body
+// accesses the same boxes as the outer function does. Function literals
+// without captured variables are represented by plain pointers.
+
+// analyzeClosures finds all function literals in the program capturing local
+// variables of the outer functions.
+func (c *codegen) analyzeClosures() {
+	c.ForEachFile(func(f *ast.File, _ *types.Package) {
+		ast.Inspect(f, func(node ast.Node) bool {
+			lit, ok := node.(*ast.FuncLit)
+			if !ok {
+				return true
+			}
+			var captured []*types.Var
+			seen := make(map[*types.Var]bool)
+			ast.Inspect(lit.Body, func(node ast.Node) bool {
+				id, ok := node.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				v, ok := c.typeInfo.Uses[id].(*types.Var)
+				if !ok || v.IsField() || v.Pkg() == nil || v.Parent() == nil ||
+					v.Parent() == v.Pkg().Scope() || seen[v] {
+					return true
+				}
+				if lit.Pos() <= v.Pos() && v.Pos() < lit.End() {
+					return true // Variable is defined inside the literal.
+				}
+				seen[v] = true
+				captured = append(captured, v)
+				c.boxedVars[v] = true
+				return true
+			})
+			if len(captured) != 0 {
+				c.closures[lit] = captured
+			}
+			return true
+		})
+	})
+}
+
+// isBoxed returns true if variable defined by id is stored in a box.
+func (c *codegen) isBoxed(id *ast.Ident) bool {
+	v, ok := c.typeInfo.Defs[id].(*types.Var)
+	return ok && c.boxedVars[v]
+}
+
+// emitNewBox allocates a new box for the variable with the specified name
+// declared in the current scope (or a global one if there is no scope). All
+// subsequent loads and stores of this variable are performed via the box.
+func (c *codegen) emitNewBox(name string) {
+	vi := c.getVarIndex("", name)
+	emit.Opcodes(c.prog.BinWriter, opcode.PUSH1, opcode.NEWARRAY)
+	c.emitStoreByIndex(vi.refType, vi.index)
+	if c.scope == nil {
+		c.boxedGlobals[c.getIdentName("", name)] = true
+	} else {
+		c.scope.vars.setBoxed(name)
+	}
+}
+
+// boxArguments moves boxed arguments of the current function into boxes
+// stored in new local variables.
+func (c *codegen) boxArguments(ids []*ast.Ident) {
+	for _, id := range ids {
+		if !c.isBoxed(id) {
+			continue
+		}
+		vi := c.scope.vars.getVarInfo(id.Name)
+		c.emitLoadByIndex(vi.refType, vi.index)
+		emit.Opcodes(c.prog.BinWriter, opcode.PUSH1, opcode.PACK)
+		c.emitStoreByIndex(varLocal, c.scope.newLocal(id.Name))
+		c.scope.vars.setBoxed(id.Name)
+	}
+}
+
+// emitClosure emits function value for the literal with the specified label.
+// Boxes of captured variables are taken from the current scope.
+func (c *codegen) emitClosure(l uint16, captured []*types.Var) {
+	for i := len(captured) - 1; i >= 0; i-- {
+		var vi *varInfo
+		if c.scope != nil {
+			vi = c.scope.vars.getVarInfo(captured[i].Name())
+		}
+		if vi == nil || !vi.boxed {
+			c.prog.Err = fmt.Errorf("can't capture variable %s", captured[i].Name())
+			return
+		}
+		c.emitLoadByIndex(vi.refType, vi.index)
+	}
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint16(buf, l)
+	emit.Instruction(c.prog.BinWriter, opcode.PUSHA, buf)
+	if len(captured) != 0 {
+		emit.Int(c.prog.BinWriter, int64(len(captured)+1))
+		emit.Opcodes(c.prog.BinWriter, opcode.PACK)
+	}
+}
+
+// emitFuncValue emits function value for the top-level function fn.
+func (c *codegen) emitFuncValue(fn *types.Func) {
+	f, ok := c.funcs[getFuncNameFromObj(fn)]
+	if !ok || isSyscall(f) || isCustomBuiltin(f) || canInline(f.pkg.Path(), f.name) {
+		c.prog.Err = fmt.Errorf("function %s can't be used as a value", fn.Name())
+		return
+	}
+	c.emitClosure(f.label, nil)
+}
+
+// emitCallFuncValue calls function value from the top of the stack which can
+// be either a pointer or a closure. If there are no closures in the program
+// all function values are pointers and can be called directly.
+func (c *codegen) emitCallFuncValue() {
+	if len(c.closures) != 0 {
+		after := c.newLabel()
+		emit.Opcodes(c.prog.BinWriter, opcode.DUP)
+		emit.Instruction(c.prog.BinWriter, opcode.ISTYPE, []byte{byte(stackitem.PointerT)})
+		emit.Jmp(c.prog.BinWriter, opcode.JMPIFL, after)
+		c.emitUnpackClosure()
+		c.setLabel(after)
+	}
+	emit.Opcodes(c.prog.BinWriter, opcode.CALLA)
+}
+
+// emitUnpackClosure replaces closure on top of the stack with boxes of
+// captured variables followed by the function pointer.
+func (c *codegen) emitUnpackClosure() {
+	emit.Opcodes(c.prog.BinWriter, opcode.UNPACK, opcode.DROP)
+}
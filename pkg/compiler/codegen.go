@@ -21,7 +21,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
-	"golang.org/x/tools/go/loader" //nolint:staticcheck // SA1019: package golang.org/x/tools/go/loader is deprecated
+	"golang.org/x/tools/go/packages"
 )
 
 type codegen struct {
@@ -34,13 +34,18 @@ type codegen struct {
 	// Type information.
 	typeInfo *types.Info
 	// pkgInfoInline is stack of type information for packages containing inline functions.
-	pkgInfoInline []*loader.PackageInfo
+	pkgInfoInline []*packages.Package
 
 	// A mapping of func identifiers with their scope.
 	funcs map[string]*funcScope
 
 	// A mapping of lambda functions into their scope.
 	lambda map[string]*funcScope
+	// A mapping of function literals into variables they capture.
+	closures map[*ast.FuncLit][]*types.Var
+	// A set of variables stored in boxes (captured by function literals or
+	// having their address taken).
+	boxedVars map[*types.Var]bool
 
 	// reverseOffsetMap maps function offsets to a local variable count.
 	reverseOffsetMap map[int]nameWithLocals
@@ -49,6 +54,8 @@ type codegen struct {
 	scope *funcScope
 
 	globals map[string]int
+	// A set of global variables stored in boxes.
+	boxedGlobals map[string]bool
 	// staticVariables contains global (static in NDX-DN11) variable names and types.
 	staticVariables []string
 	// initVariables contains variables local to `_initialize` method.
@@ -83,6 +90,9 @@ type codegen struct {
 	initEndOffset int
 	// deployEndOffset specifies the end of the deployment method.
 	deployEndOffset int
+	// singleInstrFuncs contains functions reduced to a single instruction by
+	// the optimizer, their ranges are empty, but they still need to be emitted.
+	singleInstrFuncs map[*funcScope]bool
 
 	// importMap contains mapping from package aliases to full package names for the current file.
 	importMap map[string]string
@@ -94,7 +104,7 @@ type codegen struct {
 	currPkg *types.Package
 
 	// mainPkg is a main package metadata.
-	mainPkg *loader.PackageInfo
+	mainPkg *packages.Package
 
 	// packages contains packages in the order they were loaded.
 	packages []string
@@ -228,8 +238,9 @@ func (c *codegen) getVarIndex(pkg string, name string) *varInfo {
 			}
 		}
 	}
-	if i, ok := c.globals[c.getIdentName(pkg, name)]; ok {
-		return &varInfo{refType: varGlobal, index: i}
+	fullName := c.getIdentName(pkg, name)
+	if i, ok := c.globals[fullName]; ok {
+		return &varInfo{refType: varGlobal, index: i, boxed: c.boxedGlobals[fullName]}
 	}
 
 	c.scope.newVariable(varLocal, name)
@@ -276,6 +287,9 @@ func (c *codegen) emitLoadVar(pkg string, name string) {
 		return
 	}
 	c.emitLoadByIndex(vi.refType, vi.index)
+	if vi.boxed {
+		emit.Opcodes(c.prog.BinWriter, opcode.PUSH0, opcode.PICKITEM)
+	}
 }
 
 // emitLoadByIndex loads specified variable type with index i.
@@ -295,6 +309,11 @@ func (c *codegen) emitStoreVar(pkg string, name string) {
 		return
 	}
 	vi := c.getVarIndex(pkg, name)
+	if vi.boxed {
+		c.emitLoadByIndex(vi.refType, vi.index)
+		c.emitStoreBox()
+		return
+	}
 	c.emitStoreByIndex(vi.refType, vi.index)
 }
 
@@ -478,20 +497,32 @@ func (c *codegen) convertFuncDecl(file ast.Node, decl *ast.FuncDecl, pkg *types.
 	//
 	// FIXME: For now we will hard cast this to a struct. We can later fine tune this
 	// to support other types.
+	var args []*ast.Ident
 	if decl.Recv != nil {
 		for _, arg := range decl.Recv.List {
 			// only create an argument here, it will be stored via INITSLOT
 			c.scope.newVariable(varArgument, arg.Names[0].Name)
+			args = append(args, arg.Names[0])
 		}
 	}
 
+	// Boxes of captured variables are passed as the first arguments.
+	for _, v := range f.captured {
+		i := c.scope.newVariable(varArgument, v.Name())
+		c.scope.vars.addAlias(v.Name(), varArgument, i, nil)
+		c.scope.vars.setBoxed(v.Name())
+	}
+
 	// Load the arguments in scope.
 	for _, arg := range decl.Type.Params.List {
 		for _, id := range arg.Names {
 			// only create an argument here, it will be stored via INITSLOT
 			c.scope.newVariable(varArgument, id.Name)
+			args = append(args, id)
 		}
 	}
+	c.boxArguments(args)
+	c.boxResults(decl)
 
 	ast.Walk(c, decl.Body)
 
@@ -513,8 +544,23 @@ func (c *codegen) convertFuncDecl(file ast.Node, decl *ast.FuncDecl, pkg *types.
 	f.rng.End = uint16(c.prog.Len() - 1)
 
 	if !isLambda {
-		for _, f := range c.lambda {
-			c.convertFuncDecl(file, f.decl, pkg)
+		// Lambdas can be nested, so new ones are added to the map during
+		// conversion. Convert them in the order of appearance to produce
+		// the same program every time.
+		for done := make(map[string]bool); len(done) < len(c.lambda); {
+			var lambdas []*funcScope
+			for name, f := range c.lambda {
+				if !done[name] {
+					done[name] = true
+					lambdas = append(lambdas, f)
+				}
+			}
+			sort.Slice(lambdas, func(i, j int) bool {
+				return lambdas[i].label < lambdas[j].label
+			})
+			for _, f := range lambdas {
+				c.convertFuncDecl(file, f.decl, pkg)
+			}
 		}
 		c.lambda = make(map[string]*funcScope)
 	}
@@ -532,6 +578,8 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 	if c.prog.Err != nil {
 		return nil
 	}
+	defer c.annotateError(node)
+
 	switch n := node.(type) {
 	// General declarations.
 	// var (
@@ -545,8 +593,8 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			for _, spec := range n.Specs {
 				vs := spec.(*ast.ValueSpec)
 				for i := range vs.Names {
-					info := c.buildInfo.program.Package(c.currPkg.Path())
-					obj := info.Defs[vs.Names[i]]
+					info := c.buildInfo.program[c.currPkg.Path()]
+					obj := info.TypesInfo.Defs[vs.Names[i]]
 					c.constMap[c.getIdentName("", vs.Names[i].Name)] = types.TypeAndValue{
 						Type:  obj.Type(),
 						Value: obj.(*types.Const).Val(),
@@ -560,13 +608,21 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			case *ast.ValueSpec:
 				for _, id := range t.Names {
 					if id.Name != "_" {
+						var index int
 						if c.scope == nil {
 							// it is a global declaration
 							c.newGlobal("", id.Name)
+							index = c.globals[c.getIdentName("", id.Name)]
+							if c.isBoxed(id) {
+								c.emitNewBox(id.Name)
+							}
 						} else {
-							c.scope.newLocal(id.Name)
+							index = c.scope.newLocal(id.Name)
+							if c.isBoxed(id) {
+								c.emitNewBox(id.Name)
+							}
 						}
-						c.registerDebugVariable(id.Name, t.Type)
+						c.registerDebugVariable(id.Name, t.Type, index)
 					}
 				}
 				for i := range t.Names {
@@ -595,12 +651,15 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 		for i := 0; i < len(n.Lhs); i++ {
 			switch t := n.Lhs[i].(type) {
 			case *ast.Ident:
-				if n.Tok == token.DEFINE {
-					if !multiRet {
-						c.registerDebugVariable(t.Name, n.Rhs[i])
+				// Variables redeclared in `:=` are assigned to, they can be
+				// captured or address-taken, so they must not get a new slot.
+				if n.Tok == token.DEFINE && t.Name != "_" && c.isNewVar(t) {
+					index := c.scope.newLocal(t.Name)
+					if c.isBoxed(t) {
+						c.emitNewBox(t.Name)
 					}
-					if t.Name != "_" {
-						c.scope.newLocal(t.Name)
+					if !multiRet {
+						c.registerDebugVariable(t.Name, n.Rhs[i], index)
 					}
 				}
 				if !isAssignOp && (i == 0 || !multiRet) {
@@ -636,6 +695,14 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 				ast.Walk(c, t.X)
 				ast.Walk(c, t.Index)
 				emit.Opcodes(c.prog.BinWriter, opcode.ROT, opcode.SETITEM)
+
+			// Assignments via pointers.
+			// *p = 10
+			case *ast.StarExpr:
+				if !isAssignOp {
+					ast.Walk(c, n.Rhs[i])
+				}
+				c.emitStoreDeref(t.X)
 			}
 		}
 		return nil
@@ -794,10 +861,8 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 
 	case *ast.FuncLit:
 		l := c.newLabel()
-		c.newLambda(l, n)
-		buf := make([]byte, 4)
-		binary.LittleEndian.PutUint16(buf, l)
-		emit.Instruction(c.prog.BinWriter, opcode.PUSHA, buf)
+		f := c.newLambda(l, n)
+		c.emitClosure(l, f.captured)
 		return nil
 
 	case *ast.BasicLit:
@@ -805,13 +870,8 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 		return nil
 
 	case *ast.StarExpr:
-		_, ok := c.getStruct(c.typeOf(n.X))
-		if !ok {
-			c.prog.Err = errors.New("dereferencing is only supported on structs")
-			return nil
-		}
 		ast.Walk(c, n.X)
-		c.emitConvert(stackitem.StructT)
+		c.emitDeref(c.typeOf(n.X))
 		return nil
 
 	case *ast.Ident:
@@ -819,6 +879,8 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			c.emitLoadConst(tv)
 		} else if n.Name == "nil" {
 			emit.Opcodes(c.prog.BinWriter, opcode.PUSHNULL)
+		} else if fn, ok := c.objectOf(n).(*types.Func); ok {
+			c.emitFuncValue(fn)
 		} else {
 			c.emitLoadVar("", n.Name)
 		}
@@ -897,7 +959,7 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			// directly.
 			name, isMethod := c.getFuncNameFromSelector(fun)
 			if isMethod {
-				ast.Walk(c, fun.X)
+				c.emitReceiver(fun)
 				// Dont forget to add 1 extra argument when its a method.
 				numArgs++
 			}
@@ -949,7 +1011,7 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			}
 		}
 		// Do not swap for builtin functions.
-		if !isBuiltin && (f != nil && !isSyscall(f)) {
+		if !isBuiltin && (f == nil || !isSyscall(f)) {
 			typ, ok := c.typeOf(n.Fun).(*types.Signature)
 			if ok && typ.Variadic() && !n.Ellipsis.IsValid() {
 				// pack variadic args into an array only if last argument is not of form `...`
@@ -973,10 +1035,13 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 				c.emitConvert(stackitem.ByteArrayT)
 			} else if isFunc {
 				c.emitLoadVar("", name)
-				emit.Opcodes(c.prog.BinWriter, opcode.CALLA)
+				c.emitCallFuncValue()
 			}
 		case isLiteral:
 			ast.Walk(c, n.Fun)
+			if len(c.closures[n.Fun.(*ast.FuncLit)]) != 0 {
+				c.emitUnpackClosure()
+			}
 			emit.Opcodes(c.prog.BinWriter, opcode.CALLA)
 		case isSyscall(f):
 			c.convertSyscall(f, n)
@@ -1011,6 +1076,7 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			catchLabel:   catch,
 			finallyLabel: finally,
 			expr:         n.Call,
+			locals:       append([]map[string]varInfo(nil), c.scope.vars.locals...),
 		})
 		return nil
 
@@ -1022,6 +1088,8 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			name := c.getIdentName(pkgAlias, n.Sel.Name)
 			if tv, ok := c.constMap[name]; ok {
 				c.emitLoadConst(tv)
+			} else if fn, ok := c.objectOf(n.Sel).(*types.Func); ok {
+				c.emitFuncValue(fn)
 			} else {
 				c.emitLoadVar(pkgAlias, n.Sel.Name)
 			}
@@ -1039,15 +1107,7 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 
 	case *ast.UnaryExpr:
 		if n.Op == token.AND {
-			// We support only taking address from struct literals.
-			// For identifiers we can't support "taking address" in a general way
-			// because both struct and array are reference types.
-			lit, ok := n.X.(*ast.CompositeLit)
-			if ok {
-				c.convertStruct(lit, true)
-				return nil
-			}
-			c.prog.Err = fmt.Errorf("'&' can be used only with struct literals")
+			c.emitAddressOf(n.X)
 			return nil
 		}
 
@@ -1075,11 +1135,15 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 		ast.Walk(c, n.X)
 		c.emitToken(n.Tok, c.typeOf(n.X))
 
-		// For now only identifiers are supported for (post) for stmts.
+		// For now only identifiers and pointer dereferences are supported
+		// for (post) for stmts.
 		// for i := 0; i < 10; i++ {}
 		// Where the post stmt is ( i++ )
-		if ident, ok := n.X.(*ast.Ident); ok {
-			c.emitStoreVar("", ident.Name)
+		switch t := n.X.(type) {
+		case *ast.Ident:
+			c.emitStoreVar("", t.Name)
+		case *ast.StarExpr:
+			c.emitStoreDeref(t.X)
 		}
 		return nil
 
@@ -1185,6 +1249,17 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 		c.scope.vars.newScope()
 		defer c.scope.vars.dropScope()
 
+		if n.Tok == token.DEFINE {
+			// Range variables are shared between iterations, so boxes
+			// for them are allocated once before the loop.
+			for _, e := range []ast.Expr{n.Key, n.Value} {
+				if id, ok := e.(*ast.Ident); ok && id.Name != "_" && c.isBoxed(id) {
+					c.scope.newLocal(id.Name)
+					c.emitNewBox(id.Name)
+				}
+			}
+		}
+
 		start, label := c.generateLabel(labelStart)
 		end := c.newNamedLabel(labelEnd, label)
 		post := c.newNamedLabel(labelPost, label)
@@ -1276,10 +1351,52 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			c.emitConvert(typ)
 		}
 		return nil
+
+	case *ast.GoStmt:
+		c.prog.Err = errors.New("goroutines are not supported")
+		return nil
+
+	case *ast.SendStmt, *ast.SelectStmt:
+		c.prog.Err = errors.New("channels are not supported")
+		return nil
 	}
 	return c
 }
 
+// posError is an error occurred while converting some node of the program.
+type posError struct {
+	pos token.Position
+	err error
+}
+
+// Error implements error interface.
+func (e *posError) Error() string {
+	return e.pos.String() + ": " + e.err.Error()
+}
+
+// Unwrap returns the original error.
+func (e *posError) Unwrap() error {
+	return e.err
+}
+
+// annotateError adds the position of node to the error occurred while
+// converting it, so that it's easy to find the problem even if it's
+// located in some dependency. Errors from nested nodes are more precise,
+// so they are not annotated again.
+func (c *codegen) annotateError(node ast.Node) {
+	if c.prog.Err == nil || node == nil {
+		return
+	}
+	var pe *posError
+	if errors.As(c.prog.Err, &pe) {
+		return
+	}
+	pos := c.buildInfo.fset.Position(node.Pos())
+	if pos.IsValid() {
+		c.prog.Err = &posError{pos: pos, err: c.prog.Err}
+	}
+}
+
 // packVarArgs packs variadic arguments into an array
 // and returns amount of arguments packed.
 func (c *codegen) packVarArgs(n *ast.CallExpr, typ *types.Signature) int {
@@ -1328,7 +1445,7 @@ func (c *codegen) processDefers() {
 
 		finalIndex := c.getVarIndex("", finallyVarName).index
 		c.emitStoreByIndex(varLocal, finalIndex)
-		ast.Walk(c, stmt.expr)
+		c.walkDeferred(stmt)
 		if i == 0 {
 			results := c.scope.decl.Type.Results
 			if results.NumFields() != 0 {
@@ -1345,7 +1462,7 @@ func (c *codegen) processDefers() {
 		before := c.newLabel()
 		c.emitLoadByIndex(varLocal, finalIndex)
 		emit.Jmp(c.prog.BinWriter, opcode.JMPIFL, before)
-		ast.Walk(c, stmt.expr)
+		c.walkDeferred(stmt)
 		c.setLabel(before)
 		emit.Int(c.prog.BinWriter, 0)
 		c.emitStoreByIndex(varLocal, finalIndex)
@@ -1354,6 +1471,15 @@ func (c *codegen) processDefers() {
 	}
 }
 
+// walkDeferred emits deferred call using variables visible at the point
+// where it was deferred.
+func (c *codegen) walkDeferred(stmt deferInfo) {
+	oldScope := c.scope.vars.locals
+	c.scope.vars.locals = stmt.locals
+	ast.Walk(c, stmt.expr)
+	c.scope.vars.locals = oldScope
+}
+
 // emitExplicitConvert handles `someType(someValue)` conversions between string/[]byte.
 // Rules for conversion:
 // 1. interop.* types are converted to ByteArray if not already.
@@ -1648,12 +1774,16 @@ func (c *codegen) convertBuiltin(expr *ast.CallExpr) {
 		switch {
 		case isMap(typ):
 			emit.Opcodes(c.prog.BinWriter, opcode.NEWMAP)
+		case isChan(typ):
+			c.prog.Err = errors.New("channels are not supported")
 		default:
-			if len(expr.Args) == 3 {
-				c.prog.Err = fmt.Errorf("`make()` with a capacity argument is not supported")
-				return
-			}
 			ast.Walk(c, expr.Args[1])
+			if len(expr.Args) == 3 && c.typeAndValueOf(expr.Args[2]).Value == nil {
+				// Capacity has no meaning for VM arrays and buffers, but it
+				// still needs to be evaluated.
+				ast.Walk(c, expr.Args[2])
+				emit.Opcodes(c.prog.BinWriter, opcode.DROP)
+			}
 			if isByteSlice(typ) {
 				emit.Opcodes(c.prog.BinWriter, opcode.NEWBUFFER)
 			} else {
@@ -1661,6 +1791,8 @@ func (c *codegen) convertBuiltin(expr *ast.CallExpr) {
 				emit.Instruction(c.prog.BinWriter, opcode.NEWARRAYT, []byte{byte(neoT)})
 			}
 		}
+	case "new":
+		c.emitNew(c.typeOf(expr.Args[0]))
 	case "len":
 		emit.Opcodes(c.prog.BinWriter, opcode.DUP, opcode.ISNULL)
 		emit.Instruction(c.prog.BinWriter, opcode.JMPIF, []byte{2 + 1 + 2})
@@ -1757,7 +1889,7 @@ func transformArgs(fs *funcScope, fun ast.Expr, args []ast.Expr) []ast.Expr {
 		}
 	case *ast.Ident:
 		switch f.Name {
-		case "make", "copy", "append":
+		case "make", "copy", "append", "new":
 			return nil
 		}
 	}
@@ -1951,7 +2083,7 @@ func (c *codegen) newFunc(decl *ast.FuncDecl) *funcScope {
 func (c *codegen) getFuncFromIdent(fun *ast.Ident) (*funcScope, bool) {
 	var pkgName string
 	if len(c.pkgInfoInline) != 0 {
-		pkgName = c.pkgInfoInline[len(c.pkgInfoInline)-1].Pkg.Path()
+		pkgName = c.pkgInfoInline[len(c.pkgInfoInline)-1].PkgPath
 	}
 
 	f, ok := c.funcs[c.getIdentName(pkgName, fun.Name)]
@@ -1963,23 +2095,29 @@ func (c *codegen) getFuncFromIdent(fun *ast.Ident) (*funcScope, bool) {
 func (c *codegen) getFuncNameFromSelector(e *ast.SelectorExpr) (string, bool) {
 	ident := e.X.(*ast.Ident)
 	if c.typeInfo.Selections[e] != nil {
-		typ := c.typeInfo.Types[ident].Type.String()
-		return c.getIdentName(typ, e.Sel.Name), true
+		typ := c.typeInfo.Types[ident].Type
+		if t, ok := typ.(*types.Pointer); ok {
+			// Methods are called the same way on values and pointers.
+			typ = t.Elem()
+		}
+		return c.getIdentName(typ.String(), e.Sel.Name), true
 	}
 	return c.getIdentName(ident.Name, e.Sel.Name), false
 }
 
-func (c *codegen) newLambda(u uint16, lit *ast.FuncLit) {
+func (c *codegen) newLambda(u uint16, lit *ast.FuncLit) *funcScope {
 	name := fmt.Sprintf("lambda@%d", u)
 	f := c.newFuncScope(&ast.FuncDecl{
 		Name: ast.NewIdent(name),
 		Type: lit.Type,
 		Body: lit.Body,
 	}, u)
+	f.captured = c.closures[lit]
 	c.lambda[c.getFuncNameFromDecl("", f.decl)] = f
+	return f
 }
 
-func (c *codegen) compile(info *buildInfo, pkg *loader.PackageInfo) error {
+func (c *codegen) compile(info *buildInfo, pkg *packages.Package) error {
 	c.mainPkg = pkg
 	c.analyzePkgOrder()
 	c.fillDocumentInfo()
@@ -1987,6 +2125,8 @@ func (c *codegen) compile(info *buildInfo, pkg *loader.PackageInfo) error {
 
 	// Bring all imported functions into scope.
 	c.ForEachFile(c.resolveFuncDecls)
+	c.analyzeClosures()
+	c.analyzeAddressTaken()
 
 	hasDeploy := c.traverseGlobals()
 
@@ -2002,13 +2142,6 @@ func (c *codegen) compile(info *buildInfo, pkg *loader.PackageInfo) error {
 		emit.Opcodes(c.prog.BinWriter, opcode.RET)
 	}
 
-	// sort map keys to generate code deterministically.
-	keys := make([]*types.Package, 0, len(info.program.AllPackages))
-	for p := range info.program.AllPackages {
-		keys = append(keys, p)
-	}
-	sort.Slice(keys, func(i, j int) bool { return keys[i].Path() < keys[j].Path() })
-
 	// Generate the code for the program.
 	c.ForEachFile(func(f *ast.File, pkg *types.Package) {
 		for _, decl := range f.Decls {
@@ -2017,7 +2150,7 @@ func (c *codegen) compile(info *buildInfo, pkg *loader.PackageInfo) error {
 				// Don't convert the function if it's not used. This will save a lot
 				// of bytecode space.
 				pkgPath := ""
-				if pkg != c.mainPkg.Pkg { // not a main package
+				if pkg != c.mainPkg.Types { // not a main package
 					pkgPath = pkg.Path()
 				}
 				name := c.getFuncNameFromDecl(pkgPath, n)
@@ -2032,17 +2165,20 @@ func (c *codegen) compile(info *buildInfo, pkg *loader.PackageInfo) error {
 	return c.prog.Err
 }
 
-func newCodegen(info *buildInfo, pkg *loader.PackageInfo) *codegen {
+func newCodegen(info *buildInfo, pkg *packages.Package) *codegen {
 	return &codegen{
 		buildInfo:        info,
 		prog:             io.NewBufBinWriter(),
 		l:                []int{},
 		funcs:            map[string]*funcScope{},
 		lambda:           map[string]*funcScope{},
+		closures:         map[*ast.FuncLit][]*types.Var{},
+		boxedVars:        map[*types.Var]bool{},
 		reverseOffsetMap: map[int]nameWithLocals{},
 		globals:          map[string]int{},
+		boxedGlobals:     map[string]bool{},
 		labels:           map[labelWithType]uint16{},
-		typeInfo:         &pkg.Info,
+		typeInfo:         pkg.TypesInfo,
 		constMap:         map[string]types.TypeAndValue{},
 		docIndex:         map[string]int{},
 
@@ -2057,7 +2193,7 @@ func newCodegen(info *buildInfo, pkg *loader.PackageInfo) *codegen {
 
 // CodeGen compiles the program to bytecode.
 func CodeGen(info *buildInfo) ([]byte, *DebugInfo, error) {
-	pkg := info.program.Package(info.initialPackage)
+	pkg := info.mainPkg
 	c := newCodegen(info, pkg)
 
 	if err := c.compile(info, pkg); err != nil {
@@ -2130,6 +2266,11 @@ func (c *codegen) writeJumps(b []byte) ([]byte, error) {
 		}
 	}
 
+	if c.buildInfo != nil && c.buildInfo.options != nil && c.buildInfo.options.Optimize {
+		// Optimizer assembles the program itself using the shortest jumps.
+		return c.optimize(b)
+	}
+
 	if c.deployEndOffset >= 0 {
 		_, end := correctRange(uint16(c.initEndOffset+1), uint16(c.deployEndOffset), offsets)
 		c.deployEndOffset = int(end)
@@ -2144,6 +2285,14 @@ func (c *codegen) writeJumps(b []byte) ([]byte, error) {
 	for _, f := range c.funcs {
 		f.rng.Start, f.rng.End = correctRange(f.rng.Start, f.rng.End, offsets)
 	}
+	// Correct sequence points, they can't be in the middle of the instruction
+	// being shortened.
+	for _, points := range c.sequencePoints {
+		for i := range points {
+			start, _ := correctRange(uint16(points[i].Opcode), uint16(points[i].Opcode), offsets)
+			points[i].Opcode = int(start)
+		}
+	}
 	return shortenJumps(b, offsets), nil
 }
 
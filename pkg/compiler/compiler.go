@@ -6,18 +6,18 @@ import (
 	"fmt"
 	"go/ast"
 	"go/parser"
+	"go/token"
 	"go/types"
 	"io"
 	"io/ioutil"
 	"os"
-	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest/standard"
-	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/nspcc-dev/neo-go/pkg/util"
-	"golang.org/x/tools/go/loader" //nolint:staticcheck // SA1019: package golang.org/x/tools/go/loader is deprecated
+	"golang.org/x/tools/go/packages"
 )
 
 const fileExt = "nef"
@@ -70,31 +70,36 @@ type Options struct {
 
 	// Permissions is a list of permissions for every contract method.
 	Permissions []manifest.Permission
+
+	// Optimize enables bytecode optimizations (constant folding, dead code
+	// elimination, removal of unused local variables, etc.) of the program.
+	Optimize bool
 }
 
 type buildInfo struct {
-	initialPackage string
-	program        *loader.Program
-	options        *Options
+	mainPkg *packages.Package
+	program map[string]*packages.Package
+	fset    *token.FileSet
+	options *Options
 }
 
 // ForEachPackage executes fn on each package used in the current program
 // in the order they should be initialized.
-func (c *codegen) ForEachPackage(fn func(*loader.PackageInfo)) {
+func (c *codegen) ForEachPackage(fn func(*packages.Package)) {
 	for i := range c.packages {
-		pkg := c.buildInfo.program.Package(c.packages[i])
-		c.typeInfo = &pkg.Info
-		c.currPkg = pkg.Pkg
+		pkg := c.buildInfo.program[c.packages[i]]
+		c.typeInfo = pkg.TypesInfo
+		c.currPkg = pkg.Types
 		fn(pkg)
 	}
 }
 
 // ForEachFile executes fn on each file used in current program.
 func (c *codegen) ForEachFile(fn func(*ast.File, *types.Package)) {
-	c.ForEachPackage(func(pkg *loader.PackageInfo) {
-		for _, f := range pkg.Files {
-			c.fillImportMap(f, pkg.Pkg)
-			fn(f, pkg.Pkg)
+	c.ForEachPackage(func(pkg *packages.Package) {
+		for _, f := range pkg.Syntax {
+			c.fillImportMap(f, pkg.Types)
+			fn(f, pkg.Types)
 		}
 	})
 }
@@ -107,55 +112,152 @@ func (c *codegen) fillImportMap(f *ast.File, pkg *types.Package) {
 		// name specified in `package ...` decl, can be in
 		// conflict with package path.
 		pkgPath := strings.Trim(imp.Path.Value, `"`)
-		realPkg := c.buildInfo.program.Package(pkgPath)
-		name := realPkg.Pkg.Name()
+		realPkg := c.buildInfo.program[pkgPath]
+		name := realPkg.Types.Name()
 		if imp.Name != nil {
 			name = imp.Name.Name
 		}
-		c.importMap[name] = realPkg.Pkg.Path()
+		c.importMap[name] = realPkg.Types.Path()
 	}
 }
 
+// getBuildInfo loads the contract package along with all of its dependencies.
+// Packages are resolved by the go tool using the module the contract belongs
+// to, so replace directives and vendoring work the same way they do for
+// regular Go programs. Contracts outside of any module are resolved relative
+// to the current directory.
 func getBuildInfo(name string, src interface{}) (*buildInfo, error) {
-	conf := loader.Config{ParserMode: parser.ParseComments}
-	if src != nil {
-		f, err := conf.ParseFile(name, src)
-		if err != nil {
-			return nil, err
+	absName, err := filepath.Abs(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		pattern []string
+		dir     string
+		// names maps absolute paths of contract files to the names
+		// they're referred to in error messages and debug info.
+		names    = make(map[string]string)
+		overlay  map[string][]byte
+		isSource = src != nil || strings.HasSuffix(name, ".go")
+	)
+	if isSource {
+		dir = filepath.Dir(absName)
+		if !strings.HasSuffix(absName, ".go") {
+			// The go tool only accepts files with proper extension.
+			absName += ".go"
 		}
-		conf.CreateFromFiles("", f)
-	} else {
-		var names []string
-		if strings.HasSuffix(name, ".go") {
-			names = append(names, name)
-		} else {
-			ds, err := ioutil.ReadDir(name)
+		pattern = append(pattern, absName)
+		names[absName] = name
+		if src != nil {
+			buf, err := readSource(src)
 			if err != nil {
-				return nil, fmt.Errorf("'%s' is neither Go source nor a directory", name)
+				return nil, err
 			}
-			for i := range ds {
-				if !ds[i].IsDir() && strings.HasSuffix(ds[i].Name(), ".go") {
-					names = append(names, path.Join(name, ds[i].Name()))
-				}
+			overlay = map[string][]byte{absName: buf}
+		}
+	} else {
+		ds, err := ioutil.ReadDir(name)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is neither Go source nor a directory", name)
+		}
+		dir = absName
+		for i := range ds {
+			if !ds[i].IsDir() && strings.HasSuffix(ds[i].Name(), ".go") &&
+				!strings.HasSuffix(ds[i].Name(), "_test.go") {
+				file := filepath.Join(absName, ds[i].Name())
+				pattern = append(pattern, file)
+				names[file] = filepath.Join(name, ds[i].Name())
 			}
 		}
-		if len(names) == 0 {
+		if len(pattern) == 0 {
 			return nil, errors.New("no files provided")
 		}
-		conf.CreateFromFilenames("", names...)
 	}
 
-	prog, err := conf.Load()
+	fset := token.NewFileSet()
+	conf := &packages.Config{
+		Mode: packages.NeedName |
+			packages.NeedImports |
+			packages.NeedDeps |
+			packages.NeedTypes |
+			packages.NeedSyntax |
+			packages.NeedTypesInfo |
+			packages.NeedModule,
+		Fset:    fset,
+		Overlay: overlay,
+		ParseFile: func(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+			if n, ok := names[filename]; ok {
+				filename = n
+			}
+			return parser.ParseFile(fset, filename, src, parser.ParseComments)
+		},
+	}
+	if isInModule(dir) {
+		conf.Dir = dir
+		if !isSource {
+			pattern = []string{"."}
+		}
+	}
+
+	pkgs, err := packages.Load(conf, pattern...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected exactly one package, got %d", len(pkgs))
+	}
+
+	program := make(map[string]*packages.Package)
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		if err == nil && len(p.Errors) != 0 {
+			err = p.Errors[0]
+			if p.Errors[0].Pos == "" {
+				err = errors.New(p.Errors[0].Msg)
+			}
+		}
+		program[p.PkgPath] = p
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	return &buildInfo{
-		initialPackage: prog.InitialPackages()[0].Pkg.Name(),
-		program:        prog,
+		mainPkg: pkgs[0],
+		program: program,
+		fset:    fset,
 	}, nil
 }
 
+// readSource reads contract source code passed as a string, a byte slice or
+// an io.Reader.
+func readSource(src interface{}) ([]byte, error) {
+	switch s := src.(type) {
+	case string:
+		return []byte(s), nil
+	case []byte:
+		return s, nil
+	case io.Reader:
+		return ioutil.ReadAll(s)
+	default:
+		return nil, errors.New("invalid source")
+	}
+}
+
+// isInModule checks whether dir belongs to some Go module.
+func isInModule(dir string) bool {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
 // Compile compiles a Go program into bytecode that can run on the NEO virtual machine.
 // If `r != nil`, `name` is interpreted as a filename, and `r` as file contents.
 // Otherwise `name` is either file name or name of the directory containing source files.
@@ -177,12 +279,23 @@ func CompileWithDebugInfo(name string, r io.Reader) ([]byte, *DebugInfo, error)
 
 // CompileWithOptions compiles a Go program into bytecode with provided compiler options.
 func CompileWithOptions(name string, r io.Reader, o *Options) ([]byte, *DebugInfo, error) {
+	b, di, _, err := compileWithDeps(name, r, o)
+	return b, di, err
+}
+
+// compileWithDeps compiles a Go program just like CompileWithOptions does and
+// also returns the digest of its dependencies (see depsDigest).
+func compileWithDeps(name string, r io.Reader, o *Options) ([]byte, *DebugInfo, string, error) {
 	ctx, err := getBuildInfo(name, r)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, "", err
 	}
 	ctx.options = o
-	return CodeGen(ctx)
+	b, di, err := CodeGen(ctx)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return b, di, depsDigest(ctx.program), nil
 }
 
 // CompileAndSave will compile and save the file to disk in the NEF format.
@@ -198,21 +311,14 @@ func CompileAndSave(src string, o *Options) ([]byte, error) {
 	if len(o.Ext) == 0 {
 		o.Ext = fileExt
 	}
-	b, di, err := CompileWithOptions(src, nil, o)
+	b, di, deps, err := compileWithDeps(src, nil, o)
 	if err != nil {
 		return nil, fmt.Errorf("error while trying to compile smart contract file: %w", err)
 	}
-	f, err := nef.NewFile(b)
+	f, err := newNEF(b, o, deps)
 	if err != nil {
 		return nil, fmt.Errorf("error while trying to create .nef file: %w", err)
 	}
-	if o.SourceURL != "" {
-		if len(o.SourceURL) > nef.MaxSourceURLLength {
-			return nil, errors.New("too long source URL")
-		}
-		f.Source = o.SourceURL
-		f.Checksum = f.CalculateChecksum()
-	}
 	bytes, err := f.Bytes()
 	if err != nil {
 		return nil, fmt.Errorf("error while serializing .nef file: %w", err)
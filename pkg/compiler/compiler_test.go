@@ -10,6 +10,7 @@ import (
 
 	"github.com/nspcc-dev/neo-go/internal/random"
 	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/compiler/testdata/wrapper"
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/interop/native/neo"
@@ -276,6 +277,30 @@ func TestInvokedContractsPermissons(t *testing.T) {
 		require.NoError(t, testCompile(t, di, true, *p))
 	})
 
+	t.Run("generated wrapper", func(t *testing.T) {
+		src := `package test
+			import "github.com/nspcc-dev/neo-go/pkg/compiler/testdata/wrapper"
+			func Main() int {
+				wrapper.Transfer(nil, nil, 10, nil)
+				wrapper.SetOwner(nil)
+				return wrapper.BalanceOf(nil) // skip safe
+			}`
+
+		_, di, err := compiler.CompileWithOptions("permissionTest", strings.NewReader(src), &compiler.Options{})
+		require.NoError(t, err)
+
+		var h util.Uint160
+		copy(h[:], wrapper.Hash)
+
+		p := manifest.NewPermission(manifest.PermissionHash, h)
+		p.Methods.Add("transfer")
+		require.Error(t, testCompile(t, di, false, *p))
+		require.NoError(t, testCompile(t, di, true, *p))
+
+		p.Methods.Add("setOwner")
+		require.NoError(t, testCompile(t, di, false, *p))
+	})
+
 	t.Run("custom", func(t *testing.T) {
 		hashStr := "aaaaaaaaaaaaaaaaaaaa"
 		src := fmt.Sprintf(`package test
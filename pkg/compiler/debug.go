@@ -27,7 +27,8 @@ type DebugInfo struct {
 	EmittedEvents map[string][][]string `json:"-"`
 	// InvokedContracts contains foreign contract invocations.
 	InvokedContracts map[util.Uint160][]string `json:"-"`
-	// StaticVariables contains list of static variable names and types.
+	// StaticVariables contains list of static variable names, types and
+	// slot indexes in "name,type,index" form.
 	StaticVariables []string `json:"static-variables"`
 }
 
@@ -105,7 +106,7 @@ func (c *codegen) saveSequencePoint(n ast.Node) {
 		name = c.scope.name
 	}
 
-	fset := c.buildInfo.program.Fset
+	fset := c.buildInfo.fset
 	start := fset.Position(n.Pos())
 	end := fset.Position(n.End())
 	c.sequencePoints[name] = append(c.sequencePoints[name], DebugSeqPoint{
@@ -120,7 +121,7 @@ func (c *codegen) saveSequencePoint(n ast.Node) {
 
 func (c *codegen) emitDebugInfo(contract []byte) *DebugInfo {
 	d := &DebugInfo{
-		MainPkg:         c.mainPkg.Pkg.Name(),
+		MainPkg:         c.mainPkg.Name,
 		Events:          []EventDebugInfo{},
 		Documents:       c.documents,
 		StaticVariables: c.staticVariables,
@@ -130,7 +131,7 @@ func (c *codegen) emitDebugInfo(contract []byte) *DebugInfo {
 			ID: manifest.MethodInit,
 			Name: DebugMethodName{
 				Name:      manifest.MethodInit,
-				Namespace: c.mainPkg.Pkg.Name(),
+				Namespace: c.mainPkg.Name,
 			},
 			IsExported: true,
 			IsFunction: true,
@@ -149,7 +150,7 @@ func (c *codegen) emitDebugInfo(contract []byte) *DebugInfo {
 			ID: manifest.MethodDeploy,
 			Name: DebugMethodName{
 				Name:      manifest.MethodDeploy,
-				Namespace: c.mainPkg.Pkg.Name(),
+				Namespace: c.mainPkg.Name,
 			},
 			IsExported: true,
 			IsFunction: true,
@@ -177,7 +178,7 @@ func (c *codegen) emitDebugInfo(contract []byte) *DebugInfo {
 	}
 	for name, scope := range c.funcs {
 		m := c.methodInfoFromScope(name, scope)
-		if m.Range.Start == m.Range.End {
+		if m.Range.Start == m.Range.End && !c.singleInstrFuncs[scope] {
 			continue
 		}
 		d.Methods = append(d.Methods, *m)
@@ -187,13 +188,17 @@ func (c *codegen) emitDebugInfo(contract []byte) *DebugInfo {
 	return d
 }
 
-func (c *codegen) registerDebugVariable(name string, expr ast.Expr) {
+// registerDebugVariable adds variable with the given name, type and slot
+// index to the debug info of the current function (or to the static
+// variables if there is no current function).
+func (c *codegen) registerDebugVariable(name string, expr ast.Expr, index int) {
 	_, vt := c.scAndVMTypeFromExpr(expr)
+	v := name + "," + vt.String() + "," + strconv.Itoa(index)
 	if c.scope == nil {
-		c.staticVariables = append(c.staticVariables, name+","+vt.String())
+		c.staticVariables = append(c.staticVariables, v)
 		return
 	}
-	c.scope.variables = append(c.scope.variables, name+","+vt.String())
+	c.scope.variables = append(c.scope.variables, v)
 }
 
 func (c *codegen) methodInfoFromScope(name string, scope *funcScope) *MethodDebugInfo {
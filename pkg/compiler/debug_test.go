@@ -8,6 +8,7 @@ import (
 	"github.com/nspcc-dev/neo-go/internal/testserdes"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -70,7 +71,7 @@ func _deploy(data interface{}, isUpdate bool) { x := 1; _ = x }
 	info, err := getBuildInfo("foo.go", src)
 	require.NoError(t, err)
 
-	pkg := info.program.Package(info.initialPackage)
+	pkg := info.mainPkg
 	c := newCodegen(info, pkg)
 	require.NoError(t, c.compile(info, pkg))
 
@@ -102,9 +103,9 @@ func _deploy(data interface{}, isUpdate bool) { x := 1; _ = x }
 
 	t.Run("variables", func(t *testing.T) {
 		vars := map[string][]string{
-			"Main":                {"s,ByteString", "res,Integer"},
-			manifest.MethodInit:   {"a,Integer", "x,ByteString"},
-			manifest.MethodDeploy: {"x,Integer"},
+			"Main":                {"s,ByteString,0", "res,Integer,1"},
+			manifest.MethodInit:   {"a,Integer,0", "x,ByteString,0"},
+			manifest.MethodDeploy: {"x,Integer,0"},
 		}
 		for i := range d.Methods {
 			v, ok := vars[d.Methods[i].ID]
@@ -115,7 +116,7 @@ func _deploy(data interface{}, isUpdate bool) { x := 1; _ = x }
 	})
 
 	t.Run("static variables", func(t *testing.T) {
-		require.Equal(t, []string{"staticVar,Integer"}, d.StaticVariables)
+		require.Equal(t, []string{"staticVar,Integer,0"}, d.StaticVariables)
 	})
 
 	t.Run("param types", func(t *testing.T) {
@@ -311,7 +312,7 @@ func TestSequencePoints(t *testing.T) {
 	info, err := getBuildInfo("foo.go", src)
 	require.NoError(t, err)
 
-	pkg := info.program.Package(info.initialPackage)
+	pkg := info.mainPkg
 	c := newCodegen(info, pkg)
 	require.NoError(t, c.compile(info, pkg))
 
@@ -328,6 +329,36 @@ func TestSequencePoints(t *testing.T) {
 	require.Equal(t, 6, ps[1].StartLine)
 }
 
+func TestSequencePointsShortJumps(t *testing.T) {
+	src := `package foo
+	func Main(n int) int {
+		for i := 0; i < n; i++ {
+			n = double(n)
+		}
+		return n
+	}
+	func double(a int) int {
+		return a * 2
+	}`
+
+	b, d, err := CompileWithDebugInfo("foo.go", strings.NewReader(src))
+	require.NoError(t, err)
+
+	boundaries := make(map[int]opcode.Opcode)
+	ctx := vm.NewContext(b)
+	for op, _, err := ctx.Next(); err == nil && ctx.IP() < len(b); op, _, err = ctx.Next() {
+		boundaries[ctx.IP()] = op
+	}
+	for _, m := range d.Methods {
+		for _, p := range m.SeqPoints {
+			_, ok := boundaries[p.Opcode]
+			require.True(t, ok, "%s: %d", m.ID, p.Opcode)
+		}
+		// Return sequence point is the last one.
+		require.Equal(t, opcode.RET, boundaries[m.SeqPoints[len(m.SeqPoints)-1].Opcode])
+	}
+}
+
 func TestDebugInfo_MarshalJSON(t *testing.T) {
 	d := &DebugInfo{
 		Documents: []string{"/path/to/file"},
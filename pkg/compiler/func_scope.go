@@ -47,12 +47,18 @@ type funcScope struct {
 
 	// Local variable counter.
 	i int
+
+	// captured contains variables captured by the function literal, their
+	// boxes are passed to the function as the first arguments.
+	captured []*types.Var
 }
 
 type deferInfo struct {
 	catchLabel   uint16
 	finallyLabel uint16
 	expr         *ast.CallExpr
+	// locals contains local variables visible at the defer statement.
+	locals []map[string]varInfo
 }
 
 const (
@@ -104,7 +110,7 @@ func (c *funcScope) analyzeVoidCalls(node ast.Node) bool {
 }
 
 func (c *funcScope) countArgs() int {
-	n := c.decl.Type.Params.NumFields()
+	n := c.decl.Type.Params.NumFields() + len(c.captured)
 	if c.decl.Recv != nil {
 		n += c.decl.Recv.NumFields()
 	}
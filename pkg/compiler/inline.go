@@ -29,7 +29,7 @@ func (c *codegen) inlineCall(f *funcScope, n *ast.CallExpr) {
 		c.labelList = c.labelList[:labelSz]
 	}()
 
-	pkg := c.buildInfo.program.Package(f.pkg.Path())
+	pkg := c.buildInfo.program[f.pkg.Path()]
 	sig := c.typeOf(n.Fun).(*types.Signature)
 
 	c.processStdlibCall(f, n.Args)
@@ -99,7 +99,7 @@ func (c *codegen) inlineCall(f *funcScope, n *ast.CallExpr) {
 
 	c.pkgInfoInline = append(c.pkgInfoInline, pkg)
 	oldMap := c.importMap
-	c.fillImportMap(f.file, pkg.Pkg)
+	c.fillImportMap(f.file, pkg.Types)
 	ast.Inspect(f.decl, c.scope.analyzeVoidCalls)
 	ast.Walk(c, f.decl.Body)
 	if c.scope.voidCalls[n] {
@@ -127,7 +127,7 @@ func (c *codegen) processStdlibCall(f *funcScope, args []ast.Expr) {
 
 func (c *codegen) processNotify(f *funcScope, args []ast.Expr) {
 	if c.scope != nil && c.isVerifyFunc(c.scope.decl) &&
-		c.scope.pkg == c.mainPkg.Pkg && !c.buildInfo.options.NoEventsCheck {
+		c.scope.pkg == c.mainPkg.Types && !c.buildInfo.options.NoEventsCheck {
 		c.prog.Err = fmt.Errorf("runtime.%s is not allowed in `Verify`", f.name)
 		return
 	}
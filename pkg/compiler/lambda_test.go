@@ -3,6 +3,8 @@ package compiler_test
 import (
 	"math/big"
 	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 )
 
 func TestFuncLiteral(t *testing.T) {
@@ -14,6 +16,18 @@ func TestFuncLiteral(t *testing.T) {
 	eval(t, src, big.NewInt(5))
 }
 
+func TestFuncLiteralArgumentsOrder(t *testing.T) {
+	src := `package foo
+	func Main() int {
+		f := func(a, b int) int { return a*10 + b }
+		return f(1, 2) + call(f) + func(a, b int) int { return a - b }(5, 3)
+	}
+	func call(f func(int, int) int) int {
+		return f(3, 4) * 100
+	}`
+	eval(t, src, big.NewInt(3414))
+}
+
 func TestCallInPlace(t *testing.T) {
 	src := `package foo
 	var a int = 1
@@ -26,3 +40,217 @@ func TestCallInPlace(t *testing.T) {
 	}`
 	eval(t, src, big.NewInt(111))
 }
+
+func TestFuncValue(t *testing.T) {
+	src := `package foo
+	func Main() int {
+		f := double
+		return apply(f, 1) + apply(double, 10)
+	}
+	func double(x int) int { return x * 2 }
+	func apply(f func(int) int, x int) int { return f(x) }`
+	eval(t, src, big.NewInt(22))
+}
+
+func TestClosure(t *testing.T) {
+	t.Run("capture local", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			a := 1
+			inc := func() { a++ }
+			inc()
+			inc()
+			return a
+		}`
+		eval(t, src, big.NewInt(3))
+	})
+	t.Run("redeclared in short declaration", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			x := 1
+			f := func() int { return x }
+			x, y := 2, 3
+			return f()*10 + y
+		}`
+		eval(t, src, big.NewInt(23))
+	})
+	t.Run("capture argument", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			return add(2, 3)
+		}
+		func add(a, b int) int {
+			f := func() int { return a + b }
+			a = 10
+			return f()
+		}`
+		eval(t, src, big.NewInt(13))
+	})
+	t.Run("capture with arguments", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			var sum int
+			add := func(x, y int) int { sum += x * y; return sum }
+			add(2, 3)
+			return add(4, 5)
+		}`
+		eval(t, src, big.NewInt(26))
+	})
+	t.Run("call in place", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			a, b := 1, 2
+			func() {
+				a += b
+				b *= a
+			}()
+			return a*10 + b
+		}`
+		eval(t, src, big.NewInt(36))
+	})
+	t.Run("nested", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			a := 1
+			f := func(x int) func() int {
+				b := x
+				return func() int {
+					a++
+					b *= 2
+					return a + b
+				}
+			}
+			g := f(3)
+			g()
+			return g() + a
+		}`
+		eval(t, src, big.NewInt(18))
+	})
+	t.Run("counter", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			c1 := newCounter()
+			c2 := newCounter()
+			c1()
+			c1()
+			c2()
+			return c1()*10 + c2()
+		}
+		func newCounter() func() int {
+			n := 0
+			return func() int {
+				n++
+				return n
+			}
+		}`
+		eval(t, src, big.NewInt(32))
+	})
+	t.Run("in loop", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			var fs []func() int
+			for i := 0; i < 3; i++ {
+				j := i
+				fs = append(fs, func() int { return j * 10 + i })
+			}
+			var sum int
+			for _, f := range fs {
+				sum += f()
+			}
+			return sum
+		}`
+		// Loop variable is shared between iterations, but j is not.
+		eval(t, src, big.NewInt(39))
+	})
+	t.Run("range variables", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			var fs []func() int
+			for i, v := range []int{1, 2, 3} {
+				fs = append(fs, func() int { return i*10 + v })
+			}
+			f := fs[0]
+			return f()
+		}`
+		eval(t, src, big.NewInt(23))
+	})
+	t.Run("passed to function", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			threshold := 2
+			count := 0
+			arr := filter([]int{1, 2, 3, 4}, func(x int) bool {
+				count++
+				return x > threshold
+			})
+			return len(arr)*10 + count
+		}
+		func filter(arr []int, f func(int) bool) []int {
+			var res []int
+			for _, x := range arr {
+				if f(x) {
+					res = append(res, x)
+				}
+			}
+			return res
+		}`
+		eval(t, src, big.NewInt(24))
+	})
+	t.Run("sort comparator", func(t *testing.T) {
+		src := `package foo
+		func Main() []int {
+			desc := true
+			arr := []int{2, 3, 1}
+			sort(arr, func(a, b int) bool {
+				if desc {
+					return a > b
+				}
+				return a < b
+			})
+			return arr
+		}
+		func sort(arr []int, less func(int, int) bool) {
+			for i := range arr {
+				for j := i + 1; j < len(arr); j++ {
+					if less(arr[j], arr[i]) {
+						tmp := arr[i]
+						arr[i] = arr[j]
+						arr[j] = tmp
+					}
+				}
+			}
+		}`
+		eval(t, src, []stackitem.Item{
+			stackitem.NewBigInteger(big.NewInt(3)),
+			stackitem.NewBigInteger(big.NewInt(2)),
+			stackitem.NewBigInteger(big.NewInt(1)),
+		})
+	})
+	t.Run("mixed with lambdas", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			a := 5
+			inc := func(x int) int { return x + 1 }
+			addA := func(x int) int { return x + a }
+			return apply(inc, 1) + apply(addA, 1)
+		}
+		func apply(f func(int) int, x int) int {
+			return f(x)
+		}`
+		eval(t, src, big.NewInt(8))
+	})
+	t.Run("defer", func(t *testing.T) {
+		src := `package foo
+		var result int
+		func Main() int {
+			f()
+			return result
+		}
+		func f() {
+			a := 1
+			defer func() { result = a }()
+			a = 42
+		}`
+		eval(t, src, big.NewInt(42))
+	})
+}
@@ -0,0 +1,651 @@
+package compiler
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"golang.org/x/tools/go/packages"
+)
+
+// Lint check names.
+const (
+	// LintUncheckedWitness reports runtime.CheckWitness calls with ignored
+	// result.
+	LintUncheckedWitness = "unchecked-witness"
+	// LintSafeStorageWrite reports storage modifications in methods marked
+	// as safe (they're called with read-only flags and will fail).
+	LintSafeStorageWrite = "safe-storage-write"
+	// LintReentrancy reports storage modifications made after calls to other
+	// contracts that are able to change the state (and call back).
+	LintReentrancy = "reentrancy"
+	// LintCallFlagAll reports contract.Call invocations with contract.All
+	// flags.
+	LintCallFlagAll = "callflag-all"
+	// LintUnboundedFind reports loops over storage.Find results with no
+	// way to leave them before the iterator is exhausted.
+	LintUnboundedFind = "unbounded-find"
+	// LintPrefixCollision reports storage keys with constant prefixes that
+	// are prefixes of keys used for other data.
+	LintPrefixCollision = "storage-prefix-collision"
+)
+
+// LintChecks contains names of all available lint checks.
+var LintChecks = []string{
+	LintUncheckedWitness,
+	LintSafeStorageWrite,
+	LintReentrancy,
+	LintCallFlagAll,
+	LintUnboundedFind,
+	LintPrefixCollision,
+}
+
+// LintIssue is a potential problem found in contract code by Lint.
+type LintIssue struct {
+	Check   string `json:"check"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+// String implements fmt.Stringer interface, it uses the format of Go
+// tooling messages.
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s (%s)", i.File, i.Line, i.Column, i.Message, i.Check)
+}
+
+// Lint loads the contract the same way Compile does and performs static
+// analysis of its code returning issues found sorted by their position.
+// Options are used to get the list of safe methods. All checks are
+// heuristic, they can produce false positives as well as miss some problems.
+func Lint(name string, r io.Reader, o *Options) ([]LintIssue, error) {
+	ctx, err := getBuildInfo(name, r)
+	if err != nil {
+		return nil, err
+	}
+	if o == nil {
+		o = &Options{}
+	}
+	l := newLinter(ctx, o)
+	l.checkCalls()
+	l.checkSafeMethods()
+	l.checkReentrancy()
+	l.checkFindLoops()
+	l.checkPrefixes()
+
+	sort.Slice(l.issues, func(i, j int) bool {
+		a, b := l.issues[i], l.issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Check < b.Check
+	})
+	return l.issues, nil
+}
+
+type linter struct {
+	prog    *buildInfo
+	options *Options
+	// funcs contains declarations of all functions of the program.
+	funcs map[*types.Func]*lintFunc
+	// pkgs contains contract packages to be checked (all packages except
+	// interop ones).
+	pkgs []*packages.Package
+	// globals contains initializers of package-level variables.
+	globals map[types.Object]ast.Expr
+	issues  []LintIssue
+}
+
+type lintFunc struct {
+	decl *ast.FuncDecl
+	info *types.Info
+	// visiting and visited are used for effects calculation.
+	visiting, visited bool
+	effects           lintEffects
+}
+
+// lintEffects describes what function can do.
+type lintEffects struct {
+	// writes is set if function modifies the storage.
+	writes bool
+	// calls is set if function calls other contracts with flags allowing
+	// to change the state.
+	calls bool
+}
+
+func newLinter(prog *buildInfo, o *Options) *linter {
+	l := &linter{
+		prog:    prog,
+		options: o,
+		funcs:   make(map[*types.Func]*lintFunc),
+		globals: make(map[types.Object]ast.Expr),
+	}
+	for _, pkg := range prog.program {
+		if !isInteropPath(pkg.PkgPath) && len(pkg.Syntax) != 0 {
+			l.pkgs = append(l.pkgs, pkg)
+		}
+		for _, f := range pkg.Syntax {
+			for _, decl := range f.Decls {
+				switch d := decl.(type) {
+				case *ast.FuncDecl:
+					if fn, ok := pkg.TypesInfo.Defs[d.Name].(*types.Func); ok && d.Body != nil {
+						l.funcs[fn] = &lintFunc{decl: d, info: pkg.TypesInfo}
+					}
+				case *ast.GenDecl:
+					if d.Tok != token.VAR {
+						continue
+					}
+					for _, spec := range d.Specs {
+						vs := spec.(*ast.ValueSpec)
+						if len(vs.Names) != len(vs.Values) {
+							continue
+						}
+						for i := range vs.Names {
+							if obj := pkg.TypesInfo.Defs[vs.Names[i]]; obj != nil {
+								l.globals[obj] = vs.Values[i]
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(l.pkgs, func(i, j int) bool {
+		return l.pkgs[i].PkgPath < l.pkgs[j].PkgPath
+	})
+	return l
+}
+
+func (l *linter) report(check string, pos token.Pos, format string, args ...interface{}) {
+	p := l.prog.fset.Position(pos)
+	l.issues = append(l.issues, LintIssue{
+		Check:   check,
+		File:    p.Filename,
+		Line:    p.Line,
+		Column:  p.Column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// forEachFunc executes fn for every function declared in contract packages.
+func (l *linter) forEachFunc(fn func(pkg *packages.Package, decl *ast.FuncDecl)) {
+	for _, pkg := range l.pkgs {
+		for _, f := range pkg.Syntax {
+			for _, decl := range f.Decls {
+				if d, ok := decl.(*ast.FuncDecl); ok && d.Body != nil {
+					fn(pkg, d)
+				}
+			}
+		}
+	}
+}
+
+// calleeOf returns the function called by call if it's known statically.
+func calleeOf(info *types.Info, call *ast.CallExpr) *types.Func {
+	var id *ast.Ident
+	switch f := unparenExpr(call.Fun).(type) {
+	case *ast.Ident:
+		id = f
+	case *ast.SelectorExpr:
+		id = f.Sel
+	default:
+		return nil
+	}
+	fn, _ := info.Uses[id].(*types.Func)
+	return fn
+}
+
+func unparenExpr(e ast.Expr) ast.Expr {
+	for {
+		p, ok := e.(*ast.ParenExpr)
+		if !ok {
+			return e
+		}
+		e = p.X
+	}
+}
+
+// isInteropFunc checks whether fn is the function name of the interop
+// package pkg.
+func isInteropFunc(fn *types.Func, pkg string, name string) bool {
+	return fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == interopPrefix+"/"+pkg && fn.Name() == name
+}
+
+// callFlags returns the value of contract.Call flags if it's constant.
+func callFlags(info *types.Info, call *ast.CallExpr) (callflag.CallFlag, bool) {
+	if len(call.Args) < 3 {
+		return 0, false
+	}
+	value := info.Types[call.Args[2]].Value
+	if value == nil {
+		return 0, false
+	}
+	f, ok := constant.Uint64Val(value)
+	return callflag.CallFlag(f), ok
+}
+
+// callEffects returns the effects of the call.
+func (l *linter) callEffects(info *types.Info, call *ast.CallExpr) lintEffects {
+	fn := calleeOf(info, call)
+	switch {
+	case fn == nil:
+		return lintEffects{}
+	case isInteropFunc(fn, "storage", "Put"), isInteropFunc(fn, "storage", "Delete"):
+		return lintEffects{writes: true}
+	case isInteropFunc(fn, "contract", "Call"):
+		f, ok := callFlags(info, call)
+		return lintEffects{calls: !ok || f&callflag.WriteStates != 0}
+	default:
+		return l.funcEffects(fn)
+	}
+}
+
+// funcEffects returns the effects of fn including the ones of functions it
+// calls. Recursive calls are not taken into account.
+func (l *linter) funcEffects(fn *types.Func) lintEffects {
+	f, ok := l.funcs[fn]
+	if !ok || f.visiting {
+		return lintEffects{}
+	}
+	if f.visited {
+		return f.effects
+	}
+	f.visiting = true
+	ast.Inspect(f.decl.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			e := l.callEffects(f.info, call)
+			f.effects.writes = f.effects.writes || e.writes
+			f.effects.calls = f.effects.calls || e.calls
+		}
+		return true
+	})
+	f.visiting = false
+	f.visited = true
+	return f.effects
+}
+
+// checkCalls performs the checks of separate calls.
+func (l *linter) checkCalls() {
+	l.forEachFunc(func(pkg *packages.Package, decl *ast.FuncDecl) {
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.ExprStmt:
+				if call, ok := unparenExpr(n.X).(*ast.CallExpr); ok &&
+					isInteropFunc(calleeOf(pkg.TypesInfo, call), "runtime", "CheckWitness") {
+					l.report(LintUncheckedWitness, call.Pos(), "result of runtime.CheckWitness is not checked")
+				}
+			case *ast.AssignStmt:
+				for i := range n.Rhs {
+					call, ok := unparenExpr(n.Rhs[i]).(*ast.CallExpr)
+					if !ok || len(n.Lhs) != len(n.Rhs) ||
+						!isInteropFunc(calleeOf(pkg.TypesInfo, call), "runtime", "CheckWitness") {
+						continue
+					}
+					if id, ok := n.Lhs[i].(*ast.Ident); ok && id.Name == "_" {
+						l.report(LintUncheckedWitness, call.Pos(), "result of runtime.CheckWitness is not checked")
+					}
+				}
+			case *ast.CallExpr:
+				if !isInteropFunc(calleeOf(pkg.TypesInfo, n), "contract", "Call") {
+					return true
+				}
+				if f, ok := callFlags(pkg.TypesInfo, n); ok && f == callflag.All {
+					l.report(LintCallFlagAll, n.Pos(), "contract is called with contract.All flags, "+
+						"restrict them to the ones really needed")
+				}
+			}
+			return true
+		})
+	})
+}
+
+// checkSafeMethods checks that methods marked as safe don't modify storage.
+func (l *linter) checkSafeMethods() {
+	if len(l.options.SafeMethods) == 0 {
+		return
+	}
+	safe := make(map[string]bool, len(l.options.SafeMethods))
+	for _, name := range l.options.SafeMethods {
+		safe[name] = true
+	}
+	for _, f := range l.prog.mainPkg.Syntax {
+		for _, decl := range f.Decls {
+			d, ok := decl.(*ast.FuncDecl)
+			if !ok || d.Body == nil || d.Recv != nil || !d.Name.IsExported() {
+				continue
+			}
+			r, n := utf8.DecodeRuneInString(d.Name.Name)
+			name := string(unicode.ToLower(r)) + d.Name.Name[n:]
+			if !safe[name] {
+				continue
+			}
+			ast.Inspect(d.Body, func(n ast.Node) bool {
+				if call, ok := n.(*ast.CallExpr); ok && l.callEffects(l.prog.mainPkg.TypesInfo, call).writes {
+					l.report(LintSafeStorageWrite, call.Pos(), "method %s is marked as safe, "+
+						"but modifies storage", name)
+				}
+				return true
+			})
+		}
+	}
+}
+
+// checkReentrancy checks that storage is not modified after calls to other
+// contracts within the same function.
+func (l *linter) checkReentrancy() {
+	l.forEachFunc(func(pkg *packages.Package, decl *ast.FuncDecl) {
+		var (
+			extCall  *ast.CallExpr
+			reported bool
+		)
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || reported {
+				return !reported
+			}
+			e := l.callEffects(pkg.TypesInfo, call)
+			if extCall != nil && e.writes && call.Pos() > extCall.End() {
+				p := l.prog.fset.Position(extCall.Pos())
+				l.report(LintReentrancy, call.Pos(), "storage is modified after the call to "+
+					"other contract at line %d, update the state before making calls", p.Line)
+				reported = true
+				return false
+			}
+			if extCall == nil && e.calls {
+				extCall = call
+			}
+			return true
+		})
+	})
+}
+
+// checkFindLoops checks that loops over storage.Find iterators can be left
+// before iterator is exhausted.
+func (l *linter) checkFindLoops() {
+	l.forEachFunc(func(pkg *packages.Package, decl *ast.FuncDecl) {
+		iters := make(map[types.Object]bool)
+		isFind := func(e ast.Expr) bool {
+			call, ok := unparenExpr(e).(*ast.CallExpr)
+			return ok && isInteropFunc(calleeOf(pkg.TypesInfo, call), "storage", "Find")
+		}
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				if len(n.Lhs) != len(n.Rhs) {
+					return true
+				}
+				for i := range n.Lhs {
+					id, ok := n.Lhs[i].(*ast.Ident)
+					if ok && isFind(n.Rhs[i]) {
+						if obj := pkg.TypesInfo.ObjectOf(id); obj != nil {
+							iters[obj] = true
+						}
+					}
+				}
+			case *ast.ValueSpec:
+				if len(n.Names) != len(n.Values) {
+					return true
+				}
+				for i := range n.Names {
+					if isFind(n.Values[i]) {
+						if obj := pkg.TypesInfo.ObjectOf(n.Names[i]); obj != nil {
+							iters[obj] = true
+						}
+					}
+				}
+			case *ast.ForStmt:
+				call, ok := unparenExpr(n.Cond).(*ast.CallExpr)
+				if !ok || len(call.Args) != 1 || !isInteropFunc(calleeOf(pkg.TypesInfo, call), "iterator", "Next") {
+					return true
+				}
+				arg := unparenExpr(call.Args[0])
+				id, ok := arg.(*ast.Ident)
+				if !isFind(arg) && !(ok && iters[pkg.TypesInfo.ObjectOf(id)]) {
+					return true
+				}
+				if !canLeaveLoop(n.Body) {
+					l.report(LintUnboundedFind, n.Pos(), "loop over storage.Find results is "+
+						"not bounded, its cost grows with the storage size")
+				}
+			}
+			return true
+		})
+	})
+}
+
+// canLeaveLoop checks whether loop body contains statements breaking the loop.
+func canLeaveLoop(body *ast.BlockStmt) bool {
+	var found bool
+	var walk func(n ast.Node, nested bool)
+	walk = func(n ast.Node, nested bool) {
+		ast.Inspect(n, func(n ast.Node) bool {
+			if found {
+				return false
+			}
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ReturnStmt:
+				found = true
+			case *ast.BranchStmt:
+				// Labeled break can only refer to this loop or outer ones.
+				found = n.Tok == token.BREAK && (n.Label != nil || !nested) ||
+					n.Tok == token.GOTO
+			case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+				if !nested {
+					for _, c := range childStmts(n) {
+						walk(c, true)
+					}
+					return false
+				}
+			}
+			return true
+		})
+	}
+	walk(body, false)
+	return found
+}
+
+// childStmts returns the bodies of breakable statements.
+func childStmts(n ast.Node) []ast.Node {
+	switch n := n.(type) {
+	case *ast.ForStmt:
+		return []ast.Node{n.Body}
+	case *ast.RangeStmt:
+		return []ast.Node{n.Body}
+	case *ast.SwitchStmt:
+		return []ast.Node{n.Body}
+	case *ast.TypeSwitchStmt:
+		return []ast.Node{n.Body}
+	case *ast.SelectStmt:
+		return []ast.Node{n.Body}
+	}
+	return nil
+}
+
+// storageKey is a storage key used at some position.
+type storageKey struct {
+	// prefix is the constant part of the key.
+	prefix []byte
+	// exact is set if the whole key is constant.
+	exact bool
+	pos   token.Pos
+}
+
+// checkPrefixes checks that constant prefixes of storage keys used for
+// different data don't overlap.
+func (l *linter) checkPrefixes() {
+	var keys []storageKey
+	l.forEachFunc(func(pkg *packages.Package, decl *ast.FuncDecl) {
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) < 2 {
+				return true
+			}
+			fn := calleeOf(pkg.TypesInfo, call)
+			isFind := isInteropFunc(fn, "storage", "Find")
+			if !isFind && !isInteropFunc(fn, "storage", "Put") &&
+				!isInteropFunc(fn, "storage", "Get") && !isInteropFunc(fn, "storage", "Delete") {
+				return true
+			}
+			prefix, exact := l.keyPrefix(pkg.TypesInfo, call.Args[1], 0)
+			if len(prefix) != 0 {
+				keys = append(keys, storageKey{prefix: prefix, exact: exact && !isFind, pos: call.Args[1].Pos()})
+			}
+			return true
+		})
+	})
+
+	// Keys with the same constant part are supposed to be used for the
+	// same data, so only the first occurrence is interesting.
+	var uniq []storageKey
+loop:
+	for _, k := range keys {
+		for _, u := range uniq {
+			if u.exact == k.exact && bytes.Equal(u.prefix, k.prefix) {
+				continue loop
+			}
+		}
+		uniq = append(uniq, k)
+	}
+	for _, a := range uniq {
+		if a.exact {
+			continue
+		}
+		for _, b := range uniq {
+			if (a.exact == b.exact && bytes.Equal(a.prefix, b.prefix)) || !bytes.HasPrefix(b.prefix, a.prefix) {
+				continue
+			}
+			p := l.prog.fset.Position(a.pos)
+			l.report(LintPrefixCollision, b.pos, "storage key %s can collide with keys "+
+				"prefixed by %s used at %s:%d", quoteKey(b.prefix, b.exact), quoteKey(a.prefix, false),
+				p.Filename, p.Line)
+		}
+	}
+}
+
+func quoteKey(key []byte, exact bool) string {
+	var s string
+	if utf8.Valid(key) && strings.IndexFunc(string(key), func(r rune) bool { return !unicode.IsPrint(r) }) == -1 {
+		s = fmt.Sprintf("%q", key)
+	} else {
+		s = fmt.Sprintf("0x%x", key)
+	}
+	if !exact {
+		s += "..."
+	}
+	return s
+}
+
+// maxKeyDepth limits the depth of storage key expression analysis.
+const maxKeyDepth = 8
+
+// keyPrefix returns the constant prefix of storage key expression e and
+// whether the whole key is constant.
+func (l *linter) keyPrefix(info *types.Info, e ast.Expr, depth int) ([]byte, bool) {
+	if depth > maxKeyDepth {
+		return nil, false
+	}
+	e = unparenExpr(e)
+	if tv := info.Types[e]; tv.Value != nil {
+		if tv.Value.Kind() == constant.String {
+			return []byte(constant.StringVal(tv.Value)), true
+		}
+		return nil, false
+	}
+	switch e := e.(type) {
+	case *ast.Ident:
+		obj := info.ObjectOf(e)
+		if v, ok := obj.(*types.Var); ok && obj.Parent() == obj.Pkg().Scope() {
+			if init, ok := l.globals[v]; ok {
+				return l.keyPrefix(l.infoOf(v.Pkg()), init, depth+1)
+			}
+		}
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return nil, false
+		}
+		return l.concatPrefix(info, []ast.Expr{e.X, e.Y}, depth)
+	case *ast.CallExpr:
+		if len(e.Args) == 0 {
+			return nil, false
+		}
+		if info.Types[e.Fun].IsType() {
+			return l.keyPrefix(info, e.Args[0], depth+1)
+		}
+		if id, ok := unparenExpr(e.Fun).(*ast.Ident); ok && id.Name == "append" {
+			if _, ok := info.Uses[id].(*types.Builtin); ok {
+				if e.Ellipsis.IsValid() {
+					return l.concatPrefix(info, e.Args, depth)
+				}
+				var (
+					prefix, exact = l.keyPrefix(info, e.Args[0], depth+1)
+					elems         = l.bytesPrefix(info, e.Args[1:])
+				)
+				if !exact {
+					return prefix, false
+				}
+				return append(prefix, elems...), len(elems) == len(e.Args)-1
+			}
+		}
+	case *ast.CompositeLit:
+		if t, ok := info.Types[e].Type.Underlying().(*types.Slice); ok && isByte(t.Elem()) {
+			elems := l.bytesPrefix(info, e.Elts)
+			return elems, len(elems) == len(e.Elts)
+		}
+	}
+	return nil, false
+}
+
+// concatPrefix returns the constant prefix of the concatenation of es.
+func (l *linter) concatPrefix(info *types.Info, es []ast.Expr, depth int) ([]byte, bool) {
+	var res []byte
+	for _, e := range es {
+		prefix, exact := l.keyPrefix(info, e, depth+1)
+		res = append(res, prefix...)
+		if !exact {
+			return res, false
+		}
+	}
+	return res, true
+}
+
+// bytesPrefix returns the bytes of the longest constant prefix of es.
+func (l *linter) bytesPrefix(info *types.Info, es []ast.Expr) []byte {
+	var res []byte
+	for _, e := range es {
+		if _, ok := e.(*ast.KeyValueExpr); ok {
+			break
+		}
+		value := info.Types[e].Value
+		if value == nil {
+			break
+		}
+		b, ok := constant.Uint64Val(constant.ToInt(value))
+		if !ok || b > 255 {
+			break
+		}
+		res = append(res, byte(b))
+	}
+	return res
+}
+
+func (l *linter) infoOf(pkg *types.Package) *types.Info {
+	return l.prog.program[pkg.Path()].TypesInfo
+}
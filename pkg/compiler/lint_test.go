@@ -0,0 +1,233 @@
+package compiler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func lintSource(t *testing.T, src string, o *Options) []LintIssue {
+	issues, err := Lint("foo.go", strings.NewReader(src), o)
+	require.NoError(t, err)
+	return issues
+}
+
+// lintLines returns lines of issues reported by the check.
+func lintLines(issues []LintIssue, check string) []int {
+	var lines []int
+	for _, i := range issues {
+		if i.Check == check {
+			lines = append(lines, i.Line)
+		}
+	}
+	return lines
+}
+
+func TestLint_UncheckedWitness(t *testing.T) {
+	src := `package foo
+	import "github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+	func Main(owner []byte) int {
+		runtime.CheckWitness(owner)
+		_ = runtime.CheckWitness(owner)
+		if !runtime.CheckWitness(owner) {
+			return 0
+		}
+		ok := runtime.CheckWitness(owner)
+		if !ok {
+			return 0
+		}
+		return 1
+	}`
+	issues := lintSource(t, src, nil)
+	require.Equal(t, []int{4, 5}, lintLines(issues, LintUncheckedWitness))
+	require.Equal(t, 2, len(issues))
+	require.Equal(t, "foo.go:4:3: result of runtime.CheckWitness is not checked (unchecked-witness)", issues[0].String())
+}
+
+func TestLint_SafeStorageWrite(t *testing.T) {
+	src := `package foo
+	import "github.com/nspcc-dev/neo-go/pkg/interop/storage"
+	func Get() []byte {
+		ctx := storage.GetContext()
+		return storage.Get(ctx, "key").([]byte)
+	}
+	func Count() int {
+		inc()
+		return 1
+	}
+	func Put() {
+		storage.Put(storage.GetContext(), "key", 1)
+	}
+	func inc() {
+		storage.Put(storage.GetContext(), "key", 2)
+	}`
+	issues := lintSource(t, src, &Options{SafeMethods: []string{"get", "count"}})
+	require.Equal(t, []int{8}, lintLines(issues, LintSafeStorageWrite))
+
+	issues = lintSource(t, src, nil)
+	require.Equal(t, 0, len(lintLines(issues, LintSafeStorageWrite)))
+}
+
+func TestLint_Reentrancy(t *testing.T) {
+	src := `package foo
+	import (
+		"github.com/nspcc-dev/neo-go/pkg/interop"
+		"github.com/nspcc-dev/neo-go/pkg/interop/contract"
+		"github.com/nspcc-dev/neo-go/pkg/interop/native/gas"
+		"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+		"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+	)
+	func Withdraw(to interop.Hash160, amount int) {
+		ctx := storage.GetContext()
+		gas.Transfer(runtime.GetExecutingScriptHash(), to, amount, nil)
+		storage.Put(ctx, to, 0)
+		storage.Delete(ctx, to)
+	}
+	func Safe(to interop.Hash160, amount int) {
+		storage.Put(storage.GetContext(), to, 0)
+		gas.Transfer(runtime.GetExecutingScriptHash(), to, amount, nil)
+	}
+	func ReadOnly(h interop.Hash160) {
+		contract.Call(h, "balanceOf", contract.ReadStates, h)
+		storage.Put(storage.GetContext(), h, 0)
+	}
+	func Dynamic(h interop.Hash160, f contract.CallFlag) {
+		contract.Call(h, "transfer", f, h)
+		update(h)
+	}
+	func update(h interop.Hash160) {
+		storage.Put(storage.GetContext(), h, 1)
+	}`
+	issues := lintSource(t, src, nil)
+	require.Equal(t, []int{12, 25}, lintLines(issues, LintReentrancy))
+}
+
+func TestLint_CallFlagAll(t *testing.T) {
+	src := `package foo
+	import (
+		"github.com/nspcc-dev/neo-go/pkg/interop"
+		"github.com/nspcc-dev/neo-go/pkg/interop/contract"
+	)
+	const flags = contract.ReadStates | contract.WriteStates | contract.AllowCall | contract.AllowNotify
+	func Main(h interop.Hash160) {
+		contract.Call(h, "a", contract.All)
+		contract.Call(h, "b", flags)
+		contract.Call(h, "c", contract.States)
+	}`
+	issues := lintSource(t, src, nil)
+	require.Equal(t, []int{8, 9}, lintLines(issues, LintCallFlagAll))
+}
+
+func TestLint_UnboundedFind(t *testing.T) {
+	src := `package foo
+	import (
+		"github.com/nspcc-dev/neo-go/pkg/interop/iterator"
+		"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+	)
+	func All() int {
+		ctx := storage.GetContext()
+		it := storage.Find(ctx, "a", storage.KeysOnly)
+		n := 0
+		for iterator.Next(it) {
+			for i := 0; i < 2; i++ {
+				break
+			}
+			switch n {
+			case 1:
+				break
+			}
+			n++
+		}
+		return n
+	}
+	func Limited(limit int) int {
+		var it = storage.Find(storage.GetContext(), "a", storage.KeysOnly)
+		n := 0
+		for iterator.Next(it) {
+			if n == limit {
+				break
+			}
+			n++
+		}
+		return n
+	}
+	func First() interface{} {
+		for it := storage.Find(storage.GetContext(), "a", storage.None); iterator.Next(it); {
+			return iterator.Value(it)
+		}
+		return nil
+	}
+	func Labeled() {
+		it := storage.Find(storage.GetContext(), "a", storage.None)
+	loop:
+		for iterator.Next(it) {
+			switch {
+			case true:
+				break loop
+			}
+		}
+	}
+	func Direct() {
+		for iterator.Next(storage.Find(storage.GetContext(), "a", storage.None)) {
+		}
+	}`
+	issues := lintSource(t, src, nil)
+	require.Equal(t, []int{10, 50}, lintLines(issues, LintUnboundedFind))
+}
+
+func TestLint_PrefixCollision(t *testing.T) {
+	src := `package foo
+	import (
+		"github.com/nspcc-dev/neo-go/pkg/interop"
+		"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+	)
+	const (
+		balancePrefix = "b"
+		bannedKey     = "banned"
+		ownerKey      = "owner"
+	)
+	var tokenPrefix = []byte{0x01}
+	func Balance(h interop.Hash160) int {
+		return storage.Get(storage.GetContext(), balancePrefix+string(h)).(int)
+	}
+	func Banned() bool {
+		return storage.Get(storage.GetContext(), bannedKey).(bool)
+	}
+	func Owner() interop.Hash160 {
+		return storage.Get(storage.GetContext(), ownerKey).(interop.Hash160)
+	}
+	func Token(id []byte) []byte {
+		ctx := storage.GetContext()
+		storage.Put(ctx, append(tokenPrefix, id...), 1)
+		return storage.Get(ctx, append(tokenPrefix, id...)).([]byte)
+	}
+	func TokenCount() []byte {
+		return storage.Get(storage.GetContext(), []byte{0x01, 0x02}).([]byte)
+	}`
+	issues := lintSource(t, src, nil)
+	require.Equal(t, []int{16, 27}, lintLines(issues, LintPrefixCollision))
+	require.True(t, strings.Contains(issues[0].Message, `"banned"`), issues[0].Message)
+	require.True(t, strings.Contains(issues[0].Message, `"b"...`), issues[0].Message)
+	require.True(t, strings.Contains(issues[1].Message, "0x0102"), issues[1].Message)
+}
+
+func TestLint_Clean(t *testing.T) {
+	src := `package foo
+	import (
+		"github.com/nspcc-dev/neo-go/pkg/interop"
+		"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+		"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+	)
+	const ownerKey = "owner"
+	func SetOwner(h interop.Hash160) bool {
+		ctx := storage.GetContext()
+		owner := storage.Get(ctx, ownerKey).(interop.Hash160)
+		if !runtime.CheckWitness(owner) {
+			return false
+		}
+		storage.Put(ctx, ownerKey, h)
+		return true
+	}`
+	require.Equal(t, 0, len(lintSource(t, src, &Options{SafeMethods: []string{"owner"}})))
+}
@@ -0,0 +1,130 @@
+package compiler_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestModule creates contract module depending on local library module
+// and neo-go via replace directives. It returns the path to the contract.
+func newTestModule(t *testing.T, main, lib string) string {
+	root, err := filepath.Abs("../..")
+	require.NoError(t, err)
+	sum, err := ioutil.ReadFile(filepath.Join(root, "go.sum"))
+	require.NoError(t, err)
+
+	d := t.TempDir()
+	files := map[string]string{
+		"lib/go.mod": "module example.com/lib\n",
+		"lib/lib.go": lib,
+		"contract/go.mod": `module example.com/contract
+go 1.22.0
+require (
+	example.com/lib v0.0.0
+	github.com/nspcc-dev/neo-go v0.0.0
+)
+replace example.com/lib => ../lib
+replace github.com/nspcc-dev/neo-go => ` + root + "\n",
+		"contract/go.sum":  string(sum),
+		"contract/main.go": main,
+	}
+	for name, content := range files {
+		name = filepath.Join(d, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), os.ModePerm))
+		require.NoError(t, ioutil.WriteFile(name, []byte(content), 0644))
+	}
+	return filepath.Join(d, "contract")
+}
+
+func TestCompileModule(t *testing.T) {
+	main := `package contract
+	import (
+		"example.com/lib"
+		"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+	)
+	func Main() int {
+		runtime.Log("main")
+		return lib.Sum(lib.Double, 1, 2)
+	}`
+	lib := `package lib
+	func Double(x int) int { return x * 2 }
+	func Sum(f func(int) int, xs ...int) int {
+		var s int
+		for _, x := range xs {
+			s += f(x)
+		}
+		return s
+	}
+	// Unused is not reachable from contract, so it's not compiled.
+	func Unused(ch chan int) {
+		go func() { ch <- 1 }()
+	}`
+	contract := newTestModule(t, main, lib)
+
+	check := func(t *testing.T, name string) {
+		b, di, err := compiler.CompileWithDebugInfo(name, nil)
+		require.NoError(t, err)
+
+		v := vm.New()
+		invokeMethod(t, testMainIdent, b, v, di)
+		v.SyscallHandler = func(*vm.VM, uint32) error { return nil }
+		require.NoError(t, v.Run())
+		require.Equal(t, big.NewInt(6), v.PopResult())
+	}
+	t.Run("directory", func(t *testing.T) {
+		check(t, contract)
+	})
+	t.Run("file", func(t *testing.T) {
+		check(t, filepath.Join(contract, "main.go"))
+	})
+	t.Run("dependencies", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "contract.nef")
+		_, err := compiler.CompileAndSave(contract, &compiler.Options{Outfile: out})
+		require.NoError(t, err)
+		raw, err := ioutil.ReadFile(out)
+		require.NoError(t, err)
+		f, err := nef.FileFromBytes(raw)
+		require.NoError(t, err)
+		_, _, deps, err := compiler.ParseNEFCompiler(f.Compiler)
+		require.NoError(t, err)
+		require.Equal(t, 12, len(deps))
+		require.NoError(t, compiler.VerifySource(contract, &f))
+
+		// Library version change is detected.
+		modFile := filepath.Join(contract, "go.mod")
+		mod, err := ioutil.ReadFile(modFile)
+		require.NoError(t, err)
+		mod = bytes.Replace(mod, []byte("example.com/lib v0.0.0\n"), []byte("example.com/lib v0.0.1\n"), 1)
+		mod = bytes.Replace(mod, []byte("example.com/lib =>"), []byte("example.com/lib v0.0.1 =>"), 1)
+		require.NoError(t, ioutil.WriteFile(modFile, mod, 0644))
+		require.Error(t, compiler.VerifySource(contract, &f))
+	})
+}
+
+func TestCompileModuleUnsupported(t *testing.T) {
+	main := `package contract
+	import "example.com/lib"
+	func Main() int {
+		lib.Unsupported()
+		return 1
+	}`
+	lib := `package lib
+	func Unsupported() {
+		ch := make(chan int)
+		ch <- 1
+	}`
+	contract := newTestModule(t, main, lib)
+
+	_, err := compiler.Compile(contract, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), filepath.Join("lib", "lib.go")+":3:9: channels are not supported")
+}
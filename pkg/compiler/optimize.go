@@ -0,0 +1,945 @@
+package compiler
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// noTarget is used for instructions without jump target.
+const noTarget = -1
+
+// optInstr is a single instruction of the program being optimized. Jump
+// targets are stored as instruction indices, so instructions can be freely
+// added and removed, offsets are only calculated when the program is
+// assembled back.
+type optInstr struct {
+	op    opcode.Opcode
+	param []byte
+	// offset is the offset of the instruction in the original program. New
+	// instructions inherit it from the instructions they replace.
+	offset int
+	// target is the index of the jump, call or catch block target.
+	target int
+	// finally is the index of the finally block target for TRY.
+	finally int
+}
+
+// optimizer performs bytecode-level optimizations of the compiled program:
+//   - peephole rewrites and constant folding;
+//   - jump threading and removal of jumps to the next instruction;
+//   - removal of unreachable code;
+//   - elimination of never read local and static slots;
+//   - using the shortest forms of PUSH* and JMP* instructions.
+type optimizer struct {
+	prog []optInstr
+	// roots contains original offsets of the program entry points.
+	roots []int
+	// locals maps original offset of INITSLOT to the mapping from the
+	// original local slot indices to the new ones (-1 for removed slots).
+	locals map[int][]int
+	// statics is the mapping from the original static slot indices to the
+	// new ones (-1 for removed slots).
+	statics []int
+}
+
+// maxOptimizationRounds limits the number of optimization rounds, every round
+// can open up new possibilities for the next one, but usually it converges
+// in a couple of rounds.
+const maxOptimizationRounds = 16
+
+// newOptimizer decodes the program b with all jump offsets already resolved.
+func newOptimizer(b []byte, roots []int) (*optimizer, error) {
+	o := &optimizer{
+		roots:  roots,
+		locals: make(map[int][]int),
+	}
+	index := make(map[int]int)
+	ctx := vm.NewContext(b)
+	for ctx.NextIP() < len(b) {
+		op, param, err := ctx.Next()
+		if err != nil {
+			return nil, fmt.Errorf("invalid instruction at %d: %w", ctx.IP(), err)
+		}
+		index[ctx.IP()] = len(o.prog)
+		o.prog = append(o.prog, optInstr{
+			op:      op,
+			param:   append([]byte(nil), param...),
+			offset:  ctx.IP(),
+			target:  noTarget,
+			finally: noTarget,
+		})
+	}
+	index[len(b)] = len(o.prog)
+
+	resolve := func(ip, offset int) (int, error) {
+		i, ok := index[ip+offset]
+		if !ok {
+			return noTarget, fmt.Errorf("invalid jump target at %d: %d", ip, ip+offset)
+		}
+		return i, nil
+	}
+	for i := range o.prog {
+		var (
+			in  = &o.prog[i]
+			err error
+		)
+		switch in.op {
+		case opcode.TRY, opcode.TRYL:
+			var catchOffset, finallyOffset int
+			if in.op == opcode.TRY {
+				catchOffset, finallyOffset = int(int8(in.param[0])), int(int8(in.param[1]))
+			} else {
+				catchOffset = int(int32(binary.LittleEndian.Uint32(in.param)))
+				finallyOffset = int(int32(binary.LittleEndian.Uint32(in.param[4:])))
+			}
+			if catchOffset != 0 {
+				in.target, err = resolve(in.offset, catchOffset)
+			}
+			if err == nil && finallyOffset != 0 {
+				in.finally, err = resolve(in.offset, finallyOffset)
+			}
+			in.op, in.param = opcode.TRYL, nil
+		default:
+			if !hasJumpTarget(in.op) {
+				continue
+			}
+			offset := int(int8(in.param[0]))
+			if len(in.param) == 4 {
+				offset = int(int32(binary.LittleEndian.Uint32(in.param)))
+			}
+			in.target, err = resolve(in.offset, offset)
+			in.op, in.param = toLongForm(in.op), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// optimize runs all optimizations until the program stops changing.
+func (o *optimizer) optimize() {
+	for i := 0; i < maxOptimizationRounds; i++ {
+		changed := o.peephole()
+		changed = o.threadJumps() || changed
+		changed = o.removeUnreachable() || changed
+		changed = o.removeUnusedSlots() || changed
+		if !changed {
+			return
+		}
+	}
+}
+
+// index returns the index of the first instruction originating from the
+// offset not less than the specified one.
+func (o *optimizer) index(offset int) int {
+	return sort.Search(len(o.prog), func(i int) bool {
+		return o.prog[i].offset >= offset
+	})
+}
+
+// targets returns a set of instructions that can be jumped to (including the
+// program entry points).
+func (o *optimizer) targets() []bool {
+	res := make([]bool, len(o.prog)+1)
+	for _, r := range o.roots {
+		res[o.index(r)] = true
+	}
+	for _, in := range o.prog {
+		if in.target != noTarget {
+			res[in.target] = true
+		}
+		if in.finally != noTarget {
+			res[in.finally] = true
+		}
+	}
+	return res
+}
+
+// replace sets the new program, remap contains new indices of all old
+// instructions (and of the end of the program).
+func (o *optimizer) replace(prog []optInstr, remap []int) {
+	for i := range prog {
+		if prog[i].target != noTarget {
+			prog[i].target = remap[prog[i].target]
+		}
+		if prog[i].finally != noTarget {
+			prog[i].finally = remap[prog[i].finally]
+		}
+	}
+	o.prog = prog
+}
+
+// peephole performs local rewrites of short instruction sequences.
+func (o *optimizer) peephole() bool {
+	var (
+		targets = o.targets()
+		prog    = make([]optInstr, 0, len(o.prog))
+		remap   = make([]int, len(o.prog)+1)
+		changed bool
+	)
+	for i := 0; i < len(o.prog); {
+		n, repl := o.match(i, targets)
+		if n == 0 {
+			remap[i] = len(prog)
+			prog = append(prog, o.prog[i])
+			i++
+			continue
+		}
+		for j := i; j < i+n; j++ {
+			remap[j] = len(prog)
+		}
+		for j := range repl {
+			repl[j].offset = o.prog[i].offset
+		}
+		prog = append(prog, repl...)
+		i += n
+		changed = true
+	}
+	remap[len(o.prog)] = len(prog)
+	o.replace(prog, remap)
+	return changed
+}
+
+// match checks whether the sequence starting at instruction i can be
+// rewritten. It returns the number of instructions to replace and the
+// replacement. Only the first instruction of the sequence can be a jump
+// target.
+func (o *optimizer) match(i int, targets []bool) (int, []optInstr) {
+	next := func(k int) (optInstr, bool) {
+		if i+k >= len(o.prog) || targets[i+k] {
+			return optInstr{}, false
+		}
+		return o.prog[i+k], true
+	}
+	in := o.prog[i]
+	switch {
+	case in.op == opcode.NOP:
+		return 1, nil
+	case in.op == opcode.INITSLOT && in.param[0] == 0 && in.param[1] == 0,
+		in.op == opcode.INITSSLOT && in.param[0] == 0:
+		return 1, nil
+	case in.target == i+1 && isJumpOp(in.op):
+		switch in.op {
+		case opcode.JMPL:
+			return 1, nil
+		case opcode.JMPIFL, opcode.JMPIFNOTL:
+			return 1, []optInstr{newOptInstr(opcode.DROP)}
+		default:
+			return 1, []optInstr{newOptInstr(opcode.DROP), newOptInstr(opcode.DROP)}
+		}
+	}
+	a, isConst := constValue(in)
+	if isConst {
+		if _, ok := a.(*stackitem.BigInteger); ok {
+			if short := pushItem(a); short.op != in.op {
+				return 1, []optInstr{short}
+			}
+		}
+	}
+
+	second, ok := next(1)
+	if !ok {
+		return 0, nil
+	}
+	switch {
+	case second.op == opcode.DROP && isPureLoad(in.op):
+		return 2, nil
+	case in.op == opcode.SWAP && second.op == opcode.SWAP:
+		return 2, nil
+	case in.op == opcode.SWAP && isCommutative(second.op):
+		return 2, []optInstr{second}
+	case in.op == opcode.NOT && (second.op == opcode.JMPIFL || second.op == opcode.JMPIFNOTL):
+		jmp := second
+		jmp.op = negateJmp(second.op)
+		return 2, []optInstr{jmp}
+	case isConst && (second.op == opcode.JMPIFL || second.op == opcode.JMPIFNOTL):
+		cond, err := a.TryBool()
+		if err != nil {
+			return 0, nil
+		}
+		if cond != (second.op == opcode.JMPIFL) {
+			return 2, nil
+		}
+		jmp := second
+		jmp.op = opcode.JMPL
+		return 2, []optInstr{jmp}
+	case isConst:
+		if res, ok := foldUnary(second.op, a); ok {
+			return 2, []optInstr{pushItem(res)}
+		}
+	}
+
+	third, ok := next(2)
+	if !ok {
+		return 0, nil
+	}
+	if third.op == opcode.SWAP && isPureLoad(in.op) && isPureLoad(second.op) &&
+		!isStackDependent(in.op) && !isStackDependent(second.op) {
+		return 3, []optInstr{second, in}
+	}
+	if !isConst {
+		return 0, nil
+	}
+	if b, ok := constValue(second); ok {
+		if res, ok := foldBinary(third.op, a, b); ok {
+			return 3, []optInstr{pushItem(res)}
+		}
+	}
+	return 0, nil
+}
+
+// threadJumps retargets jumps leading to unconditional jumps and replaces
+// unconditional jumps to RET with RET.
+func (o *optimizer) threadJumps() bool {
+	var changed bool
+	for i := range o.prog {
+		in := &o.prog[i]
+		if !isJumpOp(in.op) {
+			continue
+		}
+		t := in.target
+		visited := map[int]bool{i: true}
+		for t < len(o.prog) && o.prog[t].op == opcode.JMPL && !visited[t] {
+			visited[t] = true
+			t = o.prog[t].target
+		}
+		if t < len(o.prog) && visited[t] {
+			continue // Infinite loop, leave it as is.
+		}
+		if t != in.target {
+			in.target = t
+			changed = true
+		}
+		if in.op == opcode.JMPL && t < len(o.prog) && o.prog[t].op == opcode.RET {
+			in.op, in.target = opcode.RET, noTarget
+			changed = true
+		}
+	}
+	return changed
+}
+
+// removeUnreachable removes instructions that can't be reached from any of
+// the program entry points.
+func (o *optimizer) removeUnreachable() bool {
+	var (
+		reachable = make([]bool, len(o.prog))
+		stack     []int
+	)
+	push := func(i int) {
+		if i != noTarget && i < len(o.prog) && !reachable[i] {
+			reachable[i] = true
+			stack = append(stack, i)
+		}
+	}
+	for _, r := range o.roots {
+		push(o.index(r))
+	}
+	for len(stack) != 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		in := o.prog[i]
+		switch in.op {
+		case opcode.RET, opcode.THROW, opcode.ABORT, opcode.ENDFINALLY:
+		case opcode.JMPL, opcode.ENDTRYL:
+			push(in.target)
+		default:
+			push(in.target)
+			push(in.finally)
+			push(i + 1)
+		}
+	}
+
+	var (
+		prog  = make([]optInstr, 0, len(o.prog))
+		remap = make([]int, len(o.prog)+1)
+	)
+	for i := range o.prog {
+		remap[i] = len(prog)
+		if reachable[i] {
+			prog = append(prog, o.prog[i])
+		}
+	}
+	remap[len(o.prog)] = len(prog)
+	if len(prog) == len(o.prog) {
+		return false
+	}
+	o.replace(prog, remap)
+	return true
+}
+
+// removeUnusedSlots replaces stores to never read local and static slots with
+// DROP and renumbers the remaining slots.
+func (o *optimizer) removeUnusedSlots() bool {
+	var (
+		changed bool
+		frames  []int
+	)
+	for i := range o.prog {
+		if o.prog[i].op == opcode.INITSLOT {
+			frames = append(frames, i)
+		}
+	}
+	// Every function using local slots starts with INITSLOT and functions
+	// are never interleaved, so the frame lasts until the next INITSLOT.
+	for k, start := range frames {
+		end := len(o.prog)
+		if k+1 < len(frames) {
+			end = frames[k+1]
+		}
+		init := &o.prog[start]
+		remap := o.compactSlots(start+1, end, int(init.param[0]),
+			opcode.LDLOC0, opcode.LDLOC, opcode.STLOC0, opcode.STLOC)
+		if remap != nil {
+			init.param = []byte{byte(countSlots(remap)), init.param[1]}
+			o.locals[init.offset] = composeSlots(o.locals[init.offset], remap)
+			changed = true
+		}
+	}
+	for i := range o.prog {
+		if o.prog[i].op == opcode.INITSSLOT {
+			init := &o.prog[i]
+			remap := o.compactSlots(0, len(o.prog), int(init.param[0]),
+				opcode.LDSFLD0, opcode.LDSFLD, opcode.STSFLD0, opcode.STSFLD)
+			if remap != nil {
+				init.param = []byte{byte(countSlots(remap))}
+				o.statics = composeSlots(o.statics, remap)
+				changed = true
+			}
+			break
+		}
+	}
+	return changed
+}
+
+// compactSlots removes slots which are never read in the [start, end)
+// instruction range. It returns nil if there is nothing to remove.
+func (o *optimizer) compactSlots(start, end, count int, ld0, ld, st0, st opcode.Opcode) []int {
+	loaded := make([]bool, count)
+	for i := start; i < end; i++ {
+		if idx, ok := slotIndex(o.prog[i], ld0, ld); ok {
+			if idx >= count {
+				return nil
+			}
+			loaded[idx] = true
+		}
+	}
+	var (
+		remap = make([]int, count)
+		n     int
+	)
+	for i := range remap {
+		remap[i] = noTarget
+		if loaded[i] {
+			remap[i] = n
+			n++
+		}
+	}
+	if n == count {
+		return nil
+	}
+	for i := start; i < end; i++ {
+		in := &o.prog[i]
+		if idx, ok := slotIndex(*in, ld0, ld); ok {
+			setSlotIndex(in, ld0, ld, remap[idx])
+		} else if idx, ok := slotIndex(*in, st0, st); ok {
+			if idx >= count {
+				continue
+			}
+			if remap[idx] == noTarget {
+				in.op, in.param = opcode.DROP, nil
+			} else {
+				setSlotIndex(in, st0, st, remap[idx])
+			}
+		}
+	}
+	return remap
+}
+
+// assemble encodes the program using the shortest possible jumps. It returns
+// the resulting bytecode and new offsets of all instructions (and of the end
+// of the program).
+func (o *optimizer) assemble() ([]byte, []int) {
+	var (
+		short   = make([]bool, len(o.prog))
+		sizes   = make([]int, len(o.prog))
+		offsets = make([]int, len(o.prog)+1)
+	)
+	for i, in := range o.prog {
+		if in.target == noTarget && in.finally == noTarget && in.op != opcode.TRYL {
+			w := io.NewBufBinWriter()
+			o.encode(w.BinWriter, i, nil, false)
+			sizes[i] = w.Len()
+		}
+	}
+	// Shortening a jump can only decrease distances between instructions, so
+	// the process converges.
+	for changed := true; changed; {
+		for i, in := range o.prog {
+			switch {
+			case in.op == opcode.TRYL && short[i]:
+				sizes[i] = 3
+			case in.op == opcode.TRYL:
+				sizes[i] = 9
+			case in.target == noTarget:
+			case in.op == opcode.PUSHA || !short[i]:
+				sizes[i] = 5
+			default:
+				sizes[i] = 2
+			}
+			offsets[i+1] = offsets[i] + sizes[i]
+		}
+		changed = false
+		for i, in := range o.prog {
+			if short[i] || in.op == opcode.PUSHA || in.target == noTarget && in.op != opcode.TRYL {
+				continue
+			}
+			if fitsInt8(offsets, i, in.target) && fitsInt8(offsets, i, in.finally) {
+				short[i] = true
+				changed = true
+			}
+		}
+	}
+	w := io.NewBufBinWriter()
+	for i := range o.prog {
+		o.encode(w.BinWriter, i, offsets, short[i])
+	}
+	return w.Bytes(), offsets
+}
+
+// encode writes instruction i to w using the specified offsets to calculate
+// jump targets.
+func (o *optimizer) encode(w *io.BinWriter, i int, offsets []int, short bool) {
+	in := o.prog[i]
+	rel := func(t int) int {
+		if t == noTarget {
+			return 0
+		}
+		return offsets[t] - offsets[i]
+	}
+	switch {
+	case in.op == opcode.TRYL && short:
+		emit.Instruction(w, opcode.TRY, []byte{byte(rel(in.target)), byte(rel(in.finally))})
+	case in.op == opcode.TRYL:
+		param := make([]byte, 8)
+		binary.LittleEndian.PutUint32(param, uint32(rel(in.target)))
+		binary.LittleEndian.PutUint32(param[4:], uint32(rel(in.finally)))
+		emit.Instruction(w, opcode.TRYL, param)
+	case in.target != noTarget && short:
+		emit.Instruction(w, toShortForm(in.op), []byte{byte(rel(in.target))})
+	case in.target != noTarget:
+		param := make([]byte, 4)
+		binary.LittleEndian.PutUint32(param, uint32(rel(in.target)))
+		emit.Instruction(w, in.op, param)
+	case in.op == opcode.PUSHDATA1 || in.op == opcode.PUSHDATA2 || in.op == opcode.PUSHDATA4:
+		emit.Bytes(w, in.param)
+	default:
+		emit.Instruction(w, in.op, in.param)
+	}
+}
+
+func fitsInt8(offsets []int, i, target int) bool {
+	if target == noTarget {
+		return true
+	}
+	offset := offsets[target] - offsets[i]
+	return math.MinInt8 <= offset && offset <= math.MaxInt8
+}
+
+func newOptInstr(op opcode.Opcode) optInstr {
+	return optInstr{op: op, target: noTarget, finally: noTarget}
+}
+
+// pushItem returns an instruction pushing the constant item.
+func pushItem(item stackitem.Item) optInstr {
+	switch it := item.(type) {
+	case stackitem.Bool:
+		if it {
+			return newOptInstr(opcode.PUSHT)
+		}
+		return newOptInstr(opcode.PUSHF)
+	default:
+		n := it.Value().(*big.Int)
+		if n.IsInt64() && -1 <= n.Int64() && n.Int64() <= 16 {
+			return newOptInstr(opcode.Opcode(int64(opcode.PUSH0) + n.Int64()))
+		}
+		w := io.NewBufBinWriter()
+		emit.BigInt(w.BinWriter, n)
+		b := w.Bytes()
+		in := newOptInstr(opcode.Opcode(b[0]))
+		if len(b) > 1 {
+			in.param = b[1:]
+		}
+		return in
+	}
+}
+
+// constValue returns the item pushed by the instruction if it's a constant
+// integer, boolean or null.
+func constValue(in optInstr) (stackitem.Item, bool) {
+	switch {
+	case in.op == opcode.PUSHT:
+		return stackitem.NewBool(true), true
+	case in.op == opcode.PUSHF:
+		return stackitem.NewBool(false), true
+	case in.op == opcode.PUSHNULL:
+		return stackitem.Null{}, true
+	case opcode.PUSHM1 <= in.op && in.op <= opcode.PUSH16:
+		return stackitem.NewBigInteger(big.NewInt(int64(in.op) - int64(opcode.PUSH0))), true
+	case in.op <= opcode.PUSHINT256:
+		return stackitem.NewBigInteger(bigint.FromBytes(in.param)), true
+	}
+	return nil, false
+}
+
+// foldUnary evaluates unary operation over the constant item.
+func foldUnary(op opcode.Opcode, item stackitem.Item) (stackitem.Item, bool) {
+	if op == opcode.NOT {
+		b, err := item.TryBool()
+		return stackitem.NewBool(!b), err == nil
+	}
+	a, err := item.TryInteger()
+	if err != nil {
+		return nil, false
+	}
+	res := new(big.Int)
+	switch op {
+	case opcode.NEGATE:
+		res.Neg(a)
+	case opcode.INC:
+		res.Add(a, big.NewInt(1))
+	case opcode.DEC:
+		res.Sub(a, big.NewInt(1))
+	case opcode.ABS:
+		res.Abs(a)
+	case opcode.SIGN:
+		res.SetInt64(int64(a.Sign()))
+	case opcode.NZ:
+		return stackitem.NewBool(a.Sign() != 0), true
+	default:
+		return nil, false
+	}
+	return intItem(res)
+}
+
+// foldBinary evaluates binary operation over the constant items.
+func foldBinary(op opcode.Opcode, x1, x2 stackitem.Item) (stackitem.Item, bool) {
+	if op == opcode.BOOLAND || op == opcode.BOOLOR {
+		a, err1 := x1.TryBool()
+		b, err2 := x2.TryBool()
+		if err1 != nil || err2 != nil {
+			return nil, false
+		}
+		if op == opcode.BOOLAND {
+			return stackitem.NewBool(a && b), true
+		}
+		return stackitem.NewBool(a || b), true
+	}
+	a, err1 := x1.TryInteger()
+	b, err2 := x2.TryInteger()
+	if err1 != nil || err2 != nil {
+		return nil, false
+	}
+	res := new(big.Int)
+	switch op {
+	case opcode.ADD:
+		res.Add(a, b)
+	case opcode.SUB:
+		res.Sub(a, b)
+	case opcode.MUL:
+		res.Mul(a, b)
+	case opcode.DIV, opcode.MOD:
+		if b.Sign() == 0 {
+			return nil, false // Leave the runtime exception in place.
+		}
+		if op == opcode.DIV {
+			res.Quo(a, b)
+		} else {
+			res.Rem(a, b)
+		}
+	case opcode.AND:
+		res.And(a, b)
+	case opcode.OR:
+		res.Or(a, b)
+	case opcode.XOR:
+		res.Xor(a, b)
+	case opcode.MIN, opcode.MAX:
+		res.Set(a)
+		if (a.Cmp(b) > 0) == (op == opcode.MIN) {
+			res.Set(b)
+		}
+	case opcode.NUMEQUAL:
+		return stackitem.NewBool(a.Cmp(b) == 0), true
+	case opcode.NUMNOTEQUAL:
+		return stackitem.NewBool(a.Cmp(b) != 0), true
+	case opcode.LT:
+		return stackitem.NewBool(a.Cmp(b) < 0), true
+	case opcode.LE:
+		return stackitem.NewBool(a.Cmp(b) <= 0), true
+	case opcode.GT:
+		return stackitem.NewBool(a.Cmp(b) > 0), true
+	case opcode.GE:
+		return stackitem.NewBool(a.Cmp(b) >= 0), true
+	default:
+		return nil, false
+	}
+	return intItem(res)
+}
+
+// intItem returns integer item if it fits into VM limits.
+func intItem(n *big.Int) (stackitem.Item, bool) {
+	if len(bigint.ToBytes(n)) > stackitem.MaxBigIntegerSizeBits/8 {
+		return nil, false
+	}
+	return stackitem.NewBigInteger(n), true
+}
+
+// isPureLoad returns true if op pushes a single item onto the stack without
+// any other side-effects, so it can be removed along with the following DROP.
+func isPureLoad(op opcode.Opcode) bool {
+	switch {
+	case op <= opcode.PUSH16,
+		opcode.LDSFLD0 <= op && op <= opcode.LDSFLD,
+		opcode.LDLOC0 <= op && op <= opcode.LDLOC,
+		opcode.LDARG0 <= op && op <= opcode.LDARG:
+		return true
+	}
+	switch op {
+	case opcode.DUP, opcode.OVER, opcode.NEWMAP, opcode.NEWARRAY0, opcode.NEWSTRUCT0:
+		return true
+	}
+	return false
+}
+
+// isStackDependent returns true if the item pushed by op depends on the stack
+// contents.
+func isStackDependent(op opcode.Opcode) bool {
+	return op == opcode.DUP || op == opcode.OVER
+}
+
+// isCommutative returns true if the result of op doesn't depend on the order
+// of its arguments.
+func isCommutative(op opcode.Opcode) bool {
+	switch op {
+	case opcode.ADD, opcode.MUL, opcode.AND, opcode.OR, opcode.XOR,
+		opcode.MIN, opcode.MAX, opcode.NUMEQUAL, opcode.NUMNOTEQUAL,
+		opcode.BOOLAND, opcode.BOOLOR:
+		return true
+	}
+	return false
+}
+
+// hasJumpTarget returns true if op has a single relative offset parameter.
+func hasJumpTarget(op opcode.Opcode) bool {
+	switch op {
+	case opcode.CALL, opcode.CALLL, opcode.ENDTRY, opcode.ENDTRYL, opcode.PUSHA:
+		return true
+	}
+	return isJumpOp(op)
+}
+
+// isJumpOp returns true if op is JMP* instruction.
+func isJumpOp(op opcode.Opcode) bool {
+	return opcode.JMP <= op && op <= opcode.JMPLEL
+}
+
+func toLongForm(op opcode.Opcode) opcode.Opcode {
+	switch op {
+	case opcode.JMP, opcode.JMPIF, opcode.JMPIFNOT,
+		opcode.JMPEQ, opcode.JMPNE, opcode.JMPGT, opcode.JMPGE, opcode.JMPLT, opcode.JMPLE,
+		opcode.CALL, opcode.ENDTRY:
+		return op + 1
+	default:
+		return op
+	}
+}
+
+// slotIndex returns slot index if instruction is a short (op0) or long (op)
+// form of the slot instruction.
+func slotIndex(in optInstr, op0, op opcode.Opcode) (int, bool) {
+	switch {
+	case op0 <= in.op && in.op < op:
+		return int(in.op - op0), true
+	case in.op == op:
+		return int(in.param[0]), true
+	}
+	return 0, false
+}
+
+// setSlotIndex changes slot index of the instruction using the shortest form.
+func setSlotIndex(in *optInstr, op0, op opcode.Opcode, idx int) {
+	if idx < int(op-op0) {
+		in.op, in.param = op0+opcode.Opcode(idx), nil
+	} else {
+		in.op, in.param = op, []byte{byte(idx)}
+	}
+}
+
+func countSlots(remap []int) int {
+	var n int
+	for _, i := range remap {
+		if i != noTarget {
+			n++
+		}
+	}
+	return n
+}
+
+func composeSlots(prev, next []int) []int {
+	if prev == nil {
+		return next
+	}
+	res := make([]int, len(prev))
+	for i, idx := range prev {
+		res[i] = noTarget
+		if idx != noTarget {
+			res[i] = next[idx]
+		}
+	}
+	return res
+}
+
+// remapDebugVariables updates slot indices of variables in the
+// `name,type,index` format. Variables with removed slots are dropped.
+func remapDebugVariables(vars []string, remap []int) []string {
+	if remap == nil {
+		return vars
+	}
+	res := vars[:0]
+	for _, v := range vars {
+		i := strings.LastIndexByte(v, ',')
+		idx, err := strconv.Atoi(v[i+1:])
+		if err == nil && idx < len(remap) {
+			if remap[idx] == noTarget {
+				continue
+			}
+			v = v[:i+1] + strconv.Itoa(remap[idx])
+		}
+		res = append(res, v)
+	}
+	return res
+}
+
+// optimize performs bytecode optimizations of the program b with all jump
+// offsets resolved (but not yet shortened). It updates function ranges,
+// sequence points and debug variables accordingly.
+func (c *codegen) optimize(b []byte) ([]byte, error) {
+	roots := []int{0}
+	if c.deployEndOffset >= 0 {
+		roots = append(roots, c.initEndOffset+1)
+	}
+	for _, f := range c.funcs {
+		roots = append(roots, int(f.rng.Start))
+	}
+	o, err := newOptimizer(b, roots)
+	if err != nil {
+		return nil, err
+	}
+	o.optimize()
+	if c.initEndOffset > 0 && o.prog[0].op == opcode.RET && o.index(c.initEndOffset+1) == 1 {
+		// Nothing is left to initialize.
+		remap := make([]int, len(o.prog)+1)
+		for i := range remap {
+			remap[i] = i - 1
+		}
+		o.replace(o.prog[1:], remap)
+		c.initEndOffset = -1
+	}
+	buf, offsets := o.assemble()
+
+	// New offset of the first instruction originating from the offset
+	// not less than the specified one.
+	start := func(offset int) int {
+		return offsets[o.index(offset)]
+	}
+	// New offset of the last instruction originating from the offset not
+	// greater than the specified one.
+	end := func(offset int) int {
+		i := o.index(offset + 1)
+		if i == 0 {
+			return 0
+		}
+		return offsets[i-1]
+	}
+	locals := func(start, end int) []int {
+		for off, remap := range o.locals {
+			if start <= off && off <= end {
+				return remap
+			}
+		}
+		return nil
+	}
+
+	// Original code of a statement spans from its sequence point up to the
+	// next sequence point or the end of the method.
+	var bounds []int
+	for _, points := range c.sequencePoints {
+		for _, p := range points {
+			bounds = append(bounds, p.Opcode)
+		}
+	}
+	for _, f := range c.funcs {
+		bounds = append(bounds, int(f.rng.Start), int(f.rng.End)+1)
+	}
+	if c.initEndOffset > 0 {
+		bounds = append(bounds, c.initEndOffset+1)
+	}
+	if c.deployEndOffset >= 0 {
+		bounds = append(bounds, c.deployEndOffset+1)
+	}
+	sort.Ints(bounds)
+
+	c.staticVariables = remapDebugVariables(c.staticVariables, o.statics)
+	for _, f := range c.funcs {
+		f.variables = remapDebugVariables(f.variables, locals(int(f.rng.Start), int(f.rng.End)))
+		newStart, newEnd := start(int(f.rng.Start)), end(int(f.rng.End))
+		if newEnd <= newStart {
+			if f.rng.Start != f.rng.End {
+				if c.singleInstrFuncs == nil {
+					c.singleInstrFuncs = make(map[*funcScope]bool)
+				}
+				c.singleInstrFuncs[f] = true
+			}
+			newEnd = newStart
+		}
+		f.rng.Start, f.rng.End = uint16(newStart), uint16(newEnd)
+	}
+	if c.deployEndOffset >= 0 {
+		c.deployVariables = remapDebugVariables(c.deployVariables, locals(c.initEndOffset+1, c.deployEndOffset))
+		c.deployEndOffset = end(c.deployEndOffset)
+	}
+	if c.initEndOffset > 0 {
+		c.initVariables = remapDebugVariables(c.initVariables, locals(0, c.initEndOffset))
+		c.initEndOffset = end(c.initEndOffset)
+	}
+	// Sequence points are moved to the first instruction left from their
+	// statements, points of statements without any code left are dropped.
+	for name, points := range c.sequencePoints {
+		res := points[:0]
+		for _, p := range points {
+			limit := len(b)
+			if j := sort.SearchInts(bounds, p.Opcode+1); j < len(bounds) {
+				limit = bounds[j]
+			}
+			i := o.index(p.Opcode)
+			if i < len(o.prog) && o.prog[i].offset < limit {
+				p.Opcode = offsets[i]
+				res = append(res, p)
+			}
+		}
+		c.sequencePoints[name] = res
+	}
+	return buf, nil
+}
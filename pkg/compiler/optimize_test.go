@@ -0,0 +1,262 @@
+package compiler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+)
+
+func testOptimize(t *testing.T, before, after func(w *io.BinWriter)) {
+	bw := io.NewBufBinWriter()
+	before(bw.BinWriter)
+	require.NoError(t, bw.Err)
+	o, err := newOptimizer(bw.Bytes(), []int{0})
+	require.NoError(t, err)
+	o.optimize()
+	actual, _ := o.assemble()
+
+	bw.Reset()
+	after(bw.BinWriter)
+	require.NoError(t, bw.Err)
+	require.Equal(t, bw.Bytes(), actual)
+}
+
+func TestOptimizer(t *testing.T) {
+	t.Run("constant folding", func(t *testing.T) {
+		testOptimize(t, func(w *io.BinWriter) {
+			emit.Int(w, 2)
+			emit.Int(w, 3)
+			emit.Opcodes(w, opcode.ADD)
+			emit.Int(w, 100)
+			emit.Opcodes(w, opcode.MUL, opcode.NEGATE, opcode.RET)
+		}, func(w *io.BinWriter) {
+			emit.Int(w, -500)
+			emit.Opcodes(w, opcode.RET)
+		})
+	})
+	t.Run("no division by zero", func(t *testing.T) {
+		testOptimize(t, func(w *io.BinWriter) {
+			emit.Opcodes(w, opcode.PUSH1, opcode.PUSH0, opcode.DIV, opcode.RET)
+		}, func(w *io.BinWriter) {
+			emit.Opcodes(w, opcode.PUSH1, opcode.PUSH0, opcode.DIV, opcode.RET)
+		})
+	})
+	t.Run("push shortening", func(t *testing.T) {
+		testOptimize(t, func(w *io.BinWriter) {
+			emit.Instruction(w, opcode.PUSHINT32, []byte{5, 0, 0, 0})
+			emit.Opcodes(w, opcode.RET)
+		}, func(w *io.BinWriter) {
+			emit.Opcodes(w, opcode.PUSH5, opcode.RET)
+		})
+	})
+	t.Run("constant condition", func(t *testing.T) {
+		testOptimize(t, func(w *io.BinWriter) {
+			emit.Opcodes(w, opcode.PUSHT)
+			emit.Instruction(w, opcode.JMPIFL, []byte{8, 0, 0, 0})
+			emit.Opcodes(w, opcode.PUSH1, opcode.PUSH2, opcode.RET)
+			emit.Opcodes(w, opcode.PUSH3, opcode.RET)
+		}, func(w *io.BinWriter) {
+			emit.Opcodes(w, opcode.PUSH3, opcode.RET)
+		})
+	})
+	t.Run("negated condition", func(t *testing.T) {
+		testOptimize(t, func(w *io.BinWriter) {
+			emit.Opcodes(w, opcode.LDARG0, opcode.NOT)
+			emit.Instruction(w, opcode.JMPIFL, []byte{7, 0, 0, 0})
+			emit.Opcodes(w, opcode.PUSH1, opcode.RET)
+			emit.Opcodes(w, opcode.PUSH2, opcode.RET)
+		}, func(w *io.BinWriter) {
+			emit.Opcodes(w, opcode.LDARG0)
+			emit.Instruction(w, opcode.JMPIFNOT, []byte{4})
+			emit.Opcodes(w, opcode.PUSH1, opcode.RET)
+			emit.Opcodes(w, opcode.PUSH2, opcode.RET)
+		})
+	})
+	t.Run("jump to next instruction", func(t *testing.T) {
+		testOptimize(t, func(w *io.BinWriter) {
+			emit.Opcodes(w, opcode.LDARG0, opcode.LDARG1)
+			emit.Instruction(w, opcode.JMPEQL, []byte{5, 0, 0, 0})
+			emit.Opcodes(w, opcode.NOP, opcode.PUSH1, opcode.RET)
+		}, func(w *io.BinWriter) {
+			emit.Opcodes(w, opcode.PUSH1, opcode.RET)
+		})
+	})
+	t.Run("jump threading", func(t *testing.T) {
+		testOptimize(t, func(w *io.BinWriter) {
+			emit.Opcodes(w, opcode.LDARG0)
+			emit.Instruction(w, opcode.JMPIFL, []byte{7, 0, 0, 0})
+			emit.Opcodes(w, opcode.PUSH1, opcode.RET)
+			emit.Instruction(w, opcode.JMPL, []byte{5, 0, 0, 0})
+			emit.Opcodes(w, opcode.PUSH2, opcode.RET)
+		}, func(w *io.BinWriter) {
+			emit.Opcodes(w, opcode.LDARG0)
+			emit.Instruction(w, opcode.JMPIF, []byte{4})
+			emit.Opcodes(w, opcode.PUSH1, opcode.RET)
+			emit.Opcodes(w, opcode.PUSH2, opcode.RET)
+		})
+	})
+	t.Run("jump to return", func(t *testing.T) {
+		testOptimize(t, func(w *io.BinWriter) {
+			emit.Opcodes(w, opcode.PUSH1)
+			emit.Instruction(w, opcode.JMPL, []byte{7, 0, 0, 0})
+			emit.Opcodes(w, opcode.PUSH2, opcode.RET)
+			emit.Opcodes(w, opcode.RET)
+		}, func(w *io.BinWriter) {
+			emit.Opcodes(w, opcode.PUSH1, opcode.RET)
+		})
+	})
+	t.Run("unused locals", func(t *testing.T) {
+		testOptimize(t, func(w *io.BinWriter) {
+			emit.Instruction(w, opcode.INITSLOT, []byte{3, 1})
+			emit.Opcodes(w, opcode.PUSH1, opcode.STLOC0)
+			emit.Opcodes(w, opcode.LDARG0, opcode.STLOC1)
+			emit.Opcodes(w, opcode.PUSH3, opcode.STLOC2)
+			emit.Opcodes(w, opcode.LDLOC2, opcode.RET)
+		}, func(w *io.BinWriter) {
+			emit.Instruction(w, opcode.INITSLOT, []byte{1, 1})
+			emit.Opcodes(w, opcode.PUSH3, opcode.STLOC0)
+			emit.Opcodes(w, opcode.LDLOC0, opcode.RET)
+		})
+	})
+	t.Run("swapped loads", func(t *testing.T) {
+		testOptimize(t, func(w *io.BinWriter) {
+			emit.Opcodes(w, opcode.LDARG0, opcode.LDARG1, opcode.SWAP, opcode.SUB, opcode.RET)
+		}, func(w *io.BinWriter) {
+			emit.Opcodes(w, opcode.LDARG1, opcode.LDARG0, opcode.SUB, opcode.RET)
+		})
+	})
+	t.Run("try block", func(t *testing.T) {
+		testOptimize(t, func(w *io.BinWriter) {
+			emit.Instruction(w, opcode.TRYL, []byte{15, 0, 0, 0, 0, 0, 0, 0})
+			emit.Opcodes(w, opcode.PUSH1)
+			emit.Instruction(w, opcode.ENDTRYL, []byte{11, 0, 0, 0})
+			emit.Opcodes(w, opcode.PUSH2)
+			emit.Instruction(w, opcode.ENDTRYL, []byte{5, 0, 0, 0})
+			emit.Opcodes(w, opcode.RET)
+		}, func(w *io.BinWriter) {
+			emit.Instruction(w, opcode.TRY, []byte{6, 0})
+			emit.Opcodes(w, opcode.PUSH1)
+			emit.Instruction(w, opcode.ENDTRY, []byte{5})
+			emit.Opcodes(w, opcode.PUSH2)
+			emit.Instruction(w, opcode.ENDTRY, []byte{2})
+			emit.Opcodes(w, opcode.RET)
+		})
+	})
+}
+
+func TestOptimizeContract(t *testing.T) {
+	src := `package foo
+	var unused = 42
+	const debug = false
+	func Main(n int) int {
+		a, b := 2*3, 0
+		s := 0
+		for i := 0; i < n; i++ {
+			if debug {
+				s += b
+			}
+			s = add(s, i*a)
+		}
+		return s
+	}
+	func add(x, y int) int {
+		if x < 0 {
+			panic("negative")
+		}
+		return x + y
+	}`
+
+	run := func(t *testing.T, optimize bool) ([]byte, *DebugInfo, int64) {
+		b, di, err := CompileWithOptions("foo.go", strings.NewReader(src), &Options{Optimize: optimize})
+		require.NoError(t, err)
+
+		v := vm.New()
+		v.LoadScriptWithFlags(b, callflag.All)
+		for _, m := range di.Methods {
+			if m.ID == "Main" {
+				v.Jump(v.Context(), int(m.Range.Start))
+			}
+		}
+		v.Estack().PushVal(10)
+		require.NoError(t, v.Run())
+		require.Equal(t, 1, v.Estack().Len())
+		return b, di, v.Estack().Pop().BigInt().Int64()
+	}
+
+	plain, _, expected := run(t, false)
+	b, di, actual := run(t, true)
+	require.Equal(t, int64(270), expected)
+	require.Equal(t, expected, actual)
+	require.True(t, len(b) < len(plain), "optimized: %d, plain: %d", len(b), len(plain))
+
+	t.Run("debug info", func(t *testing.T) {
+		boundaries := make(map[int]bool)
+		ctx := vm.NewContext(b)
+		for ctx.NextIP() < len(b) {
+			boundaries[ctx.NextIP()] = true
+			_, _, err := ctx.Next()
+			require.NoError(t, err)
+		}
+		for _, m := range di.Methods {
+			require.True(t, boundaries[int(m.Range.Start)], m.ID)
+			require.True(t, boundaries[int(m.Range.End)], m.ID)
+			require.True(t, len(m.SeqPoints) > 0, m.ID)
+			for _, p := range m.SeqPoints {
+				require.True(t, boundaries[p.Opcode], "%s: %d", m.ID, p.Opcode)
+				require.True(t, int(m.Range.Start) <= p.Opcode && p.Opcode <= int(m.Range.End))
+			}
+			if m.ID == manifest.MethodInit {
+				t.Fatal("empty initialization method is emitted")
+			}
+		}
+	})
+}
+
+func TestOptimizeSequencePoints(t *testing.T) {
+	src := `package foo
+	var a, b int
+	func Main(n int) int {
+		a = 1
+		a, b = 7, n*2
+		return b
+	}`
+	lines := func(optimize bool) map[int]int {
+		b, di, err := CompileWithOptions("foo.go", strings.NewReader(src), &Options{Optimize: optimize})
+		require.NoError(t, err)
+		var m *MethodDebugInfo
+		for i := range di.Methods {
+			if di.Methods[i].ID == "Main" {
+				m = &di.Methods[i]
+			}
+		}
+		require.NotNil(t, m)
+		res := make(map[int]int)
+		for _, p := range m.SeqPoints {
+			require.True(t, int(m.Range.Start) <= p.Opcode && p.Opcode <= int(m.Range.End))
+			res[p.StartLine] = p.Opcode
+		}
+		ctx := vm.NewContext(b)
+		for ctx.NextIP() < res[5] {
+			_, _, err := ctx.Next()
+			require.NoError(t, err)
+		}
+		require.Equal(t, res[5], ctx.NextIP())
+		return res
+	}
+
+	require.Len(t, lines(false), 3)
+	// Store to the unused static is removed completely, while the statement
+	// on line 5 still has code for `b`.
+	opt := lines(true)
+	require.Len(t, opt, 2)
+	require.Contains(t, opt, 5)
+	require.Contains(t, opt, 6)
+	require.True(t, opt[5] < opt[6])
+}
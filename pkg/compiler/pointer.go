@@ -0,0 +1,216 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// Pointers are mapped onto VM reference types. Structures are reference
+// types already, so pointer to a structure is the structure itself (it's
+// stored as an array to avoid cloning when it's used as a value of other
+// structure or array). Pointer to a value of any other type is a box, the
+// same one-element array that is used for variables captured by closures
+// (see closure.go). Variables having their address taken are stored in
+// boxes for all their lifetime, so that the pointer and the variable refer
+// to the same value.
+
+// analyzeAddressTaken finds all variables of non-struct types having their
+// address taken either explicitly or via method calls with pointer receivers.
+func (c *codegen) analyzeAddressTaken() {
+	c.ForEachFile(func(f *ast.File, _ *types.Package) {
+		ast.Inspect(f, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.UnaryExpr:
+				if n.Op == token.AND {
+					c.markAddressTaken(n.X)
+				}
+			case *ast.SelectorExpr:
+				sel := c.typeInfo.Selections[n]
+				if sel == nil || sel.Kind() != types.MethodVal {
+					return true
+				}
+				recv := sel.Obj().(*types.Func).Type().(*types.Signature).Recv()
+				if isBoxPointer(recv.Type()) && !isPointer(c.typeOf(n.X)) {
+					c.markAddressTaken(n.X)
+				}
+			}
+			return true
+		})
+	})
+}
+
+// markAddressTaken marks variable referred to by e as a boxed one if needed.
+func (c *codegen) markAddressTaken(e ast.Expr) {
+	var id *ast.Ident
+	switch t := unparenExpr(e).(type) {
+	case *ast.Ident:
+		id = t
+	case *ast.SelectorExpr:
+		id = t.Sel
+	default:
+		return
+	}
+	v, ok := c.typeInfo.Uses[id].(*types.Var)
+	if ok && !v.IsField() && !isStruct(v.Type()) {
+		c.boxedVars[v] = true
+	}
+}
+
+// boxResults allocates boxes for the named results of the current function
+// that need them.
+func (c *codegen) boxResults(decl *ast.FuncDecl) {
+	if decl.Type.Results == nil {
+		return
+	}
+	for _, field := range decl.Type.Results.List {
+		for _, id := range field.Names {
+			if !c.isBoxed(id) {
+				continue
+			}
+			c.scope.newLocal(id.Name)
+			c.emitNewBox(id.Name)
+			c.emitDefault(c.typeOf(field.Type))
+			c.emitStoreVar("", id.Name)
+		}
+	}
+}
+
+// isPointer checks whether typ is a pointer type.
+func isPointer(typ types.Type) bool {
+	_, ok := typ.Underlying().(*types.Pointer)
+	return ok
+}
+
+// isStruct checks whether typ is a struct type.
+func isStruct(typ types.Type) bool {
+	_, ok := typ.Underlying().(*types.Struct)
+	return ok
+}
+
+// isBoxPointer checks whether typ is a pointer to a value stored in a box.
+func isBoxPointer(typ types.Type) bool {
+	t, ok := typ.Underlying().(*types.Pointer)
+	return ok && !isStruct(t.Elem())
+}
+
+// emitNew emits a pointer to the new zero value of type typ.
+func (c *codegen) emitNew(typ types.Type) {
+	if strct, ok := typ.Underlying().(*types.Struct); ok {
+		for i := strct.NumFields() - 1; i >= 0; i-- {
+			c.emitDefault(strct.Field(i).Type())
+		}
+		emit.Int(c.prog.BinWriter, int64(strct.NumFields()))
+		emit.Opcodes(c.prog.BinWriter, opcode.PACK)
+		return
+	}
+	c.emitDefault(typ)
+	emit.Opcodes(c.prog.BinWriter, opcode.PUSH1, opcode.PACK)
+}
+
+// emitAddressOf emits a pointer to the value of e.
+func (c *codegen) emitAddressOf(e ast.Expr) {
+	e = unparenExpr(e)
+	if lit, ok := e.(*ast.CompositeLit); ok {
+		if isStruct(c.typeOf(lit)) {
+			c.convertStruct(lit, true)
+		} else {
+			ast.Walk(c, lit)
+			emit.Opcodes(c.prog.BinWriter, opcode.PUSH1, opcode.PACK)
+		}
+		return
+	}
+	if isStruct(c.typeOf(e)) {
+		// Structures are reference types.
+		ast.Walk(c, e)
+		return
+	}
+
+	var (
+		pkg  string
+		name string
+	)
+	switch t := e.(type) {
+	case *ast.StarExpr:
+		// &*p is p.
+		ast.Walk(c, t.X)
+		return
+	case *ast.Ident:
+		name = t.Name
+	case *ast.SelectorExpr:
+		id, ok := t.X.(*ast.Ident)
+		if !ok || c.typeOf(t.X) != nil {
+			c.prog.Err = fmt.Errorf("taking address of non-struct field %s is not supported", t.Sel.Name)
+			return
+		}
+		// Global variable from other package.
+		pkg, name = id.Name, t.Sel.Name
+	default:
+		c.prog.Err = fmt.Errorf("'&' can't be used with %T, only variables, structs and literals are supported", e)
+		return
+	}
+	vi := c.getVarIndex(pkg, name)
+	if !vi.boxed || vi.ctx != nil {
+		c.prog.Err = fmt.Errorf("can't take address of variable %s", name)
+		return
+	}
+	c.emitLoadByIndex(vi.refType, vi.index)
+}
+
+// emitDeref replaces the pointer of type typ on top of the stack with the
+// value it points to.
+func (c *codegen) emitDeref(typ types.Type) {
+	if isBoxPointer(typ) {
+		emit.Opcodes(c.prog.BinWriter, opcode.PUSH0, opcode.PICKITEM)
+		return
+	}
+	c.emitConvert(stackitem.StructT)
+}
+
+// emitStoreDeref stores the value from the top of the stack by pointer p.
+func (c *codegen) emitStoreDeref(p ast.Expr) {
+	ast.Walk(c, p)
+	if isBoxPointer(c.typeOf(p)) {
+		c.emitStoreBox()
+		return
+	}
+	// Copy all fields of the structure to the one pointer refers to.
+	strct, _ := c.getStruct(c.typeOf(p))
+	for i := 0; i < strct.NumFields(); i++ {
+		emit.Opcodes(c.prog.BinWriter, opcode.DUP)
+		emit.Int(c.prog.BinWriter, int64(i))
+		emit.Int(c.prog.BinWriter, 3)
+		emit.Opcodes(c.prog.BinWriter, opcode.PICK)
+		emit.Int(c.prog.BinWriter, int64(i))
+		emit.Opcodes(c.prog.BinWriter, opcode.PICKITEM, opcode.SETITEM)
+	}
+	emit.Opcodes(c.prog.BinWriter, opcode.DROP, opcode.DROP)
+}
+
+// emitStoreBox stores the value from the second stack item in the box on top
+// of the stack.
+func (c *codegen) emitStoreBox() {
+	emit.Opcodes(c.prog.BinWriter, opcode.SWAP, opcode.PUSH0, opcode.SWAP, opcode.SETITEM)
+}
+
+// emitReceiver loads the receiver of the method call, taking its address or
+// dereferencing it if needed.
+func (c *codegen) emitReceiver(fun *ast.SelectorExpr) {
+	sel := c.typeInfo.Selections[fun]
+	recv := sel.Obj().(*types.Func).Type().(*types.Signature).Recv()
+	typ := c.typeOf(fun.X)
+	switch {
+	case isBoxPointer(recv.Type()) && !isPointer(typ):
+		c.emitAddressOf(fun.X)
+	case !isPointer(recv.Type()) && isBoxPointer(typ):
+		ast.Walk(c, fun.X)
+		c.emitDeref(typ)
+	default:
+		ast.Walk(c, fun.X)
+	}
+}
@@ -2,7 +2,11 @@ package compiler_test
 
 import (
 	"math/big"
+	"strings"
 	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAddressOfLiteral(t *testing.T) {
@@ -66,3 +70,224 @@ func TestStructArgCopy(t *testing.T) {
 		eval(t, src, big.NewInt(3))
 	})
 }
+
+func TestNew(t *testing.T) {
+	t.Run("Int", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			p := new(int)
+			*p = 5
+			*p += 2
+			*p++
+			return *p
+		}`
+		eval(t, src, big.NewInt(8))
+	})
+	t.Run("Default", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			p := new(string)
+			b := new(bool)
+			if *b {
+				return 0
+			}
+			return len(*p) + 1
+		}`
+		eval(t, src, big.NewInt(1))
+	})
+	t.Run("Struct", func(t *testing.T) {
+		src := `package foo
+		type Foo struct { A int; B string }
+		func Main() int {
+			p := new(Foo)
+			setA(p, 3)
+			return p.A + len(p.B)
+		}
+		func setA(f *Foo, a int) { f.A = a }`
+		eval(t, src, big.NewInt(3))
+	})
+	t.Run("Slice", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			p := new([]int)
+			*p = append(*p, 1, 2)
+			return len(*p)
+		}`
+		eval(t, src, big.NewInt(2))
+	})
+}
+
+func TestAddressOfVariable(t *testing.T) {
+	t.Run("Local", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			x := 1
+			p := &x
+			*p = 10
+			x++
+			return *p
+		}`
+		eval(t, src, big.NewInt(11))
+	})
+	t.Run("Redeclared", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			x := 1
+			p := &x
+			x, y := 2, 3
+			return *p*10 + y
+		}`
+		eval(t, src, big.NewInt(23))
+	})
+	t.Run("Argument", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			x := 1
+			inc(&x)
+			inc(&x)
+			return get(x)
+		}
+		func inc(p *int) { *p++ }
+		func get(x int) int {
+			p := &x
+			*p *= 2
+			return x
+		}`
+		eval(t, src, big.NewInt(6))
+	})
+	t.Run("Global", func(t *testing.T) {
+		src := `package foo
+		var g = 2
+		func Main() int {
+			p := &g
+			*p = 7
+			return g
+		}`
+		eval(t, src, big.NewInt(7))
+	})
+	t.Run("NamedResult", func(t *testing.T) {
+		src := `package foo
+		func Main() (r int) {
+			p := &r
+			*p = 4
+			return
+		}`
+		eval(t, src, big.NewInt(4))
+	})
+	t.Run("Closure", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			x := 1
+			f := func() { x++ }
+			p := &x
+			f()
+			*p += 10
+			return x
+		}`
+		eval(t, src, big.NewInt(12))
+	})
+	t.Run("SliceOfPointers", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			var ps []*int
+			a, b := 1, 2
+			ps = append(ps, &a, &b)
+			*ps[0] = 10
+			return a + *ps[1]
+		}`
+		eval(t, src, big.NewInt(12))
+	})
+	t.Run("StructField", func(t *testing.T) {
+		src := `package foo
+		type Foo struct { P *int }
+		func Main() int {
+			x := 1
+			f := Foo{P: &x}
+			*f.P = 5
+			return x
+		}`
+		eval(t, src, big.NewInt(5))
+	})
+	t.Run("PointerToSlice", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			s := []int{1}
+			add(&s, 2)
+			p := &*&s
+			return len(s) + len(*p)
+		}
+		func add(s *[]int, x int) { *s = append(*s, x) }`
+		eval(t, src, big.NewInt(4))
+	})
+	t.Run("Struct", func(t *testing.T) {
+		src := `package foo
+		type Foo struct { A int; B int }
+		func Main() int {
+			f := Foo{A: 1, B: 2}
+			p := &f
+			p.A = 3
+			*p = Foo{A: f.A * 2, B: 5}
+			return f.A + f.B
+		}`
+		eval(t, src, big.NewInt(11))
+	})
+	t.Run("Nil", func(t *testing.T) {
+		src := `package foo
+		func Main() bool {
+			var p *int
+			return p == nil
+		}`
+		eval(t, src, true)
+	})
+	t.Run("FieldError", func(t *testing.T) {
+		src := `package foo
+		type Foo struct { A int }
+		func Main() int {
+			f := Foo{}
+			p := &f.A
+			return *p
+		}`
+		_, err := compiler.Compile("foo.go", strings.NewReader(src))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "taking address of non-struct field A is not supported")
+	})
+	t.Run("ElementError", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			a := []int{1}
+			p := &a[0]
+			return *p
+		}`
+		_, err := compiler.Compile("foo.go", strings.NewReader(src))
+		require.Error(t, err)
+	})
+}
+
+func TestPointerReceiver(t *testing.T) {
+	src := `package foo
+	type Counter int
+	func (c *Counter) Inc() { *c++ }
+	func (c Counter) Get() int { return int(c) }
+	func Main() int {
+		var c Counter
+		c.Inc()
+		c.Inc()
+		p := &c
+		p.Inc()
+		return p.Get() + c.Get()
+	}`
+	eval(t, src, big.NewInt(6))
+}
+
+func TestStructPointerMethod(t *testing.T) {
+	src := `package foo
+	type Foo struct { A int }
+	func (f *Foo) Inc() { f.A += 1 }
+	func (f Foo) Get() int { return f.A }
+	func Main() int {
+		f := &Foo{A: 1}
+		f.Inc()
+		return f.Get()
+	}`
+	eval(t, src, big.NewInt(2))
+}
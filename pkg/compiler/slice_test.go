@@ -498,14 +498,36 @@ func TestMake(t *testing.T) {
 		}`
 		eval(t, src, big.NewInt(10))
 	})
-	t.Run("CapacityError", func(t *testing.T) {
+	t.Run("Capacity", func(t *testing.T) {
 		src := `package foo
 		func Main() int {
 			a := make([]int, 1, 2)
-			return a[0]
+			a = append(a, 5)
+			return len(a) + a[1]
 		}`
-		_, err := compiler.Compile("foo.go", strings.NewReader(src))
-		require.Error(t, err)
+		eval(t, src, big.NewInt(7))
+	})
+	t.Run("ByteSliceCapacity", func(t *testing.T) {
+		src := `package foo
+		func Main() []byte {
+			n := 10
+			a := make([]byte, 0, getCap(&n))
+			return append(a, byte(n))
+		}
+		func getCap(n *int) int {
+			*n++
+			return *n
+		}`
+		eval(t, src, []byte{11})
+	})
+	t.Run("MapCapacity", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			a := make(map[int]int, 10)
+			a[1] = 10
+			return len(a)
+		}`
+		eval(t, src, big.NewInt(1))
 	})
 }
 
@@ -0,0 +1,162 @@
+package compiler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"golang.org/x/tools/go/packages"
+)
+
+// nefCompilerPrefix is a prefix of NEF Compiler field for contracts compiled
+// by neo-go, it's followed by the compiler version.
+const nefCompilerPrefix = "neo-go-"
+
+// optimizeFlag marks contracts compiled with optimizations enabled, it
+// matches the corresponding `contract compile` flag.
+const optimizeFlag = "--optimize"
+
+// depsPrefix is a prefix of NEF Compiler field part containing dependencies
+// digest.
+const depsPrefix = "deps="
+
+// depsDigestSize is the number of digest bytes stored in NEF Compiler field,
+// it's limited by the field size.
+const depsDigestSize = 6
+
+// NEFCompiler returns the value of NEF Compiler field for contract compiled
+// with the given options and dependencies digest (see VerifySource). Besides
+// the compiler version it contains all options affecting the resulting
+// bytecode and versions of modules used, so that the contract can be rebuilt
+// later.
+func NEFCompiler(o *Options, deps string) string {
+	s := nefCompilerPrefix + config.Version
+	if o != nil && o.Optimize {
+		s += " " + optimizeFlag
+	}
+	if len(deps) != 0 {
+		s += " " + depsPrefix + deps
+	}
+	return s
+}
+
+// ParseNEFCompiler parses NEF Compiler field produced by NEFCompiler and
+// returns compiler version along with the options contract was compiled with
+// and its dependencies digest (if any).
+func ParseNEFCompiler(s string) (string, *Options, string, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], nefCompilerPrefix) {
+		return "", nil, "", fmt.Errorf("contract is not compiled by neo-go: %q", s)
+	}
+	var (
+		o    = new(Options)
+		deps string
+	)
+	for _, f := range fields[1:] {
+		switch {
+		case f == optimizeFlag:
+			o.Optimize = true
+		case strings.HasPrefix(f, depsPrefix):
+			deps = strings.TrimPrefix(f, depsPrefix)
+		default:
+			return "", nil, "", fmt.Errorf("unknown compiler option: %s", f)
+		}
+	}
+	return strings.TrimPrefix(fields[0], nefCompilerPrefix), o, deps, nil
+}
+
+// depsDigest returns a short digest of versions of all modules except the
+// main one the contract packages belong to. It's empty if there are no such
+// modules. Modules replaced with local directories are only identified by
+// their paths, their contents is not covered by the digest.
+func depsDigest(program map[string]*packages.Package) string {
+	var mods = make(map[string]bool)
+	for _, p := range program {
+		m := p.Module
+		if m == nil || m.Main {
+			continue
+		}
+		s := m.Path + " " + m.Version
+		if m.Replace != nil {
+			s += " => " + m.Replace.Path + " " + m.Replace.Version
+		}
+		mods[s] = true
+	}
+	if len(mods) == 0 {
+		return ""
+	}
+	var list = make([]string, 0, len(mods))
+	for s := range mods {
+		list = append(list, s)
+	}
+	sort.Strings(list)
+	h := sha256.Sum256([]byte(strings.Join(list, "\n")))
+	return hex.EncodeToString(h[:depsDigestSize])
+}
+
+// newNEF creates NEF file for the script compiled with the given options and
+// dependencies.
+func newNEF(b []byte, o *Options, deps string) (*nef.File, error) {
+	f, err := nef.NewFile(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(o.SourceURL) > nef.MaxSourceURLLength {
+		return nil, errors.New("too long source URL")
+	}
+	f.Compiler = NEFCompiler(o, deps)
+	f.Source = o.SourceURL
+	if _, err := f.Bytes(); err != nil {
+		return nil, err
+	}
+	f.Checksum = f.CalculateChecksum()
+	return f, nil
+}
+
+// VerifySource compiles the contract from src (file or directory) with the
+// options stored in f and checks that the resulting NEF file is the same as f.
+// The contract must be compiled by the same neo-go version as the one in use,
+// because bytecode generated by different versions can differ. Dependencies
+// of the contract must have the same versions it was compiled with.
+func VerifySource(src string, f *nef.File) error {
+	version, o, deps, err := ParseNEFCompiler(f.Compiler)
+	if err != nil {
+		return err
+	}
+	if version != config.Version {
+		return fmt.Errorf("contract is compiled by neo-go %s, but current version is %s", version, config.Version)
+	}
+	if len(f.Tokens) != 0 {
+		return errors.New("method tokens are not supported by neo-go compiler")
+	}
+	o.SourceURL = f.Source
+	b, _, actualDeps, err := compileWithDeps(src, nil, o)
+	if err != nil {
+		return fmt.Errorf("can't compile contract: %w", err)
+	}
+	if actualDeps != deps {
+		return fmt.Errorf("dependencies mismatch: contract is compiled with %q, but current ones are %q", deps, actualDeps)
+	}
+	if !bytes.Equal(b, f.Script) {
+		i := 0
+		for i < len(b) && i < len(f.Script) && b[i] == f.Script[i] {
+			i++
+		}
+		return fmt.Errorf("script mismatch: expected %d bytes, got %d bytes, first difference at offset %d",
+			len(f.Script), len(b), i)
+	}
+	res, err := newNEF(b, o, deps)
+	if err != nil {
+		return fmt.Errorf("can't create NEF file: %w", err)
+	}
+	if res.Checksum != f.Checksum {
+		return fmt.Errorf("checksum mismatch: expected %d, got %d", f.Checksum, res.Checksum)
+	}
+	return nil
+}
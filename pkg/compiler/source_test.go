@@ -0,0 +1,89 @@
+package compiler_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNEFCompiler(t *testing.T) {
+	for _, o := range []compiler.Options{{}, {Optimize: true}} {
+		for _, deps := range []string{"", "0123456789ab"} {
+			s := compiler.NEFCompiler(&o, deps)
+			version, actual, actualDeps, err := compiler.ParseNEFCompiler(s)
+			require.NoError(t, err)
+			require.Equal(t, config.Version, version)
+			require.Equal(t, o.Optimize, actual.Optimize)
+			require.Equal(t, deps, actualDeps)
+		}
+	}
+	require.Equal(t, "neo-go-"+config.Version+" --optimize", compiler.NEFCompiler(&compiler.Options{Optimize: true}, ""))
+	require.Equal(t, "neo-go-"+config.Version+" deps=0123456789ab", compiler.NEFCompiler(nil, "0123456789ab"))
+
+	_, _, _, err := compiler.ParseNEFCompiler("neon-3.0.0")
+	require.Error(t, err)
+	_, _, _, err = compiler.ParseNEFCompiler("neo-go-0.97.3 --unknown")
+	require.Error(t, err)
+}
+
+func TestVerifySource(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "verify.go")
+	require.NoError(t, ioutil.WriteFile(src, []byte(`package verify
+		func Main(a int) int { return a + 1 }`), 0644))
+
+	out := filepath.Join(dir, "verify.nef")
+	_, err := compiler.CompileAndSave(src, &compiler.Options{
+		Outfile:   out,
+		Optimize:  true,
+		SourceURL: "https://example.com/verify",
+	})
+	require.NoError(t, err)
+	raw, err := ioutil.ReadFile(out)
+	require.NoError(t, err)
+	f, err := nef.FileFromBytes(raw)
+	require.NoError(t, err)
+	// Contract doesn't belong to any module, so there are no dependencies.
+	require.Equal(t, compiler.NEFCompiler(&compiler.Options{Optimize: true}, ""), f.Compiler)
+	require.Equal(t, "https://example.com/verify", f.Source)
+
+	require.NoError(t, compiler.VerifySource(src, &f))
+	require.NoError(t, compiler.VerifySource(dir, &f))
+
+	t.Run("different options", func(t *testing.T) {
+		bad := f
+		bad.Compiler = compiler.NEFCompiler(nil, "")
+		require.Error(t, compiler.VerifySource(src, &bad))
+	})
+	t.Run("different dependencies", func(t *testing.T) {
+		bad := f
+		bad.Compiler = compiler.NEFCompiler(&compiler.Options{Optimize: true}, "0123456789ab")
+		require.Error(t, compiler.VerifySource(src, &bad))
+	})
+	t.Run("different version", func(t *testing.T) {
+		bad := f
+		bad.Compiler = "neo-go-0.0.1"
+		require.Error(t, compiler.VerifySource(src, &bad))
+	})
+	t.Run("bad checksum", func(t *testing.T) {
+		bad := f
+		bad.Checksum++
+		require.Error(t, compiler.VerifySource(src, &bad))
+	})
+	t.Run("method tokens", func(t *testing.T) {
+		bad := f
+		bad.Tokens = []nef.MethodToken{{Hash: util.Uint160{1, 2, 3}, Method: "method"}}
+		require.Error(t, compiler.VerifySource(src, &bad))
+	})
+	t.Run("different source", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(src, []byte(`package verify
+		func Main(a int) int { return a + 2 }`), 0644))
+		require.Error(t, compiler.VerifySource(src, &f))
+	})
+}
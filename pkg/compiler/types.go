@@ -9,13 +9,34 @@ import (
 
 func (c *codegen) typeAndValueOf(e ast.Expr) types.TypeAndValue {
 	for i := len(c.pkgInfoInline) - 1; i >= 0; i-- {
-		if tv, ok := c.pkgInfoInline[i].Types[e]; ok {
+		if tv, ok := c.pkgInfoInline[i].TypesInfo.Types[e]; ok {
 			return tv
 		}
 	}
 	return c.typeInfo.Types[e]
 }
 
+// objectOf returns object denoted by the identifier id.
+func (c *codegen) objectOf(id *ast.Ident) types.Object {
+	for i := len(c.pkgInfoInline) - 1; i >= 0; i-- {
+		if obj := c.pkgInfoInline[i].TypesInfo.ObjectOf(id); obj != nil {
+			return obj
+		}
+	}
+	return c.typeInfo.ObjectOf(id)
+}
+
+// isNewVar checks whether the identifier declares a new variable, it's false
+// for variables redeclared in the short variable declaration.
+func (c *codegen) isNewVar(id *ast.Ident) bool {
+	for i := len(c.pkgInfoInline) - 1; i >= 0; i-- {
+		if c.pkgInfoInline[i].TypesInfo.Defs[id] != nil {
+			return true
+		}
+	}
+	return c.typeInfo.Defs[id] != nil
+}
+
 func (c *codegen) typeOf(e ast.Expr) types.Type {
 	return c.typeAndValueOf(e).Type
 }
@@ -37,6 +58,11 @@ func isMap(typ types.Type) bool {
 	return ok
 }
 
+func isChan(typ types.Type) bool {
+	_, ok := typ.Underlying().(*types.Chan)
+	return ok
+}
+
 func isByte(typ types.Type) bool {
 	return isBasicTypeOfKind(typ, types.Uint8, types.Int8)
 }
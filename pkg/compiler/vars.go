@@ -23,6 +23,9 @@ type varInfo struct {
 	// ctx is set for inline arguments and contains
 	// context for expression traversal.
 	ctx *varContext
+	// boxed is set for variables captured by closures or having their
+	// address taken, such variables are stored in one-element arrays.
+	boxed bool
 }
 
 const unspecifiedVarIndex = -1
@@ -49,6 +52,17 @@ func (c *varScope) addAlias(name string, vt varType, index int, ctx *varContext)
 	}
 }
 
+// setBoxed marks the variable with the specified name as a boxed one.
+func (c *varScope) setBoxed(name string) {
+	for i := len(c.locals) - 1; i >= 0; i-- {
+		if vi, ok := c.locals[i][name]; ok {
+			vi.boxed = true
+			c.locals[i][name] = vi
+			return
+		}
+	}
+}
+
 func (c *varScope) getVarInfo(name string) *varInfo {
 	for i := len(c.locals) - 1; i >= 0; i-- {
 		if vi, ok := c.locals[i][name]; ok {
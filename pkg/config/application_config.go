@@ -28,6 +28,9 @@ type ApplicationConfiguration struct {
 	Oracle            OracleConfiguration     `yaml:"Oracle"`
 	P2PNotary         P2PNotary               `yaml:"P2PNotary"`
 	StateRoot         StateRoot               `yaml:"StateRoot"`
+	BloomFilter       BloomFilter             `yaml:"BloomFilter"`
+	ConsensusMonitor  ConsensusMonitor        `yaml:"ConsensusMonitor"`
+	P2PEncryption     P2PEncryption           `yaml:"P2PEncryption"`
 	// ExtensiblePoolSize is the maximum amount of the extensible payloads from a single sender.
 	ExtensiblePoolSize int `yaml:"ExtensiblePoolSize"`
 }
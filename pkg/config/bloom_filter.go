@@ -0,0 +1,9 @@
+package config
+
+// BloomFilter contains configuration for bloom filters loaded by light (SPV)
+// clients via P2P filterload command.
+type BloomFilter struct {
+	Enabled bool `yaml:"Enabled"`
+	// MaxSize is the maximum filter size in bytes.
+	MaxSize int `yaml:"MaxSize"`
+}
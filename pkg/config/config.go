@@ -51,6 +51,7 @@ func LoadFile(configPath string) (Config, error) {
 			PingInterval: 30,
 			PingTimeout:  90,
 			RPC: rpc.Config{
+				MaxBatchWorkers:        4,
 				MaxIteratorResultItems: 100,
 				MaxFindResultItems:     100,
 				MaxNEP11Tokens:         100,
@@ -0,0 +1,10 @@
+package config
+
+// ConsensusMonitor contains configuration for consensus rounds monitoring.
+// Nodes without UnlockWallet configured follow consensus in watch-only mode
+// if it's enabled.
+type ConsensusMonitor struct {
+	Enabled bool `yaml:"Enabled"`
+	// HistorySize is the number of finished consensus rounds kept.
+	HistorySize int `yaml:"HistorySize"`
+}
@@ -0,0 +1,14 @@
+package config
+
+// P2PEncryption contains configuration for encrypted and authenticated P2P
+// connections. Nodes use TLS with certificates made of their keys, so all
+// nodes in the network must have it enabled.
+type P2PEncryption struct {
+	Enabled bool `yaml:"Enabled"`
+	// UnlockWallet contains node key, the first account that can be
+	// decrypted with the password is used.
+	UnlockWallet Wallet `yaml:"UnlockWallet"`
+	// AllowedPeers is a list of hex-encoded public keys of nodes allowed to
+	// connect, any node is allowed if it's empty.
+	AllowedPeers []string `yaml:"AllowedPeers"`
+}
@@ -36,6 +36,11 @@ type (
 		MaxValidUntilBlockIncrement uint32 `yaml:"MaxValidUntilBlockIncrement"`
 		// NativeUpdateHistories is the list of histories of native contracts updates.
 		NativeUpdateHistories map[string][]uint32 `yaml:"NativeActivations"`
+		// NotificationIndex enables contract notifications index that allows
+		// to find notifications of the specified contract in the specified
+		// range of blocks. This value should remain the same for the same
+		// database.
+		NotificationIndex bool `yaml:"NotificationIndex"`
 		// P2PSigExtensions enables additional signature-related logic.
 		P2PSigExtensions bool `yaml:"P2PSigExtensions"`
 		// P2PStateExchangeExtensions enables additional P2P MPT state data exchange logic.
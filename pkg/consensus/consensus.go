@@ -12,6 +12,7 @@ import (
 	"github.com/nspcc-dev/dbft/payload"
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/consensus/monitor"
 	coreb "github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/blockchainer"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
@@ -101,8 +102,12 @@ type Config struct {
 	RequestTx func(h ...util.Uint256)
 	// TimePerBlock minimal time that should pass before next block is accepted.
 	TimePerBlock time.Duration
-	// Wallet is a local-node wallet configuration.
+	// Wallet is a local-node wallet configuration. If it's not set, the
+	// service follows consensus in watch-only mode when Monitor is set and
+	// does nothing otherwise.
 	Wallet *config.Wallet
+	// Monitor is an optional consensus rounds tracker.
+	Monitor *monitor.Monitor
 }
 
 // NewService returns new consensus.Service instance.
@@ -244,9 +249,17 @@ func (s *service) newPrepareRequest() payload.PrepareRequest {
 
 func (s *service) Start() {
 	if s.started.CAS(false, true) {
+		if s.dbft == nil {
+			s.log.Info("starting consensus service in watch-only mode")
+			s.Chain.SubscribeForBlocks(s.blockEvents)
+			s.trackBlock(s.Chain.BlockHeight())
+			go s.watchLoop()
+			return
+		}
 		s.log.Info("starting consensus service")
 		s.dbft.Start()
 		s.Chain.SubscribeForBlocks(s.blockEvents)
+		s.trackBlock(s.Chain.BlockHeight())
 		go s.eventLoop()
 	}
 }
@@ -313,7 +326,89 @@ events:
 	close(s.finished)
 }
 
+// watchLoop is an event loop used in watch-only mode, consensus payloads are
+// tracked by OnPayload directly.
+func (s *service) watchLoop() {
+events:
+	for {
+		select {
+		case <-s.quit:
+			break events
+		case b := <-s.blockEvents:
+			s.trackBlock(b.Index)
+		}
+	}
+	close(s.finished)
+}
+
+// trackBlock notifies Monitor (if any) about new block.
+func (s *service) trackBlock(index uint32) {
+	if s.Monitor == nil {
+		return
+	}
+	s.Monitor.OnBlock(index, convertKeys(s.getValidators()))
+}
+
+// trackPayload notifies Monitor (if any) about consensus payload.
+func (s *service) trackPayload(p *Payload) {
+	if s.Monitor == nil {
+		return
+	}
+	var (
+		typ    monitor.MessageType
+		reason string
+	)
+	switch p.Type() {
+	case payload.ChangeViewType:
+		typ = monitor.ChangeView
+		reason = p.GetChangeView().Reason().String()
+	case payload.PrepareRequestType:
+		typ = monitor.PrepareRequest
+	case payload.PrepareResponseType:
+		typ = monitor.PrepareResponse
+	case payload.CommitType:
+		typ = monitor.Commit
+	case payload.RecoveryRequestType:
+		typ = monitor.RecoveryRequest
+	case payload.RecoveryMessageType:
+		typ = monitor.RecoveryMessage
+	default:
+		return
+	}
+	s.Monitor.OnMessage(p.Height(), p.ViewNumber(), p.ValidatorIndex(), typ, reason)
+	if typ == monitor.RecoveryMessage {
+		s.trackRecovery(p)
+	}
+}
+
+// trackRecovery notifies Monitor about messages contained in RecoveryMessage
+// payload as if they were received along with it. ChangeView reasons are not
+// included into RecoveryMessage, so they're left empty.
+func (s *service) trackRecovery(p *Payload) {
+	m := p.GetRecoveryMessage().(*recoveryMessage)
+	for _, cv := range m.changeViewPayloads {
+		s.Monitor.OnMessage(p.Height(), cv.OriginalViewNumber, uint16(cv.ValidatorIndex), monitor.ChangeView, "")
+	}
+	if len(m.preparationPayloads) != 0 {
+		var primary = -1
+		if n := int64(len(s.getValidators())); n != 0 {
+			primary = int(((int64(p.Height())-int64(p.ViewNumber()))%n + n) % n)
+		}
+		for _, prep := range m.preparationPayloads {
+			typ := monitor.PrepareResponse
+			if int(prep.ValidatorIndex) == primary {
+				typ = monitor.PrepareRequest
+			}
+			s.Monitor.OnMessage(p.Height(), p.ViewNumber(), uint16(prep.ValidatorIndex), typ, "")
+		}
+	}
+	for _, c := range m.commitPayloads {
+		s.Monitor.OnMessage(p.Height(), c.ViewNumber, uint16(c.ValidatorIndex), monitor.Commit, "")
+	}
+}
+
 func (s *service) handleChainBlock(b *coreb.Block) {
+	s.trackBlock(b.Index)
 	// We can get our own block here, so check for index.
 	if b.Index >= s.dbft.BlockIndex {
 		s.log.Debug("new block in the chain",
@@ -383,11 +478,15 @@ func (s *service) OnPayload(cp *npayload.Extensible) {
 		return
 	}
 
-	if s.dbft == nil || !s.started.Load() {
+	if !s.started.Load() {
 		log.Debug("dbft is inactive or not started yet")
 		return
 	}
 
+	s.trackPayload(p)
+	if s.dbft == nil { // Watch-only mode.
+		return
+	}
 	s.messages <- *p
 }
 
@@ -402,6 +501,7 @@ func (s *service) broadcast(p payload.ConsensusPayload) {
 		s.log.Warn("can't sign consensus payload", zap.Error(err))
 	}
 
+	s.trackPayload(p.(*Payload))
 	ep := &p.(*Payload).Extensible
 	s.Config.Broadcast(ep)
 }
@@ -0,0 +1,364 @@
+/*
+Package monitor implements consensus rounds tracking. It doesn't depend on dBFT
+implementation and only collects information about messages sent by
+validators, so it can be used both by validator nodes and by nodes following
+consensus in watch-only mode. Besides rounds it also keeps all messages
+received for the last heights and per-validator statistics.
+*/
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"go.uber.org/atomic"
+)
+
+// MessageType is a type of consensus message tracked by Monitor.
+type MessageType byte
+
+// Tracked consensus message types.
+const (
+	ChangeView MessageType = iota
+	PrepareRequest
+	PrepareResponse
+	Commit
+	RecoveryRequest
+	RecoveryMessage
+)
+
+// String implements fmt.Stringer interface.
+func (t MessageType) String() string {
+	switch t {
+	case ChangeView:
+		return "ChangeView"
+	case PrepareRequest:
+		return "PrepareRequest"
+	case PrepareResponse:
+		return "PrepareResponse"
+	case Commit:
+		return "Commit"
+	case RecoveryRequest:
+		return "RecoveryRequest"
+	case RecoveryMessage:
+		return "RecoveryMessage"
+	default:
+		return "Unknown"
+	}
+}
+
+// DefaultHistorySize is the default number of finished rounds kept by Monitor.
+const DefaultHistorySize = 100
+
+type (
+	// Validator contains information about validator activity in a single
+	// consensus round. Message times are in milliseconds since the round
+	// start, they're nil if there was no such message from the validator.
+	Validator struct {
+		Index           uint16          `json:"index"`
+		PublicKey       *keys.PublicKey `json:"publickey"`
+		PrepareRequest  *uint64         `json:"preparerequest,omitempty"`
+		PrepareResponse *uint64         `json:"prepareresponse,omitempty"`
+		Commit          *uint64         `json:"commit,omitempty"`
+		ChangeView      *uint64         `json:"changeview,omitempty"`
+		// ChangeViewReason is the reason specified in the ChangeView
+		// message.
+		ChangeViewReason string `json:"changeviewreason,omitempty"`
+	}
+
+	// Round contains information about a single consensus round, that is
+	// a pair of block height and view number.
+	Round struct {
+		Height  uint32 `json:"height"`
+		View    byte   `json:"view"`
+		Primary uint16 `json:"primary"`
+		// Start is the round start time (Unix milliseconds). Rounds with
+		// non-zero view are started by the first message received for
+		// this view.
+		Start uint64 `json:"start"`
+		// Duration is the round duration in milliseconds, it's only set
+		// for finished rounds.
+		Duration uint64 `json:"duration"`
+		// Finished is true for rounds ended with a new block or a view
+		// change.
+		Finished bool `json:"finished"`
+		// BlockAccepted is true for rounds ended with a new block.
+		BlockAccepted bool        `json:"blockaccepted"`
+		Validators    []Validator `json:"validators"`
+	}
+
+	// Monitor tracks consensus rounds, it's safe for concurrent use.
+	Monitor struct {
+		lock    sync.RWMutex
+		current *Round
+		// start is the precise start time of the current round.
+		start       time.Time
+		history     []*Round
+		historySize int
+		// traces contains received messages for the last historySize
+		// heights in ascending order.
+		traces []*HeightTrace
+		// stats contains per-validator statistics indexed by the
+		// compressed public key.
+		stats     map[string]*ValidatorStats
+		statsList []*ValidatorStats
+		// accepted contains copies of the rounds ended with a new block
+		// for the heights traced. Commits received after the block
+		// acceptance are added to them and missed commits are only
+		// counted when the height trace is dropped.
+		accepted []*Round
+
+		now func() time.Time
+
+		// Finished rounds notifications.
+		subCh    chan chan<- *Round
+		unsubCh  chan chan<- *Round
+		events   chan *Round
+		quit     chan struct{}
+		finished chan struct{}
+		started  atomic.Bool
+	}
+)
+
+// New creates a Monitor keeping historySize finished rounds.
+func New(historySize int) *Monitor {
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+	return &Monitor{
+		historySize: historySize,
+		stats:       make(map[string]*ValidatorStats),
+		now:         time.Now,
+		subCh:       make(chan chan<- *Round),
+		unsubCh:     make(chan chan<- *Round),
+		events:      make(chan *Round, historySize),
+		quit:        make(chan struct{}),
+		finished:    make(chan struct{}),
+	}
+}
+
+// Run starts finished rounds notifications dispatching. Monitor can't be
+// restarted after Shutdown.
+func (m *Monitor) Run() {
+	if m.started.CAS(false, true) {
+		go m.notificationDispatcher()
+	}
+}
+
+// Shutdown stops finished rounds notifications dispatching.
+func (m *Monitor) Shutdown() {
+	if m.started.CAS(true, false) {
+		close(m.quit)
+		<-m.finished
+	}
+}
+
+// SubscribeForRounds adds given channel to finished rounds broadcasting. It's
+// a no-op if Monitor is not running.
+func (m *Monitor) SubscribeForRounds(ch chan<- *Round) {
+	if m.started.Load() {
+		select {
+		case m.subCh <- ch:
+		case <-m.finished:
+		}
+	}
+}
+
+// UnsubscribeFromRounds unsubscribes given channel from finished rounds
+// notifications, you can close it afterwards. Passing non-subscribed channel
+// is a no-op.
+func (m *Monitor) UnsubscribeFromRounds(ch chan<- *Round) {
+	if m.started.Load() {
+		select {
+		case m.unsubCh <- ch:
+		case <-m.finished:
+		}
+	}
+}
+
+// notificationDispatcher manages subscriptions and sends finished rounds to
+// subscribers.
+func (m *Monitor) notificationDispatcher() {
+	var feed = make(map[chan<- *Round]bool)
+	for {
+		select {
+		case <-m.quit:
+			close(m.finished)
+			return
+		case sub := <-m.subCh:
+			feed[sub] = true
+		case unsub := <-m.unsubCh:
+			delete(feed, unsub)
+		case r := <-m.events:
+			for ch := range feed {
+				ch <- r
+			}
+		}
+	}
+}
+
+// Rounds returns finished rounds kept and the current round (if any) in
+// chronological order.
+func (m *Monitor) Rounds() []Round {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	res := make([]Round, 0, len(m.history)+1)
+	for _, r := range m.history {
+		res = append(res, r.copy())
+	}
+	if m.current != nil {
+		res = append(res, m.current.copy())
+	}
+	return res
+}
+
+// OnBlock notifies Monitor about new block accepted by the chain, it finishes
+// current round and starts a new one for the next block with the given
+// validators.
+func (m *Monitor) OnBlock(index uint32, validators keys.PublicKeys) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.current != nil {
+		if index < m.current.Height {
+			return
+		}
+		// Rounds for skipped blocks (e.g. when node is not in sync)
+		// don't contain any meaningful data.
+		if index == m.current.Height {
+			m.current.BlockAccepted = true
+			m.finishRound()
+		}
+	}
+	m.startRound(index+1, 0, validators)
+}
+
+// OnMessage notifies Monitor about consensus message of the given type sent
+// by the validator with the given index. reason is only used for ChangeView
+// messages. Messages for past rounds and future heights are only traced, they
+// don't affect rounds.
+func (m *Monitor) OnMessage(height uint32, view byte, index uint16, typ MessageType, reason string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	cur := m.current
+	if cur == nil {
+		return
+	}
+	m.traceMessage(height, view, index, typ, reason)
+	if height != cur.Height || view < cur.View {
+		if typ == Commit && height < cur.Height {
+			m.addLateCommit(height, view, index)
+		}
+		return
+	}
+	if view > cur.View {
+		validators := make(keys.PublicKeys, len(cur.Validators))
+		for i := range cur.Validators {
+			validators[i] = cur.Validators[i].PublicKey
+		}
+		m.finishRound()
+		m.startRound(height, view, validators)
+		cur = m.current
+	}
+	if int(index) >= len(cur.Validators) {
+		return
+	}
+	v := &cur.Validators[index]
+	var field **uint64
+	switch typ {
+	case ChangeView:
+		field = &v.ChangeView
+	case PrepareRequest:
+		field = &v.PrepareRequest
+	case PrepareResponse:
+		field = &v.PrepareResponse
+	case Commit:
+		field = &v.Commit
+	default:
+		return
+	}
+	if *field != nil {
+		return
+	}
+	delay := m.now().Sub(m.start)
+	ms := uint64(delay.Milliseconds())
+	*field = &ms
+	if typ == ChangeView {
+		v.ChangeViewReason = reason
+		m.getStats(v.PublicKey).ChangeViews++
+		changeViewCounter.WithLabelValues(reason).Inc()
+	}
+	updateMessageDelayMetric(v.PublicKey, typ, delay)
+}
+
+// startRound makes a new round current. It must be called with the lock held.
+func (m *Monitor) startRound(height uint32, view byte, validators keys.PublicKeys) {
+	m.start = m.now()
+	r := &Round{
+		Height:     height,
+		View:       view,
+		Start:      toMillis(m.start),
+		Validators: make([]Validator, len(validators)),
+	}
+	if n := int64(len(validators)); n != 0 {
+		p := (int64(height) - int64(view)) % n
+		if p < 0 {
+			p += n
+		}
+		r.Primary = uint16(p)
+	}
+	for i := range validators {
+		r.Validators[i] = Validator{Index: uint16(i), PublicKey: validators[i]}
+	}
+	m.current = r
+	m.getTrace(height, true)
+	updateViewMetric(view)
+}
+
+// finishRound finishes the current round and moves it to the history. It
+// must be called with the lock held.
+func (m *Monitor) finishRound() {
+	r := m.current
+	m.current = nil
+	r.Finished = true
+	d := m.now().Sub(m.start)
+	r.Duration = uint64(d.Milliseconds())
+	m.updateStats(r)
+	updateRoundMetrics(d)
+
+	if len(m.history) == m.historySize {
+		copy(m.history, m.history[1:])
+		m.history = m.history[:len(m.history)-1]
+	}
+	m.history = append(m.history, r)
+	if m.started.Load() {
+		select {
+		case m.events <- r:
+		default:
+			// Subscribers are too slow, drop the notification.
+		}
+	}
+}
+
+// copy returns a deep copy of the round.
+func (r *Round) copy() Round {
+	res := *r
+	res.Validators = make([]Validator, len(r.Validators))
+	copy(res.Validators, r.Validators)
+	for i := range res.Validators {
+		v := &res.Validators[i]
+		v.PrepareRequest = copyTime(v.PrepareRequest)
+		v.PrepareResponse = copyTime(v.PrepareResponse)
+		v.Commit = copyTime(v.Commit)
+		v.ChangeView = copyTime(v.ChangeView)
+	}
+	return res
+}
+
+func copyTime(t *uint64) *uint64 {
+	if t == nil {
+		return nil
+	}
+	res := *t
+	return &res
+}
@@ -0,0 +1,284 @@
+package monitor
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMonitor(t *testing.T, historySize int) (*Monitor, keys.PublicKeys, *time.Time) {
+	m := New(historySize)
+	now := time.Unix(1000, 0)
+	m.now = func() time.Time { return now }
+
+	pubs := make(keys.PublicKeys, 4)
+	for i := range pubs {
+		priv, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		pubs[i] = priv.PublicKey()
+	}
+	return m, pubs, &now
+}
+
+func TestMonitor(t *testing.T) {
+	m, pubs, now := newTestMonitor(t, 0)
+
+	// Not started yet.
+	m.OnMessage(1, 0, 0, PrepareRequest, "")
+	require.Equal(t, 0, len(m.Rounds()))
+
+	m.OnBlock(5, pubs)
+	rs := m.Rounds()
+	require.Equal(t, 1, len(rs))
+	require.Equal(t, uint32(6), rs[0].Height)
+	require.Equal(t, byte(0), rs[0].View)
+	require.Equal(t, uint16(2), rs[0].Primary)
+	require.Equal(t, uint64(1000000), rs[0].Start)
+	require.False(t, rs[0].Finished)
+	require.Equal(t, 4, len(rs[0].Validators))
+
+	*now = now.Add(100 * time.Millisecond)
+	m.OnMessage(6, 0, 2, PrepareRequest, "")
+	*now = now.Add(100 * time.Millisecond)
+	m.OnMessage(6, 0, 1, PrepareResponse, "")
+	m.OnMessage(6, 0, 1, PrepareResponse, "") // Duplicate.
+	m.OnMessage(6, 0, 10, Commit, "")         // Unknown validator.
+	m.OnMessage(7, 0, 0, Commit, "")          // Future height.
+	m.OnMessage(5, 0, 0, Commit, "")          // Past height.
+
+	rs = m.Rounds()
+	require.Equal(t, 1, len(rs))
+	v := rs[0].Validators
+	require.Equal(t, uint64(100), *v[2].PrepareRequest)
+	require.Equal(t, uint64(200), *v[1].PrepareResponse)
+	require.Nil(t, v[1].Commit)
+	require.Nil(t, v[0].PrepareResponse)
+
+	// Returned rounds are copies.
+	*v[2].PrepareRequest = 42
+	require.Equal(t, uint64(100), *m.Rounds()[0].Validators[2].PrepareRequest)
+
+	*now = now.Add(100 * time.Millisecond)
+	m.OnMessage(6, 0, 3, ChangeView, "Timeout")
+	*now = now.Add(100 * time.Millisecond)
+	m.OnMessage(6, 1, 0, ChangeView, "Timeout")
+
+	rs = m.Rounds()
+	require.Equal(t, 2, len(rs))
+	require.True(t, rs[0].Finished)
+	require.False(t, rs[0].BlockAccepted)
+	require.Equal(t, uint64(400), rs[0].Duration)
+	require.Equal(t, uint64(300), *rs[0].Validators[3].ChangeView)
+	require.Equal(t, "Timeout", rs[0].Validators[3].ChangeViewReason)
+
+	require.Equal(t, uint32(6), rs[1].Height)
+	require.Equal(t, byte(1), rs[1].View)
+	require.Equal(t, uint16(1), rs[1].Primary)
+	require.Equal(t, uint64(0), *rs[1].Validators[0].ChangeView)
+
+	// Messages for the previous view are ignored now.
+	m.OnMessage(6, 0, 0, Commit, "")
+	require.Nil(t, m.Rounds()[1].Validators[0].Commit)
+
+	*now = now.Add(time.Second)
+	m.OnMessage(6, 1, 0, Commit, "")
+	m.OnBlock(6, pubs)
+	rs = m.Rounds()
+	require.Equal(t, 3, len(rs))
+	require.True(t, rs[1].Finished)
+	require.True(t, rs[1].BlockAccepted)
+	require.Equal(t, uint64(1000), rs[1].Duration)
+	require.Equal(t, uint32(7), rs[2].Height)
+	require.Equal(t, byte(0), rs[2].View)
+
+	// Old blocks are ignored.
+	m.OnBlock(5, pubs)
+	require.Equal(t, 3, len(m.Rounds()))
+
+	// Skipped rounds are not stored.
+	m.OnBlock(10, pubs)
+	rs = m.Rounds()
+	require.Equal(t, 3, len(rs))
+	require.Equal(t, uint32(11), rs[2].Height)
+}
+
+func TestMonitorHistorySize(t *testing.T) {
+	m, pubs, _ := newTestMonitor(t, 2)
+	for i := uint32(0); i < 5; i++ {
+		m.OnBlock(i, pubs)
+	}
+	rs := m.Rounds()
+	require.Equal(t, 3, len(rs))
+	require.Equal(t, uint32(3), rs[0].Height)
+	require.Equal(t, uint32(4), rs[1].Height)
+	require.Equal(t, uint32(5), rs[2].Height)
+}
+
+func TestMonitorSubscriptions(t *testing.T) {
+	m, pubs, _ := newTestMonitor(t, 0)
+	m.Run()
+	t.Cleanup(m.Shutdown)
+
+	ch := make(chan *Round, 1)
+	m.SubscribeForRounds(ch)
+
+	m.OnBlock(1, pubs)
+	m.OnBlock(2, pubs)
+	select {
+	case r := <-ch:
+		require.Equal(t, uint32(2), r.Height)
+		require.True(t, r.BlockAccepted)
+	case <-time.After(time.Second):
+		t.Fatal("no round received")
+	}
+
+	m.UnsubscribeFromRounds(ch)
+	m.OnBlock(3, pubs)
+	require.Never(t, func() bool { return len(ch) != 0 }, 100*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestMessageTypeString(t *testing.T) {
+	require.Equal(t, "ChangeView", ChangeView.String())
+	require.Equal(t, "PrepareRequest", PrepareRequest.String())
+	require.Equal(t, "PrepareResponse", PrepareResponse.String())
+	require.Equal(t, "Commit", Commit.String())
+	require.Equal(t, "RecoveryRequest", RecoveryRequest.String())
+	require.Equal(t, "RecoveryMessage", RecoveryMessage.String())
+	require.Equal(t, "Unknown", MessageType(42).String())
+}
+
+func TestMessageTypeJSON(t *testing.T) {
+	for typ := ChangeView; typ <= RecoveryMessage; typ++ {
+		data, err := json.Marshal(typ)
+		require.NoError(t, err)
+		require.Equal(t, `"`+typ.String()+`"`, string(data))
+
+		var actual MessageType
+		require.NoError(t, json.Unmarshal(data, &actual))
+		require.Equal(t, typ, actual)
+	}
+	var typ MessageType
+	require.Error(t, json.Unmarshal([]byte(`"Unknown"`), &typ))
+	require.Error(t, json.Unmarshal([]byte(`1`), &typ))
+}
+
+func TestMonitorTrace(t *testing.T) {
+	m, pubs, now := newTestMonitor(t, 2)
+
+	// Not started yet.
+	st := m.Status()
+	require.Nil(t, st.Round)
+	m.OnMessage(1, 0, 0, PrepareRequest, "")
+	require.Equal(t, 0, len(m.History(0).Heights))
+
+	m.OnBlock(0, pubs)
+	*now = now.Add(100 * time.Millisecond)
+	m.OnMessage(1, 0, 1, PrepareRequest, "")
+	m.OnMessage(1, 0, 2, PrepareResponse, "")
+	m.OnMessage(1, 0, 2, PrepareResponse, "") // Duplicates are traced.
+	m.OnMessage(2, 0, 3, RecoveryRequest, "") // Next height.
+	m.OnMessage(3, 0, 3, Commit, "")          // Future height, ignored.
+	*now = now.Add(100 * time.Millisecond)
+	m.OnMessage(1, 1, 0, ChangeView, "Timeout")
+
+	st = m.Status()
+	require.NotNil(t, st.Round)
+	require.Equal(t, uint32(1), st.Round.Height)
+	require.Equal(t, byte(1), st.Round.View)
+	require.Equal(t, []Message{
+		{Type: PrepareRequest, View: 0, Validator: 1, Time: 1000100},
+		{Type: PrepareResponse, View: 0, Validator: 2, Time: 1000100},
+		{Type: PrepareResponse, View: 0, Validator: 2, Time: 1000100},
+		{Type: ChangeView, View: 1, Validator: 0, Time: 1000200, Reason: "Timeout"},
+	}, st.Messages)
+	require.Equal(t, 4, len(st.Validators))
+	require.Equal(t, ValidatorStats{PublicKey: pubs[0], ChangeViews: 1}, st.Validators[0])
+	require.Equal(t, ValidatorStats{PublicKey: pubs[1]}, st.Validators[1])
+
+	m.OnMessage(1, 1, 0, Commit, "")
+	m.OnMessage(1, 1, 1, Commit, "")
+	m.OnBlock(1, pubs)
+	m.OnMessage(1, 1, 2, Commit, "") // Late message.
+
+	h := m.History(0)
+	require.Equal(t, 2, len(h.Heights))
+	require.Equal(t, uint32(1), h.Heights[0].Height)
+	require.Equal(t, uint64(1000000), h.Heights[0].Start)
+	require.Equal(t, 7, len(h.Heights[0].Messages))
+	require.Equal(t, Message{Type: Commit, View: 1, Validator: 2, Time: 1000200}, h.Heights[0].Messages[6])
+	require.Equal(t, uint32(2), h.Heights[1].Height)
+	require.Equal(t, uint64(1000100), h.Heights[1].Start)
+	require.Equal(t, []Message{{Type: RecoveryRequest, View: 0, Validator: 3, Time: 1000100}}, h.Heights[1].Messages)
+	// Missed commits are not counted until the height trace is dropped.
+	require.Equal(t, []ValidatorStats{
+		{PublicKey: pubs[0], AcceptedBlocks: 1, ChangeViews: 1},
+		{PublicKey: pubs[1], AcceptedBlocks: 1},
+		{PublicKey: pubs[2], AcceptedBlocks: 1},
+		{PublicKey: pubs[3], AcceptedBlocks: 1},
+	}, h.Validators)
+
+	// Returned traces are copies.
+	h.Heights[0].Messages[0].Validator = 42
+	require.Equal(t, uint16(1), m.History(0).Heights[0].Messages[0].Validator)
+
+	// Only historySize heights are kept.
+	m.OnBlock(2, pubs)
+	h = m.History(0)
+	require.Equal(t, 2, len(h.Heights))
+	require.Equal(t, uint32(2), h.Heights[0].Height)
+	require.Equal(t, uint32(3), h.Heights[1].Height)
+	require.Equal(t, uint32(2), h.Validators[0].AcceptedBlocks)
+	require.Equal(t, uint32(0), h.Validators[2].MissedCommits)
+	require.Equal(t, uint32(1), h.Validators[3].MissedCommits)
+
+	h = m.History(1)
+	require.Equal(t, 1, len(h.Heights))
+	require.Equal(t, uint32(3), h.Heights[0].Height)
+}
+
+func TestMonitorTraceLimit(t *testing.T) {
+	m, pubs, _ := newTestMonitor(t, 0)
+	m.OnBlock(0, pubs)
+	for i := 0; i < MaxHeightMessages+1; i++ {
+		m.OnMessage(1, 0, 0, RecoveryRequest, "")
+	}
+	h := m.History(0)
+	require.Equal(t, MaxHeightMessages, len(h.Heights[0].Messages))
+	require.True(t, h.Heights[0].Truncated)
+}
+
+func TestMonitorMissedCommits(t *testing.T) {
+	m, pubs, now := newTestMonitor(t, 2)
+	m.OnBlock(0, pubs)
+	m.OnMessage(1, 0, 0, Commit, "")
+	m.OnMessage(1, 0, 1, Commit, "")
+	*now = now.Add(100 * time.Millisecond)
+	m.OnBlock(1, pubs)
+
+	// Commits arriving just after the block acceptance are not missed.
+	*now = now.Add(10 * time.Millisecond)
+	m.OnMessage(1, 0, 2, Commit, "")
+	m.OnMessage(1, 1, 3, Commit, "")  // Different view.
+	m.OnMessage(1, 0, 10, Commit, "") // Unknown validator.
+	for _, st := range m.History(0).Validators {
+		require.Equal(t, uint32(1), st.AcceptedBlocks)
+		require.Equal(t, uint32(0), st.MissedCommits)
+	}
+	// Round itself is not changed.
+	require.Nil(t, m.Rounds()[0].Validators[2].Commit)
+
+	// Height 1 trace is dropped here.
+	m.OnBlock(2, pubs)
+	m.OnMessage(1, 0, 3, Commit, "") // Too late.
+	h := m.History(0)
+	require.Equal(t, uint32(2), h.Heights[0].Height)
+	require.Equal(t, []ValidatorStats{
+		{PublicKey: pubs[0], AcceptedBlocks: 2},
+		{PublicKey: pubs[1], AcceptedBlocks: 2},
+		{PublicKey: pubs[2], AcceptedBlocks: 2},
+		{PublicKey: pubs[3], AcceptedBlocks: 2, MissedCommits: 1},
+	}, h.Validators)
+}
@@ -0,0 +1,87 @@
+package monitor
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics for monitoring consensus rounds.
+var (
+	// currentView prometheus metric.
+	currentView = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Current consensus view number",
+			Name:      "consensus_view",
+			Namespace: "neogo",
+		},
+	)
+	// roundDuration prometheus metric.
+	roundDuration = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Duration of the last finished consensus round in seconds",
+			Name:      "consensus_last_round_duration_seconds",
+			Namespace: "neogo",
+		},
+	)
+	// changeViewCounter prometheus metric.
+	changeViewCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of ChangeView messages received by reason",
+			Name:      "consensus_change_views_total",
+			Namespace: "neogo",
+		},
+		[]string{"reason"},
+	)
+	// messageDelay prometheus metric.
+	messageDelay = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Help:      "Delay of the last consensus message of the given type from the round start in seconds",
+			Name:      "consensus_message_delay_seconds",
+			Namespace: "neogo",
+		},
+		[]string{"validator", "type"},
+	)
+	// missedCommits prometheus metric.
+	missedCommits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of accepted blocks not having Commit message from the validator",
+			Name:      "consensus_missed_commits_total",
+			Namespace: "neogo",
+		},
+		[]string{"validator"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		currentView,
+		roundDuration,
+		changeViewCounter,
+		messageDelay,
+		missedCommits,
+	)
+}
+
+func updateViewMetric(view byte) {
+	currentView.Set(float64(view))
+}
+
+func updateMessageDelayMetric(pub *keys.PublicKey, typ MessageType, d time.Duration) {
+	messageDelay.WithLabelValues(validatorLabel(pub), typ.String()).Set(d.Seconds())
+}
+
+func updateRoundMetrics(d time.Duration) {
+	roundDuration.Set(d.Seconds())
+}
+
+func updateMissedCommitsMetric(pub *keys.PublicKey) {
+	missedCommits.WithLabelValues(validatorLabel(pub)).Inc()
+}
+
+// validatorLabel returns metric label for the validator key.
+func validatorLabel(pub *keys.PublicKey) string {
+	return hex.EncodeToString(pub.Bytes())
+}
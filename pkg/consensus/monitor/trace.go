@@ -0,0 +1,247 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+)
+
+// MaxHeightMessages is the maximum number of messages traced for a single
+// height, the rest of them is dropped.
+const MaxHeightMessages = 1024
+
+type (
+	// Message is a consensus message received (or sent) by the node.
+	Message struct {
+		Type      MessageType `json:"type"`
+		View      byte        `json:"view"`
+		Validator uint16      `json:"validator"`
+		// Time is the message arrival time (Unix milliseconds).
+		Time uint64 `json:"time"`
+		// Reason is the reason specified in the ChangeView message.
+		Reason string `json:"reason,omitempty"`
+	}
+
+	// HeightTrace contains all consensus messages for a single height in
+	// the order of their arrival.
+	HeightTrace struct {
+		Height uint32 `json:"height"`
+		// Start is the time of the first event for this height (Unix
+		// milliseconds), that is either the previous block acceptance or
+		// the first message received for this height.
+		Start     uint64    `json:"start"`
+		Messages  []Message `json:"messages"`
+		Truncated bool      `json:"truncated"`
+	}
+
+	// ValidatorStats contains validator activity statistics collected since
+	// the node start.
+	ValidatorStats struct {
+		PublicKey *keys.PublicKey `json:"publickey"`
+		// AcceptedBlocks is the number of accepted blocks the validator
+		// was expected to sign.
+		AcceptedBlocks uint32 `json:"acceptedblocks"`
+		// MissedCommits is the number of accepted blocks without Commit
+		// message from the validator. Commits can arrive after the block
+		// acceptance, so blocks are only counted here when their height
+		// trace is dropped.
+		MissedCommits uint32 `json:"missedcommits"`
+		ChangeViews   uint32 `json:"changeviews"`
+	}
+
+	// Status contains the current consensus round with the messages
+	// received for its height and current validators statistics.
+	Status struct {
+		// Round is nil if there is no current round yet.
+		Round      *Round           `json:"round"`
+		Messages   []Message        `json:"messages"`
+		Validators []ValidatorStats `json:"validators"`
+	}
+
+	// History contains messages traced for the last heights and statistics
+	// for all validators seen.
+	History struct {
+		Heights    []HeightTrace    `json:"heights"`
+		Validators []ValidatorStats `json:"validators"`
+	}
+)
+
+// MarshalJSON implements json.Marshaler interface.
+func (t MessageType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (t *MessageType) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	for typ := ChangeView; typ <= RecoveryMessage; typ++ {
+		if typ.String() == s {
+			*t = typ
+			return nil
+		}
+	}
+	return errors.New("unknown message type")
+}
+
+// Status returns the current consensus status.
+func (m *Monitor) Status() Status {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	var res Status
+	if m.current == nil {
+		return res
+	}
+	r := m.current.copy()
+	res.Round = &r
+	if t := m.getTrace(r.Height, false); t != nil {
+		res.Messages = append([]Message{}, t.Messages...)
+	}
+	res.Validators = make([]ValidatorStats, len(r.Validators))
+	for i := range r.Validators {
+		if st, ok := m.stats[string(r.Validators[i].PublicKey.Bytes())]; ok {
+			res.Validators[i] = *st
+		} else {
+			res.Validators[i] = ValidatorStats{PublicKey: r.Validators[i].PublicKey}
+		}
+	}
+	return res
+}
+
+// History returns messages traced for the last count heights (all heights
+// kept if count is not positive) and statistics for all validators seen.
+func (m *Monitor) History(count int) History {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	traces := m.traces
+	if count > 0 && count < len(traces) {
+		traces = traces[len(traces)-count:]
+	}
+	res := History{
+		Heights:    make([]HeightTrace, len(traces)),
+		Validators: make([]ValidatorStats, len(m.statsList)),
+	}
+	for i, t := range traces {
+		res.Heights[i] = *t
+		res.Heights[i].Messages = append([]Message{}, t.Messages...)
+	}
+	for i, st := range m.statsList {
+		res.Validators[i] = *st
+	}
+	return res
+}
+
+// traceMessage adds a message to the height trace. Messages are only traced
+// for the heights kept and the next one. It must be called with the lock held.
+func (m *Monitor) traceMessage(height uint32, view byte, index uint16, typ MessageType, reason string) {
+	t := m.getTrace(height, height == m.current.Height+1)
+	if t == nil {
+		return
+	}
+	if len(t.Messages) >= MaxHeightMessages {
+		t.Truncated = true
+		return
+	}
+	t.Messages = append(t.Messages, Message{
+		Type:      typ,
+		View:      view,
+		Validator: index,
+		Time:      toMillis(m.now()),
+		Reason:    reason,
+	})
+}
+
+// getTrace returns the trace for the given height. If there is no such trace
+// and create is true it's added if it's higher than all traces kept. It must
+// be called with the lock held (read lock is sufficient if create is false).
+func (m *Monitor) getTrace(height uint32, create bool) *HeightTrace {
+	for i := len(m.traces) - 1; i >= 0; i-- {
+		if m.traces[i].Height == height {
+			return m.traces[i]
+		}
+		if m.traces[i].Height < height {
+			break
+		}
+	}
+	if !create || (len(m.traces) != 0 && m.traces[len(m.traces)-1].Height > height) {
+		return nil
+	}
+	if len(m.traces) == m.historySize {
+		m.countMissedCommits(m.traces[0].Height)
+		copy(m.traces, m.traces[1:])
+		m.traces = m.traces[:len(m.traces)-1]
+	}
+	t := &HeightTrace{Height: height, Start: toMillis(m.now())}
+	m.traces = append(m.traces, t)
+	return t
+}
+
+// getStats returns statistics for the given validator creating it if needed.
+// It must be called with the lock held.
+func (m *Monitor) getStats(pub *keys.PublicKey) *ValidatorStats {
+	k := string(pub.Bytes())
+	st, ok := m.stats[k]
+	if !ok {
+		st = &ValidatorStats{PublicKey: pub}
+		m.stats[k] = st
+		m.statsList = append(m.statsList, st)
+	}
+	return st
+}
+
+// updateStats updates validators statistics for the finished round and
+// keeps it for missed commits accounting if it's ended with a new block. It
+// must be called with the lock held.
+func (m *Monitor) updateStats(r *Round) {
+	if !r.BlockAccepted {
+		return
+	}
+	for i := range r.Validators {
+		m.getStats(r.Validators[i].PublicKey).AcceptedBlocks++
+	}
+	c := r.copy()
+	m.accepted = append(m.accepted, &c)
+}
+
+// addLateCommit adds Commit message received after the block acceptance to
+// the accepted round. It must be called with the lock held.
+func (m *Monitor) addLateCommit(height uint32, view byte, index uint16) {
+	for _, r := range m.accepted {
+		if r.Height != height || r.View != view {
+			continue
+		}
+		if int(index) < len(r.Validators) && r.Validators[index].Commit == nil {
+			var ms uint64
+			if now := toMillis(m.now()); now > r.Start {
+				ms = now - r.Start
+			}
+			r.Validators[index].Commit = &ms
+		}
+		return
+	}
+}
+
+// countMissedCommits updates missed commits statistics for the accepted
+// rounds up to the given height and drops them. It must be called with the
+// lock held.
+func (m *Monitor) countMissedCommits(height uint32) {
+	var n int
+	for ; n < len(m.accepted) && m.accepted[n].Height <= height; n++ {
+		r := m.accepted[n]
+		for i := range r.Validators {
+			if r.Validators[i].Commit == nil {
+				m.getStats(r.Validators[i].PublicKey).MissedCommits++
+				updateMissedCommitsMetric(r.Validators[i].PublicKey)
+			}
+		}
+	}
+	m.accepted = append(m.accepted[:0], m.accepted[n:]...)
+}
+
+func toMillis(t time.Time) uint64 {
+	return uint64(t.UnixNano()) / uint64(time.Millisecond)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/contract"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
 	"github.com/nspcc-dev/neo-go/pkg/core/native"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
@@ -102,6 +103,9 @@ var (
 	// conflicts with other transaction in the chain or pool according to
 	// Conflicts attribute.
 	ErrHasConflicts = errors.New("has conflicts")
+	// ErrNotificationIndexDisabled is returned when trying to use
+	// notification index with NotificationIndex setting disabled.
+	ErrNotificationIndexDisabled = errors.New("notification index is disabled")
 )
 var (
 	persistInterval = 1 * time.Second
@@ -177,6 +181,10 @@ type Blockchain struct {
 
 	stateRoot *stateroot.Module
 
+	// execHook is an optional vm.OnExecHook installed into every VM
+	// spawned by the chain.
+	execHook atomic.Value
+
 	// Notification subsystem.
 	events  chan bcEvent
 	subCh   chan interface{}
@@ -537,6 +545,12 @@ func (bc *Blockchain) jumpToStateInternal(p uint32, stage stateJumpStage) error
 		if p-bc.config.MaxTraceableBlocks > 0 {
 			cache := bc.dao.GetWrapped()
 			writeBuf.Reset()
+			if bc.config.NotificationIndex {
+				err := cache.DeleteNotificationIndex(bc.headerHashes[0])
+				if err != nil {
+					return fmt.Errorf("failed to remove notification index for the genesis block: %w", err)
+				}
+			}
 			err := cache.DeleteBlock(bc.headerHashes[0], writeBuf)
 			if err != nil {
 				return fmt.Errorf("failed to remove outdated state data for the genesis block: %w", err)
@@ -963,6 +977,14 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 				stop = start + 1
 			}
 			for index := start; index < stop; index++ {
+				if bc.config.NotificationIndex {
+					err := kvcache.DeleteNotificationIndex(bc.headerHashes[index])
+					if err != nil {
+						bc.log.Warn("error while removing notification index of old block",
+							zap.Uint32("index", index),
+							zap.Error(err))
+					}
+				}
 				err := kvcache.DeleteBlock(bc.headerHashes[index], writeBuf)
 				if err != nil {
 					bc.log.Warn("error while removing old block",
@@ -981,6 +1003,7 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 			err         error
 			appendBlock bool
 			transCache  = make(map[util.Uint160]transferData)
+			aerPosition uint32
 		)
 		for aer := range aerchan {
 			if aer.Container == block.Hash() && appendBlock {
@@ -999,7 +1022,15 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 				for j := range aer.Execution.Events {
 					bc.handleNotification(&aer.Execution.Events[j], kvcache, transCache, block, aer.Container)
 				}
+				if bc.config.NotificationIndex {
+					err = storeNotificationIndex(kvcache, block.Index, aerPosition, aer)
+					if err != nil {
+						err = fmt.Errorf("failed to store notification index: %w", err)
+						break
+					}
+				}
 			}
+			aerPosition++
 			writeBuf.Reset()
 		}
 		if err != nil {
@@ -1042,7 +1073,7 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 
 	for _, tx := range block.Transactions {
 		systemInterop := bc.newInteropContext(trigger.Application, cache, block, tx)
-		v := systemInterop.SpawnVM()
+		v := bc.spawnVM(systemInterop)
 		v.LoadScriptWithFlags(tx.Script, callflag.All)
 		v.SetPriceGetter(systemInterop.GetPrice)
 		v.LoadToken = contract.LoadToken(systemInterop)
@@ -1232,7 +1263,7 @@ func (bc *Blockchain) IsExtensibleAllowed(u util.Uint160) bool {
 
 func (bc *Blockchain) runPersist(script []byte, block *block.Block, cache dao.DAO, trig trigger.Type) (*state.AppExecResult, error) {
 	systemInterop := bc.newInteropContext(trig, cache, block, nil)
-	v := systemInterop.SpawnVM()
+	v := bc.spawnVM(systemInterop)
 	v.LoadScriptWithFlags(script, callflag.All)
 	v.SetPriceGetter(systemInterop.GetPrice)
 	if err := systemInterop.Exec(); err != nil {
@@ -1579,6 +1610,87 @@ func (bc *Blockchain) GetAppExecResults(hash util.Uint256, trig trigger.Type) ([
 	return bc.dao.GetAppExecResults(hash, trig)
 }
 
+// storeNotificationIndex adds notification index entries for every contract
+// that emitted notifications during the given execution.
+func storeNotificationIndex(d dao.DAO, index uint32, pos uint32, aer *state.AppExecResult) error {
+	var seen = make(map[util.Uint160]bool)
+	for i := range aer.Execution.Events {
+		h := aer.Execution.Events[i].ScriptHash
+		if seen[h] {
+			continue
+		}
+		seen[h] = true
+		err := d.PutNotificationIndex(h, dao.NotificationIndexEntry{
+			BlockIndex: index,
+			Position:   pos,
+			Container:  aer.Container,
+			Trigger:    aer.Trigger,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetNotifications returns notifications emitted by the given contract in
+// the blocks from start to end (both inclusive) in the order they were
+// emitted. If name is not empty, only notifications with this name are
+// returned. If limit is positive, notifications are returned up to the
+// first block where the overall number of notifications reaches it, so the
+// notifications of a single block are never split. It requires
+// NotificationIndex setting to be enabled and only notifications of HALTed
+// executions are returned.
+func (bc *Blockchain) GetNotifications(contract util.Uint160, name string, start, end uint32, limit int) ([]state.IndexedNotificationEvent, error) {
+	if !bc.config.NotificationIndex {
+		return nil, ErrNotificationIndexDisabled
+	}
+	var (
+		res       []state.IndexedNotificationEvent
+		lastBlock = start
+	)
+	for {
+		// Every index entry has at least one notification of the contract,
+		// but some of them can be filtered out by name, so entries are
+		// requested until the limit is reached.
+		entries, err := bc.dao.GetNotificationIndex(contract, start, end, limit-len(res))
+		if err != nil {
+			return nil, err
+		}
+		for i := range entries {
+			if limit > 0 && len(res) >= limit && entries[i].BlockIndex != lastBlock {
+				return res, nil
+			}
+			lastBlock = entries[i].BlockIndex
+			aers, err := bc.dao.GetAppExecResults(entries[i].Container, entries[i].Trigger)
+			if err != nil {
+				if errors.Is(err, storage.ErrKeyNotFound) {
+					// Execution results can be removed along with the old blocks.
+					continue
+				}
+				return nil, err
+			}
+			for _, aer := range aers {
+				for _, ev := range aer.Events {
+					if ev.ScriptHash != contract || (name != "" && ev.Name != name) {
+						continue
+					}
+					res = append(res, state.IndexedNotificationEvent{
+						BlockIndex:        entries[i].BlockIndex,
+						Container:         aer.Container,
+						Trigger:           aer.Trigger,
+						NotificationEvent: ev,
+					})
+				}
+			}
+		}
+		if limit <= 0 || len(res) >= limit || len(entries) == 0 || lastBlock >= end {
+			return res, nil
+		}
+		start = lastBlock + 1
+	}
+}
+
 // GetStorageItem returns an item from storage.
 func (bc *Blockchain) GetStorageItem(id int32, key []byte) state.StorageItem {
 	return bc.dao.GetStorageItem(id, key)
@@ -2146,16 +2258,62 @@ func (bc *Blockchain) GetEnrollments() ([]state.Validator, error) {
 	return bc.contracts.NEO.GetCandidates(bc.dao)
 }
 
+// SetOnExecHook sets the hook to be installed into every VM spawned by the
+// chain, both for block processing and test invocations. It's intended to be
+// used for execution profiling in tests, nil removes the hook.
+func (bc *Blockchain) SetOnExecHook(h vm.OnExecHook) {
+	bc.execHook.Store(h)
+}
+
+// spawnVM creates a new VM for the given interop context and installs
+// execution hook into it if there is any.
+func (bc *Blockchain) spawnVM(ic *interop.Context) *vm.VM {
+	v := ic.SpawnVM()
+	if h, ok := bc.execHook.Load().(vm.OnExecHook); ok && h != nil {
+		v.SetOnExecHook(h)
+	}
+	return v
+}
+
 // GetTestVM returns a VM setup for a test run of some sort of code and finalizer function.
 func (bc *Blockchain) GetTestVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) (*vm.VM, func()) {
 	d := bc.dao.GetWrapped().(*dao.Simple)
 	systemInterop := bc.newInteropContext(t, d, b, tx)
-	vm := systemInterop.SpawnVM()
+	vm := bc.spawnVM(systemInterop)
 	vm.SetPriceGetter(systemInterop.GetPrice)
 	vm.LoadToken = contract.LoadToken(systemInterop)
 	return vm, systemInterop.Finalize
 }
 
+// GetTestHistoricVM returns a VM setup for a test run of some sort of code and
+// finalizer function over the historic chain state. The state is the one after
+// the block preceding the given one (b.Index-1) was processed, so b is supposed
+// to be a (fake) block at the height of interest + 1. It's only supported when
+// 'KeepOnlyLatestState' setting is disabled, contract storage is retrieved from
+// the MPT in this case.
+func (bc *Blockchain) GetTestHistoricVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) (*vm.VM, func(), error) {
+	if bc.config.KeepOnlyLatestState {
+		return nil, nil, errors.New("only latest state is supported")
+	}
+	if b == nil {
+		return nil, nil, errors.New("block is mandatory to produce test historic VM")
+	}
+	if b.Index < 1 || b.Index > bc.BlockHeight()+1 {
+		return nil, nil, fmt.Errorf("unsupported historic chain's height: requested state for %d, chain height %d", b.Index-1, bc.BlockHeight())
+	}
+	sr, err := bc.stateRoot.GetStateRoot(b.Index - 1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve stateroot for height %d: %w", b.Index-1, err)
+	}
+	s := mpt.NewTrieStore(sr.Root, bc.dao.Store)
+	d := dao.NewSimple(s, bc.config.StateRootInHeader, bc.config.P2PSigExtensions)
+	systemInterop := bc.newHistoricInteropContext(t, d, b, tx)
+	vm := bc.spawnVM(systemInterop)
+	vm.SetPriceGetter(systemInterop.GetPrice)
+	vm.LoadToken = contract.LoadToken(systemInterop)
+	return vm, systemInterop.Finalize, nil
+}
+
 // Various witness verification errors.
 var (
 	ErrWitnessHashMismatch         = errors.New("witness hash mismatch")
@@ -2225,7 +2383,7 @@ func (bc *Blockchain) verifyHashAgainstScript(hash util.Uint160, witness *transa
 		gas = gasPolicy
 	}
 
-	vm := interopCtx.SpawnVM()
+	vm := bc.spawnVM(interopCtx)
 	vm.SetPriceGetter(interopCtx.GetPrice)
 	vm.LoadToken = contract.LoadToken(interopCtx)
 	vm.GasLimit = gas
@@ -2330,6 +2488,21 @@ func (bc *Blockchain) newInteropContext(trigger trigger.Type, d dao.DAO, block *
 	return ic
 }
 
+// newHistoricInteropContext is similar to newInteropContext, but it retrieves
+// contract states directly from the given DAO bypassing native Management
+// cache that always reflects the latest state.
+func (bc *Blockchain) newHistoricInteropContext(trigger trigger.Type, d dao.DAO, block *block.Block, tx *transaction.Transaction) *interop.Context {
+	ic := interop.NewContext(trigger, bc, d, bc.contracts.Management.GetContractFromDAO, bc.contracts.Contracts, block, tx, bc.log)
+	ic.Functions = systemInterops
+	if tx != nil {
+		ic.Container = tx
+	} else {
+		ic.Container = block
+	}
+	ic.InitNonceData()
+	return ic
+}
+
 // P2PSigExtensionsEnabled defines whether P2P signature extensions are enabled.
 func (bc *Blockchain) P2PSigExtensionsEnabled() bool {
 	return bc.config.P2PSigExtensions
@@ -1360,6 +1360,96 @@ func TestGetTransaction(t *testing.T) {
 	}
 }
 
+func TestBlockchain_GetNotifications(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		bc := newTestChain(t)
+		_, err := bc.GetNotifications(bc.UtilityTokenHash(), "", 0, 0, 0)
+		require.ErrorIs(t, err, ErrNotificationIndexDisabled)
+	})
+
+	bc := newTestChainWithCustomCfg(t, func(c *config.Config) {
+		c.ProtocolConfiguration.NotificationIndex = true
+	})
+	_, err := bc.genBlocks(2)
+	require.NoError(t, err)
+	transferTokenFromMultisigAccount(t, bc, util.Uint160{1, 2, 3}, bc.UtilityTokenHash(), 1_0000_0000)
+	_, err = bc.genBlocks(1)
+	require.NoError(t, err)
+
+	gasHash := bc.UtilityTokenHash()
+	var expected []state.IndexedNotificationEvent
+	for i := uint32(0); i <= bc.BlockHeight(); i++ {
+		b, err := bc.GetBlock(bc.GetHeaderHash(int(i)))
+		require.NoError(t, err)
+		var aers []state.AppExecResult
+		res, err := bc.GetAppExecResults(b.Hash(), trigger.OnPersist)
+		require.NoError(t, err)
+		aers = append(aers, res...)
+		for _, tx := range b.Transactions {
+			res, err = bc.GetAppExecResults(tx.Hash(), trigger.Application)
+			require.NoError(t, err)
+			aers = append(aers, res...)
+		}
+		res, err = bc.GetAppExecResults(b.Hash(), trigger.PostPersist)
+		require.NoError(t, err)
+		aers = append(aers, res...)
+		for _, aer := range aers {
+			for _, ev := range aer.Events {
+				if ev.ScriptHash == gasHash {
+					expected = append(expected, state.IndexedNotificationEvent{
+						BlockIndex:        i,
+						Container:         aer.Container,
+						Trigger:           aer.Trigger,
+						NotificationEvent: ev,
+					})
+				}
+			}
+		}
+	}
+	require.True(t, len(expected) > 1)
+
+	t.Run("all", func(t *testing.T) {
+		res, err := bc.GetNotifications(gasHash, "", 0, bc.BlockHeight(), 0)
+		require.NoError(t, err)
+		require.Equal(t, expected, res)
+
+		res, err = bc.GetNotifications(gasHash, "Transfer", 0, bc.BlockHeight(), 0)
+		require.NoError(t, err)
+		require.Equal(t, expected, res)
+	})
+	t.Run("unknown name", func(t *testing.T) {
+		res, err := bc.GetNotifications(gasHash, "Unknown", 0, bc.BlockHeight(), 0)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(res))
+	})
+	t.Run("range", func(t *testing.T) {
+		last := expected[len(expected)-1].BlockIndex
+		res, err := bc.GetNotifications(gasHash, "", last, last, 0)
+		require.NoError(t, err)
+		for _, ev := range res {
+			require.Equal(t, last, ev.BlockIndex)
+		}
+		var exp []state.IndexedNotificationEvent
+		for _, ev := range expected {
+			if ev.BlockIndex == last {
+				exp = append(exp, ev)
+			}
+		}
+		require.Equal(t, exp, res)
+	})
+	t.Run("limit", func(t *testing.T) {
+		res, err := bc.GetNotifications(gasHash, "", 0, bc.BlockHeight(), 1)
+		require.NoError(t, err)
+		var exp []state.IndexedNotificationEvent
+		for _, ev := range expected {
+			if ev.BlockIndex == expected[0].BlockIndex {
+				exp = append(exp, ev)
+			}
+		}
+		require.Equal(t, exp, res)
+	})
+}
+
 func TestGetClaimable(t *testing.T) {
 	bc := newTestChain(t)
 
@@ -1636,6 +1726,30 @@ func TestRemoveUntraceable(t *testing.T) {
 
 		check(t, bc, tx1.Hash(), b1.Hash(), true)
 	})
+	t.Run("notification index", func(t *testing.T) {
+		bc := newTestChainWithCustomCfg(t, func(c *config.Config) {
+			c.ProtocolConfiguration.MaxTraceableBlocks = 2
+			c.ProtocolConfiguration.RemoveUntraceableBlocks = true
+			c.ProtocolConfiguration.NotificationIndex = true
+		})
+
+		tx, err := testchain.NewTransferFromOwner(bc, bc.contracts.NEO.Hash, util.Uint160{}, 1, 0, bc.BlockHeight()+1)
+		require.NoError(t, err)
+		b := bc.newBlock(tx)
+		require.NoError(t, bc.AddBlock(b))
+		entries, err := bc.dao.GetNotificationIndex(bc.contracts.NEO.Hash, b.Index, b.Index, 0)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(entries))
+
+		require.NoError(t, bc.AddBlock(bc.newBlock()))
+		require.NoError(t, bc.AddBlock(bc.newBlock()))
+		check(t, bc, tx.Hash(), b.Hash(), true)
+		for _, h := range []util.Uint160{bc.contracts.NEO.Hash, bc.contracts.GAS.Hash} {
+			entries, err = bc.dao.GetNotificationIndex(h, b.Index, b.Index, 0)
+			require.NoError(t, err)
+			require.Equal(t, 0, len(entries))
+		}
+	})
 	t.Run("P2PStateExchangeExtensions on", func(t *testing.T) {
 		bc := newTestChainWithCustomCfg(t, func(c *config.Config) {
 			c.ProtocolConfiguration.MaxTraceableBlocks = 2
@@ -53,6 +53,7 @@ type Blockchainer interface {
 	GetTokenLastUpdated(acc util.Uint160) (map[int32]uint32, error)
 	GetNotaryContractScriptHash() util.Uint160
 	GetNotaryBalance(acc util.Uint160) *big.Int
+	GetNotifications(contract util.Uint160, name string, start, end uint32, limit int) ([]state.IndexedNotificationEvent, error)
 	GetPolicer() Policer
 	GetValidators() ([]*keys.PublicKey, error)
 	GetStandByCommittee() keys.PublicKeys
@@ -61,6 +62,7 @@ type Blockchainer interface {
 	GetStateSyncModule() StateSync
 	GetStorageItem(id int32, key []byte) state.StorageItem
 	GetStorageItems(id int32) ([]state.StorageItemWithKey, error)
+	GetTestHistoricVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) (*vm.VM, func(), error)
 	GetTestVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) (*vm.VM, func())
 	GetTransaction(util.Uint256) (*transaction.Transaction, uint32, error)
 	SetOracle(service services.Oracle)
@@ -17,6 +17,7 @@ type StateRoot interface {
 	FindStates(root util.Uint256, prefix, start []byte, max int) ([]storage.KeyValue, error)
 	GetState(root util.Uint256, key []byte) ([]byte, error)
 	GetStateProof(root util.Uint256, key []byte) ([][]byte, error)
+	GetLatestStateHeight(root util.Uint256) (uint32, error)
 	GetStateRoot(height uint32) (*state.MPTRoot, error)
 	GetStateValidators(height uint32) keys.PublicKeys
 	SetUpdateValidatorsCallback(func(uint32, keys.PublicKeys))
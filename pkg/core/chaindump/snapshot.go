@@ -0,0 +1,216 @@
+package chaindump
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/blockchainer"
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+const (
+	// snapshotVersion is the current version of snapshot format.
+	snapshotVersion = 0
+	// snapshotHeadersBatch is the number of headers passed to the state
+	// sync module at once.
+	snapshotHeadersBatch = 2000
+	// snapshotNodesBatch is the number of MPT nodes passed to the state
+	// sync module at once.
+	snapshotNodesBatch = 1000
+)
+
+// ErrSnapshotIncomplete is returned by RestoreSnapshot if snapshot doesn't
+// contain all the data needed to complete state synchronisation.
+var ErrSnapshotIncomplete = errors.New("snapshot is incomplete")
+
+// DumpSnapshot writes state snapshot for the state sync point p to the
+// provided writer. Snapshot contains network magic, p, state root for p,
+// all headers up to p+1, blocks starting from p-MaxTraceableBlocks+1 up to p
+// and all MPT nodes for the state root of height p, that's exactly the data
+// that is needed for state sync module to perform a state jump.
+func DumpSnapshot(bc blockchainer.Blockchainer, w *io.BinWriter, p uint32) error {
+	cfg := bc.GetConfig()
+	if p+1 > bc.HeaderHeight() {
+		return fmt.Errorf("header height %d is too low to dump snapshot at %d", bc.HeaderHeight(), p)
+	}
+	if cfg.StateSyncInterval > 0 && p%uint32(cfg.StateSyncInterval) != 0 {
+		return fmt.Errorf("height %d is not a state sync point (StateSyncInterval is %d)", p, cfg.StateSyncInterval)
+	}
+	sr, err := bc.GetStateModule().GetStateRoot(p)
+	if err != nil {
+		return fmt.Errorf("failed to get state root for %d: %w", p, err)
+	}
+
+	w.WriteU32LE(uint32(cfg.Magic))
+	w.WriteB(snapshotVersion)
+	w.WriteU32LE(p)
+	sr.Root.EncodeBinary(w)
+
+	w.WriteU32LE(p + 2)
+	for i := uint32(0); i <= p+1; i++ {
+		h, err := bc.GetHeader(bc.GetHeaderHash(int(i)))
+		if err != nil {
+			return fmt.Errorf("failed to get header %d: %w", i, err)
+		}
+		h.EncodeBinary(w)
+		if w.Err != nil {
+			return w.Err
+		}
+	}
+
+	var start uint32 = 1
+	if p > cfg.MaxTraceableBlocks {
+		start = p - cfg.MaxTraceableBlocks + 1
+	}
+	w.WriteU32LE(start)
+	w.WriteU32LE(p + 1 - start)
+	for i := start; i <= p; i++ {
+		b, err := bc.GetBlock(bc.GetHeaderHash(int(i)))
+		if err != nil {
+			return fmt.Errorf("failed to get block %d: %w", i, err)
+		}
+		if len(b.Transactions) == 0 && !b.MerkleRoot.Equals(util.Uint256{}) {
+			return fmt.Errorf("block %d is untraceable", i)
+		}
+		buf := io.NewBufBinWriter()
+		b.EncodeBinary(buf.BinWriter)
+		w.WriteVarBytes(buf.Bytes())
+		if w.Err != nil {
+			return w.Err
+		}
+	}
+
+	err = bc.GetStateSyncModule().Traverse(sr.Root, func(_ mpt.Node, nodeBytes []byte) bool {
+		w.WriteVarBytes(nodeBytes)
+		return w.Err != nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to traverse MPT: %w", err)
+	}
+	// Empty node marks the end of MPT nodes list.
+	w.WriteVarBytes([]byte{})
+	return w.Err
+}
+
+// RestoreSnapshot restores state from the snapshot created by DumpSnapshot
+// using the state sync module of the provided chain. The chain must be a
+// fresh one (containing the genesis block only) configured for state
+// synchronisation (P2PStateExchangeExtensions and RemoveUntraceableBlocks
+// must be enabled). If everything is OK, the chain jumps to the snapshot's
+// state sync point.
+func RestoreSnapshot(bc blockchainer.Blockchainer, r *io.BinReader) error {
+	cfg := bc.GetConfig()
+	if !cfg.P2PStateExchangeExtensions || !cfg.RemoveUntraceableBlocks {
+		return errors.New("P2PStateExchangeExtensions and RemoveUntraceableBlocks must be enabled to restore snapshot")
+	}
+	if bc.BlockHeight() != 0 {
+		return fmt.Errorf("chain is not empty: block height is %d", bc.BlockHeight())
+	}
+	magic := r.ReadU32LE()
+	version := r.ReadB()
+	p := r.ReadU32LE()
+	var root util.Uint256
+	root.DecodeBinary(r)
+	if r.Err != nil {
+		return fmt.Errorf("failed to read snapshot header: %w", r.Err)
+	}
+	if magic != uint32(cfg.Magic) {
+		return fmt.Errorf("network magic mismatch: expected %d, got %d", cfg.Magic, magic)
+	}
+	if version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", version)
+	}
+	if p%uint32(cfg.StateSyncInterval) != 0 {
+		return fmt.Errorf("snapshot height %d is not a state sync point (StateSyncInterval is %d)", p, cfg.StateSyncInterval)
+	}
+
+	sm := bc.GetStateSyncModule()
+	if err := sm.Init(p); err != nil {
+		return fmt.Errorf("failed to initialize state sync module: %w", err)
+	}
+	if !sm.IsActive() {
+		return fmt.Errorf("state sync for %d is not possible", p)
+	}
+
+	count := r.ReadU32LE()
+	if r.Err != nil {
+		return r.Err
+	}
+	if count != p+2 {
+		return fmt.Errorf("invalid number of headers: expected %d, got %d", p+2, count)
+	}
+	hdrs := make([]*block.Header, 0, snapshotHeadersBatch)
+	for i := uint32(0); i < count; i++ {
+		h := &block.Header{StateRootEnabled: cfg.StateRootInHeader}
+		h.DecodeBinary(r)
+		if r.Err != nil {
+			return fmt.Errorf("failed to read header %d: %w", i, r.Err)
+		}
+		if i == 0 {
+			if !h.Hash().Equals(bc.GetHeaderHash(0)) {
+				return errors.New("genesis block mismatch")
+			}
+			continue
+		}
+		if i == p+1 && !h.PrevStateRoot.Equals(root) {
+			return fmt.Errorf("state root mismatch: snapshot has %s, header %d has %s", root.StringLE(), i, h.PrevStateRoot.StringLE())
+		}
+		hdrs = append(hdrs, h)
+		if len(hdrs) == snapshotHeadersBatch || i == count-1 {
+			if err := sm.AddHeaders(hdrs...); err != nil {
+				return fmt.Errorf("failed to add headers: %w", err)
+			}
+			hdrs = hdrs[:0]
+		}
+	}
+
+	start := r.ReadU32LE()
+	count = r.ReadU32LE()
+	if r.Err != nil {
+		return r.Err
+	}
+	for i := start; i < start+count; i++ {
+		buf := r.ReadVarBytes()
+		if r.Err != nil {
+			return fmt.Errorf("failed to read block %d: %w", i, r.Err)
+		}
+		b := block.New(cfg.StateRootInHeader)
+		br := io.NewBinReaderFromBuf(buf)
+		b.DecodeBinary(br)
+		if br.Err != nil {
+			return fmt.Errorf("failed to decode block %d: %w", i, br.Err)
+		}
+		if err := sm.AddBlock(b); err != nil {
+			return fmt.Errorf("failed to add block %d: %w", i, err)
+		}
+	}
+
+	nodes := make([][]byte, 0, snapshotNodesBatch)
+	for {
+		node := r.ReadVarBytes()
+		if r.Err != nil {
+			return fmt.Errorf("failed to read MPT node: %w", r.Err)
+		}
+		if len(node) != 0 {
+			nodes = append(nodes, node)
+		}
+		if len(nodes) == snapshotNodesBatch || (len(node) == 0 && len(nodes) != 0) {
+			if sm.NeedMPTNodes() {
+				if err := sm.AddMPTNodes(nodes); err != nil {
+					return fmt.Errorf("failed to add MPT nodes: %w", err)
+				}
+			}
+			nodes = nodes[:0]
+		}
+		if len(node) == 0 {
+			break
+		}
+	}
+	if sm.IsActive() {
+		return ErrSnapshotIncomplete
+	}
+	return nil
+}
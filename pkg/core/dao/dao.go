@@ -33,6 +33,7 @@ type DAO interface {
 	AppendAppExecResult(aer *state.AppExecResult, buf *io.BufBinWriter) error
 	DeleteBlock(h util.Uint256, buf *io.BufBinWriter) error
 	DeleteContractID(id int32) error
+	DeleteNotificationIndex(h util.Uint256) error
 	DeleteStorageItem(id int32, key []byte) error
 	GetAndDecode(entity io.Serializable, key []byte) error
 	GetAppExecResults(hash util.Uint256, trig trigger.Type) ([]state.AppExecResult, error)
@@ -42,6 +43,7 @@ type DAO interface {
 	GetCurrentBlockHeight() (uint32, error)
 	GetCurrentHeaderHeight() (i uint32, h util.Uint256, err error)
 	GetHeaderHashes() ([]util.Uint256, error)
+	GetNotificationIndex(contract util.Uint160, start, end uint32, limit int) ([]NotificationIndexEntry, error)
 	GetTokenTransferInfo(acc util.Uint160) (*state.TokenTransferInfo, error)
 	GetTokenTransferLog(acc util.Uint160, index uint32, isNEP11 bool) (*state.TokenTransferLog, error)
 	GetStateSyncPoint() (uint32, error)
@@ -58,6 +60,7 @@ type DAO interface {
 	PutAppExecResult(aer *state.AppExecResult, buf *io.BufBinWriter) error
 	PutContractID(id int32, hash util.Uint160) error
 	PutCurrentHeader(hashAndIndex []byte) error
+	PutNotificationIndex(contract util.Uint160, e NotificationIndexEntry) error
 	PutTokenTransferInfo(acc util.Uint160, bs *state.TokenTransferInfo) error
 	PutTokenTransferLog(acc util.Uint160, index uint32, isNEP11 bool, lg *state.TokenTransferLog) error
 	PutStateSyncPoint(p uint32) error
@@ -273,6 +276,129 @@ func (dao *Simple) PutAppExecResult(aer *state.AppExecResult, buf *io.BufBinWrit
 
 // -- end notification event.
 
+// -- start notification index.
+
+// NotificationIndexEntry is a notification index record pointing to the
+// execution result having notifications of some contract.
+type NotificationIndexEntry struct {
+	// BlockIndex is the index of the block the execution belongs to.
+	BlockIndex uint32
+	// Position is the position of the execution inside the block: OnPersist
+	// is the first one, then transactions follow and PostPersist is the last.
+	Position uint32
+	// Container is the hash of execution container (block or transaction).
+	Container util.Uint256
+	// Trigger is the trigger of the execution.
+	Trigger trigger.Type
+}
+
+func makeNotificationIndexKey(contract util.Uint160, index uint32, pos uint32) []byte {
+	key := make([]byte, 1+util.Uint160Size+4+4)
+	key[0] = byte(storage.STNotificationIndex)
+	copy(key[1:], contract.BytesBE())
+	// Big-endian is used to keep entries sorted by block and position.
+	binary.BigEndian.PutUint32(key[1+util.Uint160Size:], index)
+	binary.BigEndian.PutUint32(key[1+util.Uint160Size+4:], pos)
+	return key
+}
+
+// PutNotificationIndex stores notification index entry for the given contract.
+func (dao *Simple) PutNotificationIndex(contract util.Uint160, e NotificationIndexEntry) error {
+	key := makeNotificationIndexKey(contract, e.BlockIndex, e.Position)
+	val := make([]byte, util.Uint256Size+1)
+	copy(val, e.Container.BytesBE())
+	val[util.Uint256Size] = byte(e.Trigger)
+	return dao.Store.Put(key, val)
+}
+
+// GetNotificationIndex returns notification index entries of the given
+// contract for blocks from start to end (both inclusive) sorted by block
+// index and execution position. If limit is positive, entries are returned up
+// to the first block where their overall number reaches it, so the entries of
+// a single block are never split.
+func (dao *Simple) GetNotificationIndex(contract util.Uint160, start, end uint32, limit int) ([]NotificationIndexEntry, error) {
+	var (
+		err    error
+		res    []NotificationIndexEntry
+		prefix = storage.AppendPrefix(storage.STNotificationIndex, contract.BytesBE())
+		from   = make([]byte, 4)
+	)
+	binary.BigEndian.PutUint32(from, start)
+	dao.Store.SeekRange(prefix, from, func(k, v []byte) bool {
+		if len(k) != len(prefix)+8 || len(v) != util.Uint256Size+1 {
+			err = fmt.Errorf("invalid notification index entry %x", k)
+			return false
+		}
+		index := binary.BigEndian.Uint32(k[len(prefix):])
+		if index > end || (limit > 0 && len(res) >= limit && index != res[len(res)-1].BlockIndex) {
+			return false
+		}
+		e := NotificationIndexEntry{
+			BlockIndex: index,
+			Position:   binary.BigEndian.Uint32(k[len(prefix)+4:]),
+			Trigger:    trigger.Type(v[util.Uint256Size]),
+		}
+		e.Container, err = util.Uint256DecodeBytesBE(v[:util.Uint256Size])
+		if err != nil {
+			return false
+		}
+		res = append(res, e)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteNotificationIndex removes notification index entries for all
+// executions of the block with the given hash. It must be called before the
+// block and its execution results are removed.
+func (dao *Simple) DeleteNotificationIndex(h util.Uint256) error {
+	b, err := dao.GetBlock(h)
+	if err != nil {
+		return err
+	}
+	var (
+		batch = dao.Store.Batch()
+		del   = func(hash util.Uint256, trig trigger.Type, pos func(*state.AppExecResult) uint32) error {
+			aers, err := dao.GetAppExecResults(hash, trig)
+			if err != nil {
+				if errors.Is(err, storage.ErrKeyNotFound) {
+					return nil
+				}
+				return err
+			}
+			for i := range aers {
+				for _, ev := range aers[i].Events {
+					batch.Delete(makeNotificationIndexKey(ev.ScriptHash, b.Index, pos(&aers[i])))
+				}
+			}
+			return nil
+		}
+	)
+	// OnPersist is the first execution of the block, PostPersist is the last one.
+	err = del(h, trigger.OnPersist|trigger.PostPersist, func(aer *state.AppExecResult) uint32 {
+		if aer.Trigger == trigger.OnPersist {
+			return 0
+		}
+		return uint32(len(b.Transactions)) + 1
+	})
+	if err != nil {
+		return err
+	}
+	for i, tx := range b.Transactions {
+		pos := uint32(i) + 1
+		err = del(tx.Hash(), trigger.Application, func(*state.AppExecResult) uint32 { return pos })
+		if err != nil {
+			return err
+		}
+	}
+	return dao.Store.PutBatch(batch)
+}
+
+// -- end notification index.
+
 // -- start storage item.
 
 // GetStorageItem returns StorageItem if it exists in the given store.
@@ -61,6 +61,57 @@ func TestPutGetAppExecResult(t *testing.T) {
 	require.Equal(t, []state.AppExecResult{*appExecResult}, gotAppExecResult)
 }
 
+func TestPutGetNotificationIndex(t *testing.T) {
+	dao := NewSimple(storage.NewMemoryStore(), false, false)
+	h1, h2 := random.Uint160(), random.Uint160()
+	entries := []NotificationIndexEntry{
+		{BlockIndex: 1, Position: 0, Container: random.Uint256(), Trigger: trigger.OnPersist},
+		{BlockIndex: 1, Position: 2, Container: random.Uint256(), Trigger: trigger.Application},
+		{BlockIndex: 2, Position: 1, Container: random.Uint256(), Trigger: trigger.Application},
+		{BlockIndex: 256, Position: 3, Container: random.Uint256(), Trigger: trigger.PostPersist},
+	}
+	// Put in reverse order to check sorting.
+	for i := len(entries) - 1; i >= 0; i-- {
+		require.NoError(t, dao.PutNotificationIndex(h1, entries[i]))
+	}
+	require.NoError(t, dao.PutNotificationIndex(h2, entries[0]))
+
+	res, err := dao.GetNotificationIndex(h1, 0, 1000, 0)
+	require.NoError(t, err)
+	require.Equal(t, entries, res)
+
+	res, err = dao.GetNotificationIndex(h1, 2, 256, 0)
+	require.NoError(t, err)
+	require.Equal(t, entries[2:], res)
+
+	res, err = dao.GetNotificationIndex(h1, 3, 255, 0)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(res))
+
+	res, err = dao.GetNotificationIndex(h2, 0, 1000, 0)
+	require.NoError(t, err)
+	require.Equal(t, entries[:1], res)
+
+	res, err = dao.GetNotificationIndex(random.Uint160(), 0, 1000, 0)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(res))
+
+	t.Run("limit", func(t *testing.T) {
+		// Entries of a single block are not split.
+		res, err := dao.GetNotificationIndex(h1, 0, 1000, 1)
+		require.NoError(t, err)
+		require.Equal(t, entries[:2], res)
+
+		res, err = dao.GetNotificationIndex(h1, 0, 1000, 3)
+		require.NoError(t, err)
+		require.Equal(t, entries[:3], res)
+
+		res, err = dao.GetNotificationIndex(h1, 2, 1000, 1)
+		require.NoError(t, err)
+		require.Equal(t, entries[2:3], res)
+	})
+}
+
 func TestPutGetStorageItem(t *testing.T) {
 	dao := NewSimple(storage.NewMemoryStore(), false, false)
 	id := int32(random.Int(0, 1024))
@@ -49,3 +49,23 @@ func TestIterator(t *testing.T) {
 	require.NoError(t, Next(ic))
 	require.False(t, false, ic.VM.Estack().Pop().Bool())
 }
+
+func TestValues(t *testing.T) {
+	full := []int{4, 8, 15, 16, 23}
+	it := stackitem.NewInterop(&testIter{index: -1, arr: full})
+
+	res := Values(it, 2)
+	require.Equal(t, 2, len(res))
+	require.Equal(t, big.NewInt(4), res[0].Value())
+	require.Equal(t, big.NewInt(8), res[1].Value())
+
+	res = Values(it, 2)
+	require.Equal(t, 2, len(res))
+	require.Equal(t, big.NewInt(15), res[0].Value())
+	require.Equal(t, big.NewInt(16), res[1].Value())
+
+	res, truncated := ValuesTruncated(it, 2)
+	require.Equal(t, 1, len(res))
+	require.Equal(t, big.NewInt(23), res[0].Value())
+	require.False(t, truncated)
+}
@@ -0,0 +1,121 @@
+package mpt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/util/slice"
+)
+
+// TrieStore is an MPT-based storage implementation for retrieving historic
+// contract storage data. Contract storage items (STStorage-prefixed keys) are
+// read from the MPT with the specified root, all other keys are read from the
+// underlying store as is. TrieStore is read-only, it's supposed to be used
+// within test script invocations wrapped by MemCachedStore to properly handle
+// any write operations.
+type TrieStore struct {
+	trie    *Trie
+	backend storage.Store
+}
+
+// ErrForbiddenTrieStoreOperation is returned when operation is not supported
+// by TrieStore.
+var ErrForbiddenTrieStoreOperation = errors.New("operation is not allowed to be performed over TrieStore")
+
+// NewTrieStore returns new TrieStore instance for the specified MPT root
+// backed by the specified storage.
+func NewTrieStore(root util.Uint256, backend storage.Store) *TrieStore {
+	tr := NewTrie(NewHashNode(root), false, storage.NewMemCachedStore(backend))
+	return &TrieStore{
+		trie:    tr,
+		backend: backend,
+	}
+}
+
+// Batch implements storage.Store interface.
+func (m *TrieStore) Batch() storage.Batch {
+	return storage.NewMemoryStore().Batch()
+}
+
+// Delete implements storage.Store interface. It always returns an error.
+func (m *TrieStore) Delete(k []byte) error {
+	return fmt.Errorf("%w: Delete is not supported", ErrForbiddenTrieStoreOperation)
+}
+
+// Get implements storage.Store interface.
+func (m *TrieStore) Get(key []byte) ([]byte, error) {
+	if len(key) == 0 || storage.KeyPrefix(key[0]) != storage.STStorage {
+		return m.backend.Get(key)
+	}
+	res, err := m.trie.Get(key[1:])
+	if err != nil && errors.Is(err, ErrNotFound) {
+		// Mimic the real storage behaviour.
+		return nil, storage.ErrKeyNotFound
+	}
+	return res, err
+}
+
+// Put implements storage.Store interface. It always returns an error.
+func (m *TrieStore) Put(k, v []byte) error {
+	return fmt.Errorf("%w: Put is not supported", ErrForbiddenTrieStoreOperation)
+}
+
+// PutBatch implements storage.Store interface. It always returns an error.
+func (m *TrieStore) PutBatch(storage.Batch) error {
+	return fmt.Errorf("%w: PutBatch is not supported", ErrForbiddenTrieStoreOperation)
+}
+
+// PutChangeSet implements storage.Store interface. It always returns an error.
+func (m *TrieStore) PutChangeSet(puts map[string][]byte, dels map[string]bool) error {
+	return fmt.Errorf("%w: PutChangeSet is not supported", ErrForbiddenTrieStoreOperation)
+}
+
+// Seek implements storage.Store interface. Contract storage items are
+// traversed in ascending key order the same way as for any other Store.
+func (m *TrieStore) Seek(key []byte, f func(k, v []byte)) {
+	if len(key) == 0 || storage.KeyPrefix(key[0]) != storage.STStorage {
+		m.backend.Seek(key, f)
+		return
+	}
+	prefixP := toNibbles(key[1:])
+	_, start, path, err := m.trie.getWithPath(m.trie.root, prefixP, false)
+	if err != nil {
+		// Failed to determine the start node => no matching items.
+		return
+	}
+	path = path[len(prefixP):]
+
+	var res []storage.KeyValue
+	b := NewBillet(m.trie.root.Hash(), false, m.trie.Store)
+	process := func(pathToNode []byte, node Node, _ []byte) bool {
+		if leaf, ok := node.(*LeafNode); ok {
+			res = append(res, storage.KeyValue{
+				Key:   append(slice.Copy(key), pathToNode...),
+				Value: slice.Copy(leaf.value),
+			})
+		}
+		return false
+	}
+	_, err = b.traverse(start, path, []byte{}, process, false)
+	if err != nil {
+		return
+	}
+	// MPT traversal order differs from the byte-wise one (value of a branch
+	// node goes after its children), but Store users expect sorted items.
+	sort.Slice(res, func(i, j int) bool {
+		return bytes.Compare(res[i].Key, res[j].Key) < 0
+	})
+	for _, kv := range res {
+		f(kv.Key, kv.Value)
+	}
+}
+
+// Close implements storage.Store interface. It doesn't close the underlying
+// store.
+func (m *TrieStore) Close() error {
+	return nil
+}
@@ -0,0 +1,60 @@
+package mpt
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrieStore_TestTrieOperations(t *testing.T) {
+	source := newTestTrie(t)
+	backed := source.Store
+
+	st := NewTrieStore(source.root.Hash(), backed)
+
+	t.Run("forbidden operations", func(t *testing.T) {
+		require.ErrorIs(t, st.Put([]byte{byte(storage.STStorage), 1}, []byte{1}), ErrForbiddenTrieStoreOperation)
+		require.ErrorIs(t, st.Delete([]byte{byte(storage.STStorage), 1}), ErrForbiddenTrieStoreOperation)
+		require.ErrorIs(t, st.PutBatch(st.Batch()), ErrForbiddenTrieStoreOperation)
+		require.ErrorIs(t, st.PutChangeSet(nil, nil), ErrForbiddenTrieStoreOperation)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		t.Run("good", func(t *testing.T) {
+			res, err := st.Get(append([]byte{byte(storage.STStorage)}, 0xAC, 0x01))
+			require.NoError(t, err)
+			require.Equal(t, []byte{0xAB, 0xCD}, res)
+		})
+		t.Run("missing", func(t *testing.T) {
+			_, err := st.Get(append([]byte{byte(storage.STStorage)}, 0xAC, 0x02))
+			require.ErrorIs(t, err, storage.ErrKeyNotFound)
+		})
+		t.Run("non-storage key", func(t *testing.T) {
+			require.NoError(t, backed.Put([]byte{byte(storage.SYSVersion)}, []byte("v")))
+			res, err := st.Get([]byte{byte(storage.SYSVersion)})
+			require.NoError(t, err)
+			require.Equal(t, []byte("v"), res)
+		})
+	})
+
+	t.Run("Seek", func(t *testing.T) {
+		var res [][]byte
+		st.Seek([]byte{byte(storage.STStorage), 0xAC}, func(k, v []byte) {
+			res = append(res, k)
+		})
+		require.Equal(t, 4, len(res))
+		for i := 0; i < len(res); i++ {
+			require.Equal(t, byte(storage.STStorage), res[i][0])
+			if i < len(res)-1 {
+				require.True(t, string(res[i]) < string(res[i+1]))
+			}
+		}
+
+		res = res[:0]
+		st.Seek([]byte{byte(storage.STStorage), 0xAD}, func(k, v []byte) {
+			res = append(res, k)
+		})
+		require.Equal(t, 0, len(res))
+	})
+}
@@ -154,10 +154,12 @@ func (m *Management) GetContract(d dao.DAO, hash util.Uint160) (*state.Contract,
 	} else if cs != nil {
 		return cs, nil
 	}
-	return m.getContractFromDAO(d, hash)
+	return m.GetContractFromDAO(d, hash)
 }
 
-func (m *Management) getContractFromDAO(d dao.DAO, hash util.Uint160) (*state.Contract, error) {
+// GetContractFromDAO returns contract with given hash retrieved from the given
+// DAO bypassing the contract cache.
+func (m *Management) GetContractFromDAO(d dao.DAO, hash util.Uint160) (*state.Contract, error) {
 	contract := new(state.Contract)
 	key := MakeContractKey(hash)
 	err := getConvertibleFromDAO(m.ID, d, key, contract)
@@ -523,7 +525,7 @@ func (m *Management) PostPersist(ic *interop.Context) error {
 		}
 		delete(m.nep11, h)
 		delete(m.nep17, h)
-		newCs, err := m.getContractFromDAO(ic.DAO, h)
+		newCs, err := m.GetContractFromDAO(ic.DAO, h)
 		if err != nil {
 			// Contract was destroyed.
 			delete(m.contracts, h)
@@ -20,6 +20,16 @@ type NotificationEvent struct {
 	Item       *stackitem.Array `json:"state"`
 }
 
+// IndexedNotificationEvent is a NotificationEvent along with its location in
+// the chain: block index, container (block or transaction) hash and trigger
+// of the execution it was emitted from.
+type IndexedNotificationEvent struct {
+	BlockIndex uint32
+	Container  util.Uint256
+	Trigger    trigger.Type
+	NotificationEvent
+}
+
 // AppExecResult represent the result of the script execution, gathering together
 // all resulting notifications, state, stack and other metadata.
 type AppExecResult struct {
@@ -132,6 +142,56 @@ func (ne *NotificationEvent) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// indexedNotificationEventAux is an auxiliary struct for
+// IndexedNotificationEvent JSON marshalling.
+type indexedNotificationEventAux struct {
+	BlockIndex uint32       `json:"blockindex"`
+	Container  util.Uint256 `json:"container"`
+	Trigger    string       `json:"trigger"`
+}
+
+// MarshalJSON implements implements json.Marshaler interface.
+func (ne IndexedNotificationEvent) MarshalJSON() ([]byte, error) {
+	h, err := json.Marshal(&indexedNotificationEventAux{
+		BlockIndex: ne.BlockIndex,
+		Container:  ne.Container,
+		Trigger:    ne.Trigger.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal location: %w", err)
+	}
+	event, err := json.Marshal(ne.NotificationEvent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if h[len(h)-1] != '}' || event[0] != '{' {
+		return nil, errors.New("can't merge internal jsons")
+	}
+	h[len(h)-1] = ','
+	h = append(h, event[1:]...)
+	return h, nil
+}
+
+// UnmarshalJSON implements implements json.Unmarshaler interface.
+func (ne *IndexedNotificationEvent) UnmarshalJSON(data []byte) error {
+	aux := new(indexedNotificationEventAux)
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+	trig, err := trigger.FromString(aux.Trigger)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &ne.NotificationEvent); err != nil {
+		return err
+	}
+	ne.BlockIndex = aux.BlockIndex
+	ne.Container = aux.Container
+	ne.Trigger = trig
+	return nil
+}
+
 // appExecResultAux is an auxiliary struct for JSON marshalling.
 type appExecResultAux struct {
 	Container util.Uint256 `json:"container"`
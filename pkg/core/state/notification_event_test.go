@@ -144,6 +144,36 @@ func TestMarshalUnmarshalJSONNotificationEvent(t *testing.T) {
 	})
 }
 
+func TestMarshalUnmarshalJSONIndexedNotificationEvent(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		ne := &IndexedNotificationEvent{
+			BlockIndex: 123,
+			Container:  random.Uint256(),
+			Trigger:    trigger.Application,
+			NotificationEvent: NotificationEvent{
+				ScriptHash: random.Uint160(),
+				Name:       "my_ne",
+				Item: stackitem.NewArray([]stackitem.Item{
+					stackitem.NewBool(true),
+				}),
+			},
+		}
+		testserdes.MarshalUnmarshalJSON(t, ne, new(IndexedNotificationEvent))
+	})
+
+	t.Run("UnmarshalJSON error", func(t *testing.T) {
+		errorCases := []string{
+			`{"blockindex":1,"container":"0xBadHash","trigger":"Application","contract":"0xab2f820e2aa7cca1e081283c58a7d7943c33a2f1","eventname":"my_ne","state":{"type":"Array","value":[]}}`,
+			`{"blockindex":1,"container":"0xe1cd5e57e721d2a2e05fb1f08721b12057b25ab1dd7fd0f33ee1639932fdfad7","trigger":"BadTrigger","contract":"0xab2f820e2aa7cca1e081283c58a7d7943c33a2f1","eventname":"my_ne","state":{"type":"Array","value":[]}}`,
+			`{"blockindex":1,"container":"0xe1cd5e57e721d2a2e05fb1f08721b12057b25ab1dd7fd0f33ee1639932fdfad7","trigger":"Application","contract":"0xab2f820e2aa7cca1e081283c58a7d7943c33a2f1","eventname":"my_ne","state":{"type":"Boolean","value":true}}`,
+		}
+		for _, errCase := range errorCases {
+			err := json.Unmarshal([]byte(errCase), new(IndexedNotificationEvent))
+			require.Error(t, err)
+		}
+	})
+}
+
 func TestMarshalUnmarshalJSONAppExecResult(t *testing.T) {
 	t.Run("positive, transaction", func(t *testing.T) {
 		appExecResult := &AppExecResult{
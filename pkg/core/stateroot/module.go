@@ -25,6 +25,8 @@ type (
 		mpt     *mpt.Trie
 		bc      blockchainer.Blockchainer
 		log     *zap.Logger
+		// keepOnlyLatest disables state root index, see KeepOnlyLatestState.
+		keepOnlyLatest bool
 
 		currentLocal    atomic.Value
 		localHeight     atomic.Uint32
@@ -81,6 +83,17 @@ func (s *Module) GetStateRoot(height uint32) (*state.MPTRoot, error) {
 	return s.getStateRoot(makeStateRootKey(height))
 }
 
+// GetLatestStateHeight returns the latest height the state with the specified
+// root was stored at. The index of state roots is not maintained with
+// KeepOnlyLatestState setting enabled.
+func (s *Module) GetLatestStateHeight(root util.Uint256) (uint32, error) {
+	data, err := s.Store.Get(makeStateRootIndexKey(root))
+	if err != nil {
+		return 0, fmt.Errorf("state root %s: %w", root.StringLE(), err)
+	}
+	return binary.LittleEndian.Uint32(data), nil
+}
+
 // CurrentLocalStateRoot returns hash of the local state root.
 func (s *Module) CurrentLocalStateRoot() util.Uint256 {
 	return s.currentLocal.Load().(util.Uint256)
@@ -103,6 +116,7 @@ func (s *Module) Init(height uint32, enableRefCount bool) error {
 		s.validatedHeight.Store(binary.LittleEndian.Uint32(data))
 	}
 
+	s.keepOnlyLatest = enableRefCount
 	var gcKey = []byte{byte(storage.DataMPT), prefixGC}
 	if height == 0 {
 		s.mpt = mpt.NewTrie(nil, enableRefCount, s.Store)
@@ -111,6 +125,9 @@ func (s *Module) Init(height uint32, enableRefCount bool) error {
 			val = 1
 		}
 		s.currentLocal.Store(util.Uint256{})
+		if err := s.Store.Put(rootIndexVersionKey(), []byte{rootIndexVersion}); err != nil {
+			return err
+		}
 		return s.Store.Put(gcKey, []byte{val})
 	}
 	var hasRefCount bool
@@ -120,6 +137,11 @@ func (s *Module) Init(height uint32, enableRefCount bool) error {
 	if hasRefCount != enableRefCount {
 		return fmt.Errorf("KeepOnlyLatestState setting mismatch: old=%v, new=%v", hasRefCount, enableRefCount)
 	}
+	if _, err := s.Store.Get(rootIndexVersionKey()); err != nil && !enableRefCount {
+		if err := s.buildStateRootIndex(height); err != nil {
+			return fmt.Errorf("failed to build state root index: %w", err)
+		}
+	}
 	r, err := s.getStateRoot(makeStateRootKey(height))
 	if err != nil {
 		return err
@@ -131,7 +153,7 @@ func (s *Module) Init(height uint32, enableRefCount bool) error {
 }
 
 // CleanStorage removes all MPT-related data from the storage (MPT nodes, validated stateroots)
-// except local stateroot for the current height and GC flag. This method is aimed to clean
+// except local stateroot for the current height, GC flag and state root index version. This method is aimed to clean
 // outdated MPT data before state sync process can be started.
 // Note: this method is aimed to be called for genesis block only, an error is returned otherwice.
 func (s *Module) CleanStorage() error {
@@ -157,6 +179,10 @@ func (s *Module) CleanStorage() error {
 	if err != nil {
 		return fmt.Errorf("failed to store GC flag: %w", err)
 	}
+	err = s.Store.Put(rootIndexVersionKey(), []byte{rootIndexVersion})
+	if err != nil {
+		return fmt.Errorf("failed to store state root index version: %w", err)
+	}
 	currentLocal := s.currentLocal.Load().(util.Uint256)
 	if !currentLocal.Equals(util.Uint256{}) {
 		err := s.addLocalStateRoot(s.Store, &state.MPTRoot{
@@ -172,6 +198,7 @@ func (s *Module) CleanStorage() error {
 
 // JumpToState performs jump to the state specified by given stateroot index.
 func (s *Module) JumpToState(sr *state.MPTRoot, enableRefCount bool) error {
+	s.keepOnlyLatest = enableRefCount
 	if err := s.addLocalStateRoot(s.Store, sr); err != nil {
 		return fmt.Errorf("failed to store local state root: %w", err)
 	}
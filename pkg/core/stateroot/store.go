@@ -8,6 +8,8 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"go.uber.org/zap"
 )
 
 var (
@@ -20,6 +22,15 @@ const (
 	prefixGC        = 0x01
 	prefixLocal     = 0x02
 	prefixValidated = 0x03
+	// prefixRootIndex is used for state root hash to height index.
+	prefixRootIndex = 0x04
+
+	// rootIndexVersion is stored with prefixRootIndex key to mark the
+	// state root index as complete.
+	rootIndexVersion = 1
+	// rootIndexPersistInterval is the number of state roots indexed between
+	// flushes to the persistent store when building the index.
+	rootIndexPersistInterval = 100000
 )
 
 func (s *Module) addLocalStateRoot(store *storage.MemCachedStore, sr *state.MPTRoot) error {
@@ -30,9 +41,45 @@ func (s *Module) addLocalStateRoot(store *storage.MemCachedStore, sr *state.MPTR
 
 	data := make([]byte, 4)
 	binary.LittleEndian.PutUint32(data, sr.Index)
+	// Old states are removed with KeepOnlyLatestState, so there is nothing
+	// to index then.
+	if !s.keepOnlyLatest {
+		// The same root can be stored at several heights, the latest one is kept.
+		if err := store.Put(makeStateRootIndexKey(sr.Root), data); err != nil {
+			return err
+		}
+	}
 	return store.Put([]byte{byte(storage.DataMPT), prefixLocal}, data)
 }
 
+// buildStateRootIndex fills the state root index for all local state roots
+// up to the given height. It's needed for databases created before the
+// index was introduced.
+func (s *Module) buildStateRootIndex(height uint32) error {
+	s.log.Info("building state root index", zap.Uint32("height", height))
+	data := make([]byte, 4)
+	for i := uint32(0); i <= height; i++ {
+		sr, err := s.getStateRoot(makeStateRootKey(i))
+		if err != nil {
+			// State roots before the state sync point are missing.
+			if errors.Is(err, storage.ErrKeyNotFound) {
+				continue
+			}
+			return err
+		}
+		binary.LittleEndian.PutUint32(data, i)
+		if err := s.Store.Put(makeStateRootIndexKey(sr.Root), data); err != nil {
+			return err
+		}
+		if (i+1)%rootIndexPersistInterval == 0 {
+			if _, err := s.Store.Persist(); err != nil {
+				return err
+			}
+		}
+	}
+	return s.Store.Put(rootIndexVersionKey(), []byte{rootIndexVersion})
+}
+
 func putStateRoot(store *storage.MemCachedStore, key []byte, sr *state.MPTRoot) error {
 	w := io.NewBufBinWriter()
 	sr.EncodeBinary(w.BinWriter)
@@ -40,7 +87,18 @@ func putStateRoot(store *storage.MemCachedStore, key []byte, sr *state.MPTRoot)
 }
 
 func (s *Module) getStateRoot(key []byte) (*state.MPTRoot, error) {
-	data, err := s.Store.Get(key)
+	return getStateRoot(s.Store, key)
+}
+
+// GetLocalStateRoot reads the local state root for the given height directly
+// from the given store, so it can be used without Module (and Blockchain)
+// instance, e.g. for offline DB checks.
+func GetLocalStateRoot(s storage.Store, height uint32) (*state.MPTRoot, error) {
+	return getStateRoot(s, makeStateRootKey(height))
+}
+
+func getStateRoot(s storage.Store, key []byte) (*state.MPTRoot, error) {
+	data, err := s.Get(key)
 	if err != nil {
 		return nil, err
 	}
@@ -58,6 +116,14 @@ func makeStateRootKey(index uint32) []byte {
 	return key
 }
 
+func makeStateRootIndexKey(root util.Uint256) []byte {
+	return append(rootIndexVersionKey(), root.BytesBE()...)
+}
+
+func rootIndexVersionKey() []byte {
+	return []byte{byte(storage.DataMPT), prefixRootIndex}
+}
+
 // AddStateRoot adds validated state root provided by network.
 func (s *Module) AddStateRoot(sr *state.MPTRoot) error {
 	if err := s.VerifyStateRoot(sr); err != nil {
@@ -21,6 +21,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/services/stateroot"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/util/slice"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"github.com/stretchr/testify/require"
@@ -164,6 +165,73 @@ func TestStateRootInitNonZeroHeight(t *testing.T) {
 	require.Equal(t, root, srv.CurrentLocalStateRoot())
 }
 
+func TestGetLatestStateHeight(t *testing.T) {
+	bc := newTestChain(t)
+	for i := 0; i < 3; i++ {
+		_, err := persistBlock(bc)
+		require.NoError(t, err)
+	}
+	srv := bc.GetStateModule()
+	for h := uint32(0); h <= bc.BlockHeight(); h++ {
+		r, err := srv.GetStateRoot(h)
+		require.NoError(t, err)
+		latest, err := srv.GetLatestStateHeight(r.Root)
+		require.NoError(t, err)
+		require.True(t, latest >= h)
+		lr, err := srv.GetStateRoot(latest)
+		require.NoError(t, err)
+		require.Equal(t, r.Root, lr.Root)
+		if latest < bc.BlockHeight() {
+			next, err := srv.GetStateRoot(latest + 1)
+			require.NoError(t, err)
+			require.NotEqual(t, r.Root, next.Root)
+		}
+	}
+	_, err := srv.GetLatestStateHeight(util.Uint256{1, 2, 3})
+	require.True(t, errors.Is(err, storage.ErrKeyNotFound), err)
+}
+
+func TestGetLatestStateHeightIndexRebuild(t *testing.T) {
+	st := memoryStore{storage.NewMemoryStore()}
+	heights := make(map[util.Uint256]uint32)
+	t.Run("init", func(t *testing.T) { // this is in a separate test to do proper cleanup
+		bc := newTestChainWithCustomCfgAndStore(t, st, nil)
+		for i := 0; i < 3; i++ {
+			_, err := persistBlock(bc)
+			require.NoError(t, err)
+		}
+		srv := bc.GetStateModule()
+		for h := uint32(0); h <= bc.BlockHeight(); h++ {
+			r, err := srv.GetStateRoot(h)
+			require.NoError(t, err)
+			heights[r.Root], err = srv.GetLatestStateHeight(r.Root)
+			require.NoError(t, err)
+		}
+	})
+
+	// Drop the index like it's a DB created before it was introduced.
+	indexPrefix := []byte{byte(storage.DataMPT), 0x04}
+	var keys [][]byte
+	st.Seek(indexPrefix, func(k, _ []byte) {
+		// MPT nodes (with 32-byte hashes) can have the same prefix.
+		if len(k) == len(indexPrefix) || len(k) == len(indexPrefix)+32 {
+			keys = append(keys, slice.Copy(k))
+		}
+	})
+	require.Equal(t, 1+len(heights), len(keys))
+	for _, k := range keys {
+		require.NoError(t, st.Delete(k))
+	}
+
+	bc := newTestChainWithCustomCfgAndStore(t, st, nil)
+	srv := bc.GetStateModule()
+	for root, h := range heights {
+		latest, err := srv.GetLatestStateHeight(root)
+		require.NoError(t, err)
+		require.Equal(t, h, latest)
+	}
+}
+
 func createAndWriteWallet(t *testing.T, acc *wallet.Account, path, password string) *wallet.Wallet {
 	w, err := wallet.NewWallet(path)
 	require.NoError(t, err)
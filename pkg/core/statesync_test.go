@@ -5,8 +5,10 @@ import (
 
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/chaindump"
 	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/util/slice"
 	"github.com/stretchr/testify/require"
@@ -440,3 +442,61 @@ func TestStateSyncModule_RestoreBasicChain(t *testing.T) {
 		t.Fatal("temp storage items are found")
 	})
 }
+
+func TestStateSyncSnapshot(t *testing.T) {
+	var (
+		stateSyncInterval        = 4
+		maxTraceable      uint32 = 6
+		stateSyncPoint    uint32 = 16
+	)
+	spoutCfg := func(c *config.Config) {
+		c.ProtocolConfiguration.StateRootInHeader = true
+		c.ProtocolConfiguration.P2PStateExchangeExtensions = true
+		c.ProtocolConfiguration.StateSyncInterval = stateSyncInterval
+		c.ProtocolConfiguration.MaxTraceableBlocks = maxTraceable
+	}
+	bcSpout := newTestChainWithCustomCfg(t, spoutCfg)
+	initBasicChain(t, bcSpout)
+	require.NoError(t, bcSpout.AddBlock(bcSpout.newBlock()))
+
+	t.Run("bad height", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		require.Error(t, chaindump.DumpSnapshot(bcSpout, w.BinWriter, stateSyncPoint+1))
+		require.Error(t, chaindump.DumpSnapshot(bcSpout, w.BinWriter, bcSpout.HeaderHeight()))
+	})
+
+	w := io.NewBufBinWriter()
+	require.NoError(t, chaindump.DumpSnapshot(bcSpout, w.BinWriter, stateSyncPoint))
+	require.NoError(t, w.Err)
+	snapshot := w.Bytes()
+
+	boltCfg := func(c *config.Config) {
+		spoutCfg(c)
+		c.ProtocolConfiguration.KeepOnlyLatestState = true
+		c.ProtocolConfiguration.RemoveUntraceableBlocks = true
+	}
+	t.Run("disabled state exchange", func(t *testing.T) {
+		bc := newTestChainWithCustomCfg(t, spoutCfg)
+		require.Error(t, chaindump.RestoreSnapshot(bc, io.NewBinReaderFromBuf(snapshot)))
+	})
+	t.Run("truncated", func(t *testing.T) {
+		bc := newTestChainWithCustomCfg(t, boltCfg)
+		require.Error(t, chaindump.RestoreSnapshot(bc, io.NewBinReaderFromBuf(snapshot[:len(snapshot)-100])))
+	})
+
+	bcBolt := newTestChainWithCustomCfg(t, boltCfg)
+	require.NoError(t, chaindump.RestoreSnapshot(bcBolt, io.NewBinReaderFromBuf(snapshot)))
+	require.Equal(t, stateSyncPoint, bcBolt.BlockHeight())
+	sm := bcBolt.GetStateSyncModule()
+	require.NoError(t, sm.Init(bcSpout.BlockHeight()))
+	require.False(t, sm.IsActive())
+
+	// Regular blocks processing continues after the snapshot.
+	for i := stateSyncPoint + 1; i <= bcSpout.BlockHeight(); i++ {
+		b, err := bcSpout.GetBlock(bcSpout.GetHeaderHash(int(i)))
+		require.NoError(t, err)
+		require.NoError(t, bcBolt.AddBlock(b))
+	}
+	require.Equal(t, bcSpout.BlockHeight(), bcBolt.BlockHeight())
+	require.Equal(t, bcSpout.GetStateModule().CurrentLocalStateRoot(), bcBolt.GetStateModule().CurrentLocalStateRoot())
+}
@@ -123,6 +123,22 @@ func (s *BoltDBStore) Seek(key []byte, f func(k, v []byte)) {
 	}
 }
 
+// SeekRange implements the RangeSeeker interface.
+func (s *BoltDBStore) SeekRange(prefix, start []byte, f func(k, v []byte) bool) {
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		c := tx.Bucket(Bucket).Cursor()
+		for k, v := c.Seek(seekStart(prefix, start)); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			if !f(k, v) {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
 // Batch implements the Batch interface and returns a boltdb
 // compatible Batch.
 func (s *BoltDBStore) Batch() Batch {
@@ -93,6 +93,19 @@ func (s *LevelDBStore) Seek(key []byte, f func(k, v []byte)) {
 	iter.Release()
 }
 
+// SeekRange implements the RangeSeeker interface.
+func (s *LevelDBStore) SeekRange(prefix, start []byte, f func(k, v []byte) bool) {
+	rng := util.BytesPrefix(prefix)
+	rng.Start = seekStart(prefix, start)
+	iter := s.db.NewIterator(rng, nil)
+	for iter.Next() {
+		if !f(iter.Key(), iter.Value()) {
+			break
+		}
+	}
+	iter.Release()
+}
+
 // Batch implements the Batch interface and returns a leveldb
 // compatible Batch.
 func (s *LevelDBStore) Batch() Batch {
@@ -91,7 +91,15 @@ func (s *MemCachedStore) GetBatch() *MemBatch {
 
 // Seek implements the Store interface.
 func (s *MemCachedStore) Seek(key []byte, f func(k, v []byte)) {
-	s.seek(context.Background(), key, false, f)
+	s.seek(context.Background(), key, nil, false, func(k, v []byte) bool {
+		f(k, v)
+		return true
+	})
+}
+
+// SeekRange implements the RangeSeeker interface.
+func (s *MemCachedStore) SeekRange(prefix, start []byte, f func(k, v []byte) bool) {
+	s.seek(context.Background(), prefix, start, false, f)
 }
 
 // SeekAsync returns non-buffered channel with matching KeyValue pairs. Key and
@@ -100,11 +108,12 @@ func (s *MemCachedStore) Seek(key []byte, f func(k, v []byte)) {
 func (s *MemCachedStore) SeekAsync(ctx context.Context, key []byte, cutPrefix bool) chan KeyValue {
 	res := make(chan KeyValue)
 	go func() {
-		s.seek(ctx, key, cutPrefix, func(k, v []byte) {
+		s.seek(ctx, key, nil, cutPrefix, func(k, v []byte) bool {
 			res <- KeyValue{
 				Key:   k,
 				Value: v,
 			}
+			return true
 		})
 		close(res)
 	}()
@@ -112,13 +121,16 @@ func (s *MemCachedStore) SeekAsync(ctx context.Context, key []byte, cutPrefix bo
 	return res
 }
 
-func (s *MemCachedStore) seek(ctx context.Context, key []byte, cutPrefix bool, f func(k, v []byte)) {
+// seek iterates over items with the given prefix (key) starting from key+start
+// until f returns false or ctx is done.
+func (s *MemCachedStore) seek(ctx context.Context, key []byte, start []byte, cutPrefix bool, f func(k, v []byte) bool) {
 	// Create memory store `mem` and `del` snapshot not to hold the lock.
 	var memRes []KeyValueExists
 	sk := string(key)
+	from := string(seekStart(key, start))
 	s.mut.RLock()
 	for k, v := range s.MemoryStore.mem {
-		if strings.HasPrefix(k, sk) {
+		if strings.HasPrefix(k, sk) && k >= from {
 			memRes = append(memRes, KeyValueExists{
 				KeyValue: KeyValue{
 					Key:   []byte(k),
@@ -129,7 +141,7 @@ func (s *MemCachedStore) seek(ctx context.Context, key []byte, cutPrefix bool, f
 		}
 	}
 	for k := range s.MemoryStore.del {
-		if strings.HasPrefix(k, sk) {
+		if strings.HasPrefix(k, sk) && k >= from {
 			memRes = append(memRes, KeyValueExists{
 				KeyValue: KeyValue{
 					Key: []byte(k),
@@ -156,10 +168,7 @@ func (s *MemCachedStore) seek(ctx context.Context, key []byte, cutPrefix bool, f
 		iMem++
 	}
 	// Merge results of seek operations in ascending order.
-	ps.Seek(key, func(k, v []byte) {
-		if done {
-			return
-		}
+	SeekRange(ps, key, start, func(k, v []byte) bool {
 		kvPs := KeyValue{
 			Key:   slice.Copy(k),
 			Value: slice.Copy(v),
@@ -177,7 +186,10 @@ func (s *MemCachedStore) seek(ctx context.Context, key []byte, cutPrefix bool, f
 						if cutPrefix {
 							kvMem.Key = kvMem.Key[len(key):]
 						}
-						f(kvMem.Key, kvMem.Value)
+						if !f(kvMem.Key, kvMem.Value) {
+							done = true
+							break loop
+						}
 					}
 					if iMem < len(memRes) {
 						kvMem = memRes[iMem]
@@ -191,12 +203,13 @@ func (s *MemCachedStore) seek(ctx context.Context, key []byte, cutPrefix bool, f
 						if cutPrefix {
 							kvPs.Key = kvPs.Key[len(key):]
 						}
-						f(kvPs.Key, kvPs.Value)
+						done = !f(kvPs.Key, kvPs.Value)
 					}
 					break loop
 				}
 			}
 		}
+		return !done
 	})
 	if !done && haveMem {
 	loop:
@@ -210,7 +223,9 @@ func (s *MemCachedStore) seek(ctx context.Context, key []byte, cutPrefix bool, f
 					if cutPrefix {
 						kvMem.Key = kvMem.Key[len(key):]
 					}
-					f(kvMem.Key, kvMem.Value)
+					if !f(kvMem.Key, kvMem.Value) {
+						break loop
+					}
 				}
 			}
 		}
@@ -179,6 +179,33 @@ func TestCachedSeek(t *testing.T) {
 	}
 }
 
+func TestCachedSeekRange(t *testing.T) {
+	var (
+		ps = NewMemoryStore()
+		ts = NewMemCachedStore(ps)
+	)
+	for _, k := range []string{"fa", "fc", "fe", "fg"} {
+		require.NoError(t, ps.Put([]byte(k), []byte("persisted")))
+	}
+	require.NoError(t, ts.Put([]byte("fb"), []byte("cached")))
+	require.NoError(t, ts.Put([]byte("fe"), []byte("cached")))
+	require.NoError(t, ts.Put([]byte("ff"), []byte("cached")))
+	require.NoError(t, ts.Delete([]byte("fc")))
+
+	seek := func(start string, stop string) []string {
+		var res []string
+		ts.SeekRange([]byte{'f'}, []byte(start), func(k, v []byte) bool {
+			res = append(res, string(k)+"="+string(v))
+			return string(k) != stop
+		})
+		return res
+	}
+	require.Equal(t, []string{"fa=persisted", "fb=cached", "fe=cached", "ff=cached", "fg=persisted"}, seek("", ""))
+	require.Equal(t, []string{"fb=cached", "fe=cached"}, seek("b", "fe"))
+	require.Equal(t, []string{"fe=cached", "ff=cached"}, seek("d", "ff"))
+	require.Equal(t, []string{"fg=persisted"}, seek("g", ""))
+}
+
 func benchmarkCachedSeek(t *testing.B, ps Store, psElementsCount, tsElementsCount int) {
 	var (
 		searchPrefix      = []byte{1}
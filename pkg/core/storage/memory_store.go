@@ -127,12 +127,28 @@ func (s *MemoryStore) SeekAll(key []byte, f func(k, v []byte)) {
 	}
 }
 
+// SeekRange implements the RangeSeeker interface.
+func (s *MemoryStore) SeekRange(prefix, start []byte, f func(k, v []byte) bool) {
+	s.mut.RLock()
+	s.seekRange(prefix, start, f)
+	s.mut.RUnlock()
+}
+
 // seek is an internal unlocked implementation of Seek.
 func (s *MemoryStore) seek(key []byte, f func(k, v []byte)) {
-	sk := string(key)
+	s.seekRange(key, nil, func(k, v []byte) bool {
+		f(k, v)
+		return true
+	})
+}
+
+// seekRange is an internal unlocked implementation of SeekRange.
+func (s *MemoryStore) seekRange(prefix, start []byte, f func(k, v []byte) bool) {
+	sk := string(prefix)
+	from := string(seekStart(prefix, start))
 	var memList []KeyValue
 	for k, v := range s.mem {
-		if strings.HasPrefix(k, sk) {
+		if strings.HasPrefix(k, sk) && k >= from {
 			memList = append(memList, KeyValue{
 				Key:   []byte(k),
 				Value: v,
@@ -143,7 +159,9 @@ func (s *MemoryStore) seek(key []byte, f func(k, v []byte)) {
 		return bytes.Compare(memList[i].Key, memList[j].Key) < 0
 	})
 	for _, kv := range memList {
-		f(kv.Key, kv.Value)
+		if !f(kv.Key, kv.Value) {
+			break
+		}
 	}
 }
 
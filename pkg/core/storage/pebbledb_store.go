@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+	"github.com/nspcc-dev/neo-go/pkg/util/slice"
+)
+
+// PebbleDBOptions configuration for PebbleDB.
+type PebbleDBOptions struct {
+	DataDirectoryPath string `yaml:"DataDirectoryPath"`
+	// Compression is the block compression algorithm, it can be "snappy"
+	// (default), "zstd" or "none".
+	Compression string `yaml:"Compression"`
+	// CacheSize is the block cache size in megabytes, PebbleDB default is
+	// used if not set.
+	CacheSize int `yaml:"CacheSize"`
+	// MemTableSize is the size of a single memtable in megabytes, PebbleDB
+	// default is used if not set.
+	MemTableSize int `yaml:"MemTableSize"`
+	// MaxOpenFiles is a soft limit on the number of open files, PebbleDB
+	// default is used if not set.
+	MaxOpenFiles int `yaml:"MaxOpenFiles"`
+	// MaxConcurrentCompactions is the maximum number of concurrent
+	// compactions, PebbleDB default is used if not set.
+	MaxConcurrentCompactions int `yaml:"MaxConcurrentCompactions"`
+	// L0CompactionThreshold is the number of L0 read-amplification that
+	// triggers L0 compaction, PebbleDB default is used if not set.
+	L0CompactionThreshold int `yaml:"L0CompactionThreshold"`
+}
+
+// pebbleDBLevels is the number of LSM levels used by PebbleDB.
+const pebbleDBLevels = 7
+
+// PebbleDBStore is an LSM-based storage implementation backed by PebbleDB.
+type PebbleDBStore struct {
+	db *pebble.DB
+}
+
+// pebbleDBBatch wraps pebble.Batch to implement Batch interface.
+type pebbleDBBatch struct {
+	*pebble.Batch
+}
+
+// NewPebbleDBStore returns a new PebbleDBStore object that will initialize
+// the database found at the given path.
+func NewPebbleDBStore(cfg PebbleDBOptions) (*PebbleDBStore, error) {
+	var compression pebble.Compression
+	switch strings.ToLower(cfg.Compression) {
+	case "", "snappy":
+		compression = pebble.SnappyCompression
+	case "zstd":
+		compression = pebble.ZstdCompression
+	case "none":
+		compression = pebble.NoCompression
+	default:
+		return nil, fmt.Errorf("unknown PebbleDB compression: %s", cfg.Compression)
+	}
+	opts := &pebble.Options{
+		MaxOpenFiles:          cfg.MaxOpenFiles,
+		MemTableSize:          cfg.MemTableSize * 1024 * 1024,
+		L0CompactionThreshold: cfg.L0CompactionThreshold,
+		Levels:                make([]pebble.LevelOptions, pebbleDBLevels),
+	}
+	for i := range opts.Levels {
+		opts.Levels[i].Compression = compression
+		opts.Levels[i].FilterPolicy = bloom.FilterPolicy(10)
+	}
+	if cfg.MaxConcurrentCompactions > 0 {
+		opts.MaxConcurrentCompactions = cfg.MaxConcurrentCompactions
+	}
+	if cfg.CacheSize > 0 {
+		cache := pebble.NewCache(int64(cfg.CacheSize) * 1024 * 1024)
+		// DB holds its own reference to the cache.
+		defer cache.Unref()
+		opts.Cache = cache
+	}
+	db, err := pebble.Open(cfg.DataDirectoryPath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PebbleDBStore{db: db}, nil
+}
+
+// Put implements the Store interface.
+func (s *PebbleDBStore) Put(key, value []byte) error {
+	return s.db.Set(key, value, pebble.NoSync)
+}
+
+// Get implements the Store interface.
+func (s *PebbleDBStore) Get(key []byte) ([]byte, error) {
+	value, closer, err := s.db.Get(key)
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			err = ErrKeyNotFound
+		}
+		return nil, err
+	}
+	// Returned value is only valid until closer is closed.
+	res := slice.Copy(value)
+	return res, closer.Close()
+}
+
+// Delete implements the Store interface.
+func (s *PebbleDBStore) Delete(key []byte) error {
+	return s.db.Delete(key, pebble.NoSync)
+}
+
+// PutBatch implements the Store interface.
+func (s *PebbleDBStore) PutBatch(batch Batch) error {
+	b := batch.(*pebbleDBBatch)
+	return s.db.Apply(b.Batch, pebble.NoSync)
+}
+
+// PutChangeSet implements the Store interface.
+func (s *PebbleDBStore) PutChangeSet(puts map[string][]byte, dels map[string]bool) error {
+	b := s.db.NewBatch()
+	defer b.Close()
+	for k := range puts {
+		if err := b.Set([]byte(k), puts[k], nil); err != nil {
+			return err
+		}
+	}
+	for k := range dels {
+		if err := b.Delete([]byte(k), nil); err != nil {
+			return err
+		}
+	}
+	return b.Commit(pebble.NoSync)
+}
+
+// Seek implements the Store interface.
+func (s *PebbleDBStore) Seek(key []byte, f func(k, v []byte)) {
+	iter := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: key,
+		UpperBound: prefixUpperBound(key),
+	})
+	for iter.First(); iter.Valid(); iter.Next() {
+		f(iter.Key(), iter.Value())
+	}
+	_ = iter.Close()
+}
+
+// SeekRange implements the RangeSeeker interface.
+func (s *PebbleDBStore) SeekRange(prefix, start []byte, f func(k, v []byte) bool) {
+	iter := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: seekStart(prefix, start),
+		UpperBound: prefixUpperBound(prefix),
+	})
+	for iter.First(); iter.Valid(); iter.Next() {
+		if !f(iter.Key(), iter.Value()) {
+			break
+		}
+	}
+	_ = iter.Close()
+}
+
+// prefixUpperBound returns the smallest key that is greater than all keys
+// with the given prefix or nil if there is no such key.
+func prefixUpperBound(prefix []byte) []byte {
+	var limit []byte
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xff {
+			limit = make([]byte, i+1)
+			copy(limit, prefix)
+			limit[i]++
+			break
+		}
+	}
+	return limit
+}
+
+// Batch implements the Batch interface and returns a PebbleDB compatible
+// Batch.
+func (s *PebbleDBStore) Batch() Batch {
+	return &pebbleDBBatch{s.db.NewBatch()}
+}
+
+// Close implements the Store interface.
+func (s *PebbleDBStore) Close() error {
+	return s.db.Close()
+}
+
+// Put implements the Batch interface.
+func (b *pebbleDBBatch) Put(k, v []byte) {
+	// Error can only be returned for closed or read-only batch.
+	_ = b.Batch.Set(k, v, nil)
+}
+
+// Delete implements the Batch interface.
+func (b *pebbleDBBatch) Delete(k []byte) {
+	_ = b.Batch.Delete(k, nil)
+}
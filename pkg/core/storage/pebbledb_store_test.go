@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newPebbleDBForTesting(t testing.TB) Store {
+	dbConfig := DBConfiguration{
+		Type: "pebbledb",
+		PebbleDBOptions: PebbleDBOptions{
+			DataDirectoryPath: t.TempDir(),
+		},
+	}
+	newPebbleStore, err := NewPebbleDBStore(dbConfig.PebbleDBOptions)
+	require.Nil(t, err, "NewPebbleDBStore error")
+	return newPebbleStore
+}
+
+func TestNewPebbleDBStore(t *testing.T) {
+	t.Run("bad compression", func(t *testing.T) {
+		_, err := NewPebbleDBStore(PebbleDBOptions{
+			DataDirectoryPath: t.TempDir(),
+			Compression:       "lzma",
+		})
+		require.Error(t, err)
+	})
+	for _, c := range []string{"snappy", "zstd", "none"} {
+		t.Run(c, func(t *testing.T) {
+			s, err := NewPebbleDBStore(PebbleDBOptions{
+				DataDirectoryPath:        t.TempDir(),
+				Compression:              c,
+				CacheSize:                1,
+				MemTableSize:             1,
+				MaxOpenFiles:             100,
+				MaxConcurrentCompactions: 2,
+				L0CompactionThreshold:    2,
+			})
+			require.NoError(t, err)
+			require.NoError(t, s.Put([]byte{1}, []byte{2}))
+			v, err := s.Get([]byte{1})
+			require.NoError(t, err)
+			require.Equal(t, []byte{2}, v)
+			require.NoError(t, s.Close())
+		})
+	}
+}
+
+func TestPrefixUpperBound(t *testing.T) {
+	require.Nil(t, prefixUpperBound(nil))
+	require.Nil(t, prefixUpperBound([]byte{0xff, 0xff}))
+	require.Equal(t, []byte{2}, prefixUpperBound([]byte{1}))
+	require.Equal(t, []byte{1, 3}, prefixUpperBound([]byte{1, 2}))
+	require.Equal(t, []byte{2}, prefixUpperBound([]byte{1, 0xff}))
+}
@@ -1,9 +1,12 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/util/slice"
 )
 
 // KeyPrefix constants.
@@ -13,8 +16,12 @@ const (
 	DataMPT         KeyPrefix = 0x03
 	STAccount       KeyPrefix = 0x40
 	STNotification  KeyPrefix = 0x4d
-	STContractID    KeyPrefix = 0x51
-	STStorage       KeyPrefix = 0x70
+	// STNotificationIndex is used to store notification index entries
+	// (contract, block index and execution position to container mapping)
+	// if notification index is enabled.
+	STNotificationIndex KeyPrefix = 0x4e
+	STContractID        KeyPrefix = 0x51
+	STStorage           KeyPrefix = 0x70
 	// STTempStorage is used to store contract storage items during state sync process
 	// in order not to mess up the previous state which has its own items stored by
 	// STStorage prefix. Once state exchange process is completed, all items with
@@ -62,6 +69,16 @@ type (
 		Close() error
 	}
 
+	// RangeSeeker is implemented by stores able to start iteration from the
+	// specified key and to stop it before all matching items are traversed.
+	RangeSeeker interface {
+		// SeekRange iterates over items with the given prefix and keys not
+		// less than prefix+start in ascending order until f returns false.
+		// Key and value slices are only valid until the next call to f and
+		// should not be modified.
+		SeekRange(prefix, start []byte, f func(k, v []byte) bool)
+	}
+
 	// Batch represents an abstraction on top of batch operations.
 	// Each Store implementation is responsible of casting a Batch
 	// to its appropriate type. Batches can only be used in a single
@@ -109,8 +126,35 @@ func NewStore(cfg DBConfiguration) (Store, error) {
 		store = NewMemoryStore()
 	case "boltdb":
 		store, err = NewBoltDBStore(cfg.BoltDBOptions)
+	case "pebbledb":
+		store, err = NewPebbleDBStore(cfg.PebbleDBOptions)
 	default:
 		return nil, fmt.Errorf("unknown storage: %s", cfg.Type)
 	}
 	return store, err
 }
+
+// SeekRange iterates over items of s with the given prefix and keys not less
+// than prefix+start in ascending order until f returns false. If s doesn't
+// implement RangeSeeker all items with the given prefix are traversed.
+func SeekRange(s Store, prefix, start []byte, f func(k, v []byte) bool) {
+	if rs, ok := s.(RangeSeeker); ok {
+		rs.SeekRange(prefix, start, f)
+		return
+	}
+	var (
+		done bool
+		from = seekStart(prefix, start)
+	)
+	// Some stores can return a key following the prefix range from Seek.
+	s.Seek(prefix, func(k, v []byte) {
+		if !done && bytes.HasPrefix(k, prefix) && bytes.Compare(k, from) >= 0 {
+			done = !f(k, v)
+		}
+	})
+}
+
+// seekStart returns the first key for SeekRange iteration.
+func seekStart(prefix, start []byte) []byte {
+	return append(slice.Copy(prefix), start...)
+}
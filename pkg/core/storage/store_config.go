@@ -1,10 +1,11 @@
 package storage
 
 type (
-	// DBConfiguration describes configuration for DB. Supported: 'levelDB', 'boltDB'.
+	// DBConfiguration describes configuration for DB. Supported: 'levelDB', 'boltDB', 'pebbleDB'.
 	DBConfiguration struct {
-		Type           string         `yaml:"Type"`
-		LevelDBOptions LevelDBOptions `yaml:"LevelDBOptions"`
-		BoltDBOptions  BoltDBOptions  `yaml:"BoltDBOptions"`
+		Type            string          `yaml:"Type"`
+		LevelDBOptions  LevelDBOptions  `yaml:"LevelDBOptions"`
+		BoltDBOptions   BoltDBOptions   `yaml:"BoltDBOptions"`
+		PebbleDBOptions PebbleDBOptions `yaml:"PebbleDBOptions"`
 	}
 )
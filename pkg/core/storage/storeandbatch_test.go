@@ -120,6 +120,31 @@ func testStoreSeek(t *testing.T, s Store) {
 	require.NoError(t, s.Close())
 }
 
+func testStoreSeekRange(t *testing.T, s Store) {
+	for _, k := range []string{"e", "fa", "fb", "fc", "fd", "g"} {
+		require.NoError(t, s.Put([]byte(k), []byte(k)))
+	}
+	seek := func(s Store, start string, stop string) []string {
+		var res []string
+		SeekRange(s, []byte{'f'}, []byte(start), func(k, v []byte) bool {
+			require.Equal(t, k, v)
+			res = append(res, string(k))
+			return string(k) != stop
+		})
+		return res
+	}
+	// Store without RangeSeeker implementation.
+	type plainStore struct{ Store }
+
+	for _, st := range []Store{s, plainStore{s}} {
+		require.Equal(t, []string{"fa", "fb", "fc", "fd"}, seek(st, "", ""))
+		require.Equal(t, []string{"fb", "fc"}, seek(st, "b", "fc"))
+		require.Equal(t, []string{"fc", "fd"}, seek(st, "bb", ""))
+		require.Equal(t, 0, len(seek(st, "e", "")))
+	}
+	require.NoError(t, s.Close())
+}
+
 func testStoreDeleteNonExistent(t *testing.T, s Store) {
 	key := []byte("sparse")
 
@@ -222,10 +247,11 @@ func TestAllDBs(t *testing.T) {
 		{"LevelDB", newLevelDBForTesting},
 		{"MemCached", newMemCachedStoreForTesting},
 		{"Memory", newMemoryStoreForTesting},
+		{"PebbleDB", newPebbleDBForTesting},
 	}
 	var tests = []dbTestFunction{testStoreClose, testStorePutAndGet,
 		testStoreGetNonExistent, testStorePutBatch, testStoreSeek,
-		testStoreDeleteNonExistent, testStorePutAndDelete,
+		testStoreSeekRange, testStoreDeleteNonExistent, testStorePutAndDelete,
 		testStorePutBatchWithDelete}
 	for _, db := range DBs {
 		for _, test := range tests {
@@ -36,6 +36,40 @@ func (t *MerkleTree) Root() util.Uint256 {
 	return t.root.hash
 }
 
+// PartialHashes returns hashes needed to prove inclusion of the leaves marked
+// by flags (flags[i] corresponds to the i-th leaf) into the tree. The tree is
+// traversed depth-first and every subtree without marked leaves is represented
+// by its root hash, so that for no marked leaves only the root hash is
+// returned and for all leaves marked all of them are returned. It's the format
+// used by merkle block payload.
+func (t *MerkleTree) PartialHashes(flags []bool) []util.Uint256 {
+	var depth int
+	for n := t.root; n != nil; n = n.leftChild {
+		depth++
+	}
+	var hashes []util.Uint256
+	t.root.partialHashes(0, depth, flags, &hashes)
+	return hashes
+}
+
+// partialHashes appends partial tree hashes to res for the node with the
+// given index at the given level (counting from leaves, starting at 1).
+func (n *MerkleTreeNode) partialHashes(index int, depth int, flags []bool, res *[]util.Uint256) {
+	var marked bool
+	for i := index << (depth - 1); i < (index+1)<<(depth-1) && i < len(flags); i++ {
+		if flags[i] {
+			marked = true
+			break
+		}
+	}
+	if n.IsLeaf() || !marked {
+		*res = append(*res, n.hash)
+		return
+	}
+	n.leftChild.partialHashes(index*2, depth-1, flags, res)
+	n.rightChild.partialHashes(index*2+1, depth-1, flags, res)
+}
+
 func buildMerkleTree(leaves []*MerkleTreeNode) *MerkleTreeNode {
 	if len(leaves) == 0 {
 		panic("length of leaves cannot be zero")
@@ -101,3 +101,21 @@ func TestBuildMerkleTreeWithoutNodes(t *testing.T) {
 	leaves = make([]*MerkleTreeNode, 0)
 	require.Panics(t, func() { buildMerkleTree(leaves) })
 }
+
+func TestMerkleTreePartialHashes(t *testing.T) {
+	hashes := []util.Uint256{{1}, {2}, {3}}
+	merkle, err := NewMerkleTree(hashes)
+	require.NoError(t, err)
+	left := merkle.root.leftChild.hash
+	right := merkle.root.rightChild.hash
+
+	require.Equal(t, []util.Uint256{merkle.Root()}, merkle.PartialHashes(nil))
+	require.Equal(t, []util.Uint256{merkle.Root()}, merkle.PartialHashes([]bool{false, false, false}))
+	require.Equal(t, []util.Uint256{hashes[0], hashes[1], right}, merkle.PartialHashes([]bool{true, false, false}))
+	require.Equal(t, []util.Uint256{left, hashes[2], hashes[2]}, merkle.PartialHashes([]bool{false, false, true}))
+	require.Equal(t, []util.Uint256{hashes[0], hashes[1], hashes[2], hashes[2]}, merkle.PartialHashes([]bool{true, true, true}))
+
+	single, err := NewMerkleTree(hashes[:1])
+	require.NoError(t, err)
+	require.Equal(t, []util.Uint256{hashes[0]}, single.PartialHashes([]bool{true}))
+}
@@ -0,0 +1,248 @@
+/*
+Package lightclient implements a light client that doesn't trust the node it
+gets data from. It syncs block headers (via RPC or from any other source like
+P2P network), checks their witnesses against the NextConsensus address of the
+previous (already verified) header and verifies contract storage proofs
+against state roots signed by the state validators.
+*/
+package lightclient
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+type (
+	// Source is a source of untrusted chain data, rpc/client.Client
+	// implements it.
+	Source interface {
+		GetBlockHeaderCount() (uint32, error)
+		GetBlockHash(index uint32) (util.Uint256, error)
+		GetBlockHeader(hash util.Uint256) (*block.Header, error)
+		GetStateRootByHeight(height uint32) (*state.MPTRoot, error)
+		GetProof(stateroot util.Uint256, historicalContractHash util.Uint160, historicalKey []byte) (*result.ProofWithKey, error)
+	}
+
+	// Config contains light client settings.
+	Config struct {
+		// Network is the magic of the network headers and state roots
+		// are signed for.
+		Network netmode.Magic
+		// TrustedHeader is the header all other headers are verified from,
+		// it can be the genesis block header or any other checkpoint
+		// obtained from a trusted party.
+		TrustedHeader *block.Header
+		// StateValidators are the keys designated for the StateValidator
+		// role, state roots must be signed by the majority of them. They're
+		// not needed if StateRootInHeader is set. These keys are fixed for
+		// the client lifetime, re-designation of the StateValidator role is
+		// not tracked, so state roots signed by the new keys are rejected
+		// with ErrUnknownStateValidators and the client should be recreated
+		// with the new keys.
+		StateValidators keys.PublicKeys
+		// StateRootInHeader should be set for networks that have state root
+		// included into block headers, then they're taken from verified
+		// headers instead of being requested from the Source.
+		StateRootInHeader bool
+		// MaxHeaders is the number of the latest verified headers kept,
+		// DefaultMaxHeaders is used if it's not positive. Older headers are
+		// dropped unless they're checkpoints.
+		MaxHeaders int
+		// CheckpointInterval is the distance between checkpoint headers
+		// kept after they're dropped from the latest headers (along with
+		// the trusted header), DefaultCheckpointInterval is used if it's
+		// zero. Checkpoints can be used as trusted headers later.
+		CheckpointInterval uint32
+	}
+
+	// Client is a light client. It's safe for concurrent use.
+	Client struct {
+		src Source
+		cfg Config
+
+		// stateValidatorsHash is the script hash of state root witness.
+		stateValidatorsHash util.Uint160
+
+		lock sync.RWMutex
+		// headers contains the latest verified headers in ascending order.
+		headers []*block.Header
+		// checkpoints contains the trusted header and checkpoint headers
+		// dropped from headers in ascending order.
+		checkpoints []*block.Header
+	}
+)
+
+const (
+	// DefaultMaxHeaders is the default number of the latest verified
+	// headers kept by Client.
+	DefaultMaxHeaders = 2000
+	// DefaultCheckpointInterval is the default distance between checkpoint
+	// headers kept by Client.
+	DefaultCheckpointInterval = 10000
+)
+
+var (
+	// ErrUnknownHeader is returned when the header requested is not
+	// (yet) synchronized and verified.
+	ErrUnknownHeader = errors.New("unknown header")
+	// ErrInvalidHeader is returned when the header doesn't pass
+	// verification.
+	ErrInvalidHeader = errors.New("invalid header")
+	// ErrInvalidStateRoot is returned when the state root doesn't pass
+	// verification.
+	ErrInvalidStateRoot = errors.New("invalid state root")
+	// ErrUnknownStateValidators is returned when the state root is signed
+	// by keys other than configured StateValidators, it usually means that
+	// StateValidator role was re-designated.
+	ErrUnknownStateValidators = errors.New("state root is signed by unknown state validators")
+	// ErrInvalidProof is returned when the storage proof doesn't pass
+	// verification.
+	ErrInvalidProof = errors.New("invalid proof")
+)
+
+// New creates a light client getting data from src.
+func New(src Source, cfg Config) (*Client, error) {
+	if cfg.TrustedHeader == nil {
+		return nil, errors.New("no trusted header")
+	}
+	if cfg.MaxHeaders <= 0 {
+		cfg.MaxHeaders = DefaultMaxHeaders
+	}
+	if cfg.CheckpointInterval == 0 {
+		cfg.CheckpointInterval = DefaultCheckpointInterval
+	}
+	c := &Client{
+		src:     src,
+		cfg:     cfg,
+		headers: []*block.Header{cfg.TrustedHeader},
+	}
+	if !cfg.StateRootInHeader {
+		if len(cfg.StateValidators) == 0 {
+			return nil, errors.New("no state validators")
+		}
+		h, err := scriptHashForKeys(cfg.StateValidators)
+		if err != nil {
+			return nil, fmt.Errorf("invalid state validators: %w", err)
+		}
+		c.stateValidatorsHash = h
+	}
+	return c, nil
+}
+
+// Height returns the index of the latest verified header.
+func (c *Client) Height() uint32 {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.headers[len(c.headers)-1].Index
+}
+
+// GetHeader returns verified header with the given index. Only the latest
+// MaxHeaders headers and checkpoints are available.
+func (c *Client) GetHeader(index uint32) (*block.Header, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.getHeader(index)
+}
+
+func (c *Client) getHeader(index uint32) (*block.Header, error) {
+	start := c.headers[0].Index
+	if index >= start && index-start < uint32(len(c.headers)) {
+		return c.headers[index-start], nil
+	}
+	i := sort.Search(len(c.checkpoints), func(i int) bool {
+		return c.checkpoints[i].Index >= index
+	})
+	if i < len(c.checkpoints) && c.checkpoints[i].Index == index {
+		return c.checkpoints[i], nil
+	}
+	return nil, fmt.Errorf("%w: %d", ErrUnknownHeader, index)
+}
+
+// Checkpoints returns the trusted header and checkpoint headers that are no
+// longer among the latest MaxHeaders headers in ascending order.
+func (c *Client) Checkpoints() []*block.Header {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return append([]*block.Header{}, c.checkpoints...)
+}
+
+// AddHeaders verifies and adds headers following the latest verified one. It
+// can be used to feed the client with headers received from the P2P network.
+// Headers up to the first one failing verification are added.
+func (c *Client) AddHeaders(hs ...*block.Header) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, h := range hs {
+		prev := c.headers[len(c.headers)-1]
+		if err := verifyHeader(uint32(c.cfg.Network), prev, h); err != nil {
+			return fmt.Errorf("%w %d: %v", ErrInvalidHeader, h.Index, err)
+		}
+		c.headers = append(c.headers, h)
+		if len(c.headers) > c.cfg.MaxHeaders {
+			c.dropHeader()
+		}
+	}
+	return nil
+}
+
+// dropHeader removes the oldest header from the latest headers keeping it
+// if it's a checkpoint. It must be called with the lock held.
+func (c *Client) dropHeader() {
+	h := c.headers[0]
+	if h == c.cfg.TrustedHeader || h.Index%c.cfg.CheckpointInterval == 0 {
+		c.checkpoints = append(c.checkpoints, h)
+	}
+	copy(c.headers, c.headers[1:])
+	c.headers[len(c.headers)-1] = nil
+	c.headers = c.headers[:len(c.headers)-1]
+}
+
+// Sync requests headers from the Source and verifies them until the Source
+// height is reached.
+func (c *Client) Sync() error {
+	count, err := c.src.GetBlockHeaderCount()
+	if err != nil {
+		return fmt.Errorf("failed to get header count: %w", err)
+	}
+	for i := c.Height() + 1; i < count; i++ {
+		h, err := c.src.GetBlockHash(i)
+		if err != nil {
+			return fmt.Errorf("failed to get header hash %d: %w", i, err)
+		}
+		hdr, err := c.src.GetBlockHeader(h)
+		if err != nil {
+			return fmt.Errorf("failed to get header %d: %w", i, err)
+		}
+		if err := c.AddHeaders(hdr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyHeader checks that h is the next header after the (verified) prev.
+func verifyHeader(net uint32, prev, h *block.Header) error {
+	if h.Index != prev.Index+1 {
+		return fmt.Errorf("expected index %d", prev.Index+1)
+	}
+	if h.PrevHash != prev.Hash() {
+		return errors.New("previous hash mismatch")
+	}
+	if h.Timestamp <= prev.Timestamp {
+		return errors.New("timestamp is not increasing")
+	}
+	if hash.Hash160(h.Script.VerificationScript) != prev.NextConsensus {
+		return errors.New("witness doesn't match previous NextConsensus")
+	}
+	return verifyWitness(net, h, h.Script.InvocationScript, h.Script.VerificationScript)
+}
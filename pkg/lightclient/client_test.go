@@ -0,0 +1,283 @@
+package lightclient
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+)
+
+// chainSource serves data from the local chain, state roots are signed by
+// the state validator key.
+type chainSource struct {
+	bc  *core.Blockchain
+	key *keys.PrivateKey
+
+	// corrupt allows to modify data before returning it.
+	corruptHeader func(h *block.Header)
+	corruptRoot   func(r *state.MPTRoot)
+	corruptProof  func(p *result.ProofWithKey)
+}
+
+func (s *chainSource) GetBlockHeaderCount() (uint32, error) {
+	return s.bc.HeaderHeight() + 1, nil
+}
+
+func (s *chainSource) GetBlockHash(index uint32) (util.Uint256, error) {
+	return s.bc.GetHeaderHash(int(index)), nil
+}
+
+func (s *chainSource) GetBlockHeader(h util.Uint256) (*block.Header, error) {
+	hdr, err := s.bc.GetHeader(h)
+	if err != nil {
+		return nil, err
+	}
+	if s.corruptHeader != nil {
+		cp := *hdr
+		s.corruptHeader(&cp)
+		hdr = &cp
+	}
+	return hdr, nil
+}
+
+func (s *chainSource) GetStateRootByHeight(height uint32) (*state.MPTRoot, error) {
+	r, err := s.bc.GetStateModule().GetStateRoot(height)
+	if err != nil {
+		return nil, err
+	}
+	script, err := smartcontract.CreateDefaultMultiSigRedeemScript(keys.PublicKeys{s.key.PublicKey()})
+	if err != nil {
+		return nil, err
+	}
+	signed := &state.MPTRoot{Version: r.Version, Index: r.Index, Root: r.Root}
+	sig := s.key.SignHashable(uint32(s.bc.GetConfig().Magic), signed)
+	signed.Witness = []transaction.Witness{{
+		InvocationScript:   append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, sig...),
+		VerificationScript: script,
+	}}
+	if s.corruptRoot != nil {
+		s.corruptRoot(signed)
+	}
+	return signed, nil
+}
+
+func (s *chainSource) GetProof(root util.Uint256, contract util.Uint160, key []byte) (*result.ProofWithKey, error) {
+	cs := s.bc.GetContractState(contract)
+	if cs == nil {
+		return nil, errors.New("unknown contract")
+	}
+	skey := make([]byte, 4+len(key))
+	binary.LittleEndian.PutUint32(skey, uint32(cs.ID))
+	copy(skey[4:], key)
+	proof, err := s.bc.GetStateModule().GetStateProof(root, skey)
+	if err != nil {
+		return nil, err
+	}
+	p := &result.ProofWithKey{Key: skey, Proof: proof}
+	if s.corruptProof != nil {
+		s.corruptProof(p)
+	}
+	return p, nil
+}
+
+func newTestClient(t *testing.T) (*chainSource, *Client) {
+	bc, validator, committee := chain.NewMulti(t)
+	e := neotest.NewExecutor(t, bc, validator, committee)
+	for i := 0; i < 5; i++ {
+		e.AddNewBlock(t)
+	}
+
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	src := &chainSource{bc: bc, key: key}
+
+	genesis, err := bc.GetHeader(bc.GetHeaderHash(0))
+	require.NoError(t, err)
+	c, err := New(src, Config{
+		Network:         bc.GetConfig().Magic,
+		TrustedHeader:   genesis,
+		StateValidators: keys.PublicKeys{key.PublicKey()},
+	})
+	require.NoError(t, err)
+	return src, c
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(nil, Config{})
+	require.Error(t, err)
+
+	_, err = New(nil, Config{TrustedHeader: new(block.Header)})
+	require.Error(t, err)
+
+	_, err = New(nil, Config{TrustedHeader: new(block.Header), StateRootInHeader: true})
+	require.NoError(t, err)
+}
+
+func TestClientSync(t *testing.T) {
+	t.Run("good", func(t *testing.T) {
+		src, c := newTestClient(t)
+		require.NoError(t, c.Sync())
+		require.Equal(t, src.bc.BlockHeight(), c.Height())
+
+		h, err := c.GetHeader(3)
+		require.NoError(t, err)
+		require.Equal(t, src.bc.GetHeaderHash(3), h.Hash())
+
+		_, err = c.GetHeader(c.Height() + 1)
+		require.True(t, errors.Is(err, ErrUnknownHeader))
+	})
+	t.Run("bad witness", func(t *testing.T) {
+		src, c := newTestClient(t)
+		src.corruptHeader = func(h *block.Header) {
+			if h.Index == 3 {
+				h.Script.InvocationScript = append([]byte{}, h.Script.InvocationScript...)
+				h.Script.InvocationScript[10] ^= 0xFF
+			}
+		}
+		require.True(t, errors.Is(c.Sync(), ErrInvalidHeader))
+		require.Equal(t, uint32(2), c.Height())
+	})
+	t.Run("bad NextConsensus", func(t *testing.T) {
+		src, c := newTestClient(t)
+		src.corruptHeader = func(h *block.Header) {
+			if h.Index == 2 {
+				h.Script.VerificationScript = src.key.PublicKey().GetVerificationScript()
+			}
+		}
+		require.True(t, errors.Is(c.Sync(), ErrInvalidHeader))
+		require.Equal(t, uint32(1), c.Height())
+	})
+	t.Run("AddHeaders", func(t *testing.T) {
+		src, c := newTestClient(t)
+		h1, err := src.bc.GetHeader(src.bc.GetHeaderHash(1))
+		require.NoError(t, err)
+		h3, err := src.bc.GetHeader(src.bc.GetHeaderHash(3))
+		require.NoError(t, err)
+
+		require.NoError(t, c.AddHeaders(h1))
+		require.True(t, errors.Is(c.AddHeaders(h3), ErrInvalidHeader))
+		require.Equal(t, uint32(1), c.Height())
+	})
+}
+
+func TestClientHeadersWindow(t *testing.T) {
+	src, _ := newTestClient(t)
+	genesis, err := src.bc.GetHeader(src.bc.GetHeaderHash(0))
+	require.NoError(t, err)
+	c, err := New(src, Config{
+		Network:            src.bc.GetConfig().Magic,
+		TrustedHeader:      genesis,
+		StateRootInHeader:  true,
+		MaxHeaders:         2,
+		CheckpointInterval: 2,
+	})
+	require.NoError(t, err)
+	require.NoError(t, c.Sync())
+	height := c.Height()
+	require.Equal(t, src.bc.BlockHeight(), height)
+
+	var expected []uint32
+	for i := uint32(0); i <= height; i++ {
+		h, err := c.GetHeader(i)
+		if i+2 > height || i%2 == 0 {
+			require.NoError(t, err, i)
+			require.Equal(t, src.bc.GetHeaderHash(int(i)), h.Hash())
+		} else {
+			require.True(t, errors.Is(err, ErrUnknownHeader), i)
+		}
+		if i+2 <= height && i%2 == 0 {
+			expected = append(expected, i)
+		}
+	}
+	var actual []uint32
+	for _, h := range c.Checkpoints() {
+		actual = append(actual, h.Index)
+	}
+	require.Equal(t, expected, actual)
+}
+
+func TestClientGetStorage(t *testing.T) {
+	src, c := newTestClient(t)
+	require.NoError(t, c.Sync())
+
+	policy, err := src.bc.GetNativeContractScriptHash(nativenames.Policy)
+	require.NoError(t, err)
+	policyID := src.bc.GetContractState(policy).ID
+	key := []byte{10} // Fee per byte.
+	height := c.Height()
+
+	t.Run("good", func(t *testing.T) {
+		val, err := c.GetStorage(height, policy, key)
+		require.NoError(t, err)
+		require.Equal(t, []byte(src.bc.GetStorageItem(policyID, key)), val)
+	})
+	t.Run("missing item", func(t *testing.T) {
+		_, err := c.GetStorage(height, policy, []byte{0xFF})
+		require.Error(t, err)
+	})
+	t.Run("bad state root signature", func(t *testing.T) {
+		src.corruptRoot = func(r *state.MPTRoot) { r.Root[0] ^= 0xFF }
+		defer func() { src.corruptRoot = nil }()
+		_, err := c.GetStorage(height, policy, key)
+		require.True(t, errors.Is(err, ErrInvalidStateRoot))
+	})
+	t.Run("bad state root signer", func(t *testing.T) {
+		other, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		src.key, other = other, src.key
+		defer func() { src.key = other }()
+		_, err = c.GetStorage(height, policy, key)
+		require.True(t, errors.Is(err, ErrUnknownStateValidators))
+	})
+	t.Run("bad proof", func(t *testing.T) {
+		src.corruptProof = func(p *result.ProofWithKey) {
+			last := p.Proof[len(p.Proof)-1]
+			p.Proof[len(p.Proof)-1] = append(append([]byte{}, last...), 1)
+		}
+		defer func() { src.corruptProof = nil }()
+		_, err := c.GetStorage(height, policy, key)
+		require.True(t, errors.Is(err, ErrInvalidProof))
+	})
+	t.Run("bad key", func(t *testing.T) {
+		src.corruptProof = func(p *result.ProofWithKey) { p.Key = append(p.Key, 1) }
+		defer func() { src.corruptProof = nil }()
+		_, err := c.GetStorage(height, policy, key)
+		require.True(t, errors.Is(err, ErrInvalidProof))
+	})
+}
+
+func TestClientStateRootInHeader(t *testing.T) {
+	src, _ := newTestClient(t)
+	genesis, err := src.bc.GetHeader(src.bc.GetHeaderHash(0))
+	require.NoError(t, err)
+	c, err := New(src, Config{
+		Network:           src.bc.GetConfig().Magic,
+		TrustedHeader:     genesis,
+		StateRootInHeader: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, c.Sync())
+
+	h, err := c.GetHeader(3)
+	require.NoError(t, err)
+	r, err := c.GetStateRoot(2)
+	require.NoError(t, err)
+	require.Equal(t, h.PrevStateRoot, r.Root)
+
+	_, err = c.GetStateRoot(c.Height())
+	require.True(t, errors.Is(err, ErrUnknownHeader))
+	require.Error(t, c.VerifyStateRoot(r))
+}
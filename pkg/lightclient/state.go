@@ -0,0 +1,115 @@
+package lightclient
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// managementHash is the hash of the Management native contract.
+var managementHash = state.CreateContractHash(util.Uint160{}, 0, nativenames.Management)
+
+// VerifyStateRoot checks that r is signed by the state validators. Roots signed
+// by other keys (e.g. after StateValidator role re-designation) are rejected
+// with ErrUnknownStateValidators.
+func (c *Client) VerifyStateRoot(r *state.MPTRoot) error {
+	if c.cfg.StateRootInHeader {
+		return errors.New("state roots are included into headers")
+	}
+	if len(r.Witness) != 1 {
+		return fmt.Errorf("%w: expected 1 witness, got %d", ErrInvalidStateRoot, len(r.Witness))
+	}
+	w := &r.Witness[0]
+	if hash.Hash160(w.VerificationScript) != c.stateValidatorsHash {
+		return fmt.Errorf("%w (height %d)", ErrUnknownStateValidators, r.Index)
+	}
+	if err := verifyWitness(uint32(c.cfg.Network), r, w.InvocationScript, w.VerificationScript); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidStateRoot, err)
+	}
+	return nil
+}
+
+// GetStateRoot returns verified state root for the given height. If state
+// roots are included into headers the header with the next index must be
+// synchronized, otherwise the state root is requested from the Source.
+func (c *Client) GetStateRoot(height uint32) (*state.MPTRoot, error) {
+	if c.cfg.StateRootInHeader {
+		h, err := c.GetHeader(height + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &state.MPTRoot{Index: height, Root: h.PrevStateRoot}, nil
+	}
+	r, err := c.src.GetStateRootByHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state root %d: %w", height, err)
+	}
+	if r.Index != height {
+		return nil, fmt.Errorf("%w: expected height %d, got %d", ErrInvalidStateRoot, height, r.Index)
+	}
+	if err := c.VerifyStateRoot(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetStorage returns the value of the contract storage item with the given key
+// at the given height. The value is verified against the state root. Proofs of
+// absence are not supported, so an error is returned for missing items.
+func (c *Client) GetStorage(height uint32, contract util.Uint160, key []byte) ([]byte, error) {
+	r, err := c.GetStateRoot(height)
+	if err != nil {
+		return nil, err
+	}
+	id, err := c.getContractID(r.Root, contract)
+	if err != nil {
+		return nil, err
+	}
+	return c.getProvenStorage(r.Root, contract, id, key)
+}
+
+// getContractID returns the ID of the contract proven by the Management
+// contract storage.
+func (c *Client) getContractID(root util.Uint256, contract util.Uint160) (int32, error) {
+	val, err := c.getProvenStorage(root, managementHash, native.ManagementContractID, native.MakeContractKey(contract))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get contract state: %w", err)
+	}
+	cs := new(state.Contract)
+	if err := stackitem.DeserializeConvertible(val, cs); err != nil {
+		return 0, fmt.Errorf("invalid contract state: %w", err)
+	}
+	if cs.Hash != contract {
+		return 0, fmt.Errorf("%w: contract hash mismatch", ErrInvalidProof)
+	}
+	return cs.ID, nil
+}
+
+// getProvenStorage requests the proof for the storage item of the contract
+// with the given hash and ID and verifies it against the root.
+func (c *Client) getProvenStorage(root util.Uint256, contract util.Uint160, id int32, key []byte) ([]byte, error) {
+	p, err := c.src.GetProof(root, contract, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proof: %w", err)
+	}
+	skey := make([]byte, 4+len(key))
+	binary.LittleEndian.PutUint32(skey, uint32(id))
+	copy(skey[4:], key)
+	if !bytes.Equal(p.Key, skey) {
+		return nil, fmt.Errorf("%w: key mismatch", ErrInvalidProof)
+	}
+	val, ok := mpt.VerifyProof(root, p.Key, p.Proof)
+	if !ok {
+		return nil, ErrInvalidProof
+	}
+	return val, nil
+}
@@ -0,0 +1,79 @@
+package lightclient
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// scriptHashForKeys returns the hash of the default multisignature script
+// for the given keys.
+func scriptHashForKeys(pubs keys.PublicKeys) (util.Uint160, error) {
+	script, err := smartcontract.CreateDefaultMultiSigRedeemScript(pubs)
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	return hash.Hash160(script), nil
+}
+
+// parseSignatures returns signatures pushed by the standard invocation script.
+func parseSignatures(script []byte) ([][]byte, error) {
+	const chunkLen = 2 + keys.SignatureLen
+
+	if len(script) == 0 || len(script)%chunkLen != 0 {
+		return nil, errors.New("invalid invocation script length")
+	}
+	sigs := make([][]byte, 0, len(script)/chunkLen)
+	for i := 0; i < len(script); i += chunkLen {
+		if script[i] != byte(opcode.PUSHDATA1) || script[i+1] != keys.SignatureLen {
+			return nil, fmt.Errorf("invalid invocation script at %d", i)
+		}
+		sigs = append(sigs, script[i+2:i+chunkLen])
+	}
+	return sigs, nil
+}
+
+// verifyWitness checks standard signature or multisignature witness of hh
+// without running the VM. Signatures of the multisignature witness must be in
+// the same order as the keys.
+func verifyWitness(net uint32, hh hash.Hashable, invocation, verification []byte) error {
+	sigs, err := parseSignatures(invocation)
+	if err != nil {
+		return err
+	}
+	var (
+		m    = 1
+		pubs [][]byte
+	)
+	if n, ps, ok := vm.ParseMultiSigContract(verification); ok {
+		m, pubs = n, ps
+	} else if p, ok := vm.ParseSignatureContract(verification); ok {
+		pubs = [][]byte{p}
+	} else {
+		return errors.New("unsupported verification script")
+	}
+	if len(sigs) != m {
+		return fmt.Errorf("expected %d signatures, got %d", m, len(sigs))
+	}
+	var i, j int
+	for ; i < len(sigs) && j < len(pubs) && len(sigs)-i <= len(pubs)-j; j++ {
+		pub, err := keys.NewPublicKeyFromBytes(pubs[j], elliptic.P256())
+		if err != nil {
+			return fmt.Errorf("invalid public key %d: %w", j, err)
+		}
+		if pub.VerifyHashable(sigs[i], net, hh) {
+			i++
+		}
+	}
+	if i != len(sigs) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
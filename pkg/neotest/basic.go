@@ -20,6 +20,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/profile"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"github.com/stretchr/testify/require"
@@ -32,6 +33,8 @@ type Executor struct {
 	Committee     Signer
 	CommitteeHash util.Uint160
 	Contracts     map[string]*Contract
+
+	profile *profile.Profile
 }
 
 // NewExecutor creates new executor instance from provided blockchain and committee.
@@ -55,6 +58,39 @@ func (e *Executor) TopBlock(t *testing.T) *block.Block {
 	return b
 }
 
+// execHookSetter is implemented by chains supporting VM execution hooks.
+type execHookSetter interface {
+	SetOnExecHook(vm.OnExecHook)
+}
+
+// Profile enables execution profiling for the chain and returns the profile
+// collecting statistics of all scripts executed since then, both in blocks
+// and test invocations (except for system fee calculations done by Executor
+// itself). Use its Report and WriteCoverProfile methods with
+// Contract.DebugInfo to get per-line GAS reports and coverage of the
+// contract. Chain must support execution hooks (like core.Blockchain does).
+func (e *Executor) Profile(t *testing.T) *profile.Profile {
+	if e.profile == nil {
+		bc, ok := e.Chain.(execHookSetter)
+		require.True(t, ok, "chain doesn't support execution hooks")
+		e.profile = profile.New()
+		bc.SetOnExecHook(e.profile.Hook)
+	}
+	return e.profile
+}
+
+// withoutProfile executes f with profiling disabled.
+func (e *Executor) withoutProfile(f func()) {
+	if e.profile == nil {
+		f()
+		return
+	}
+	bc := e.Chain.(execHookSetter)
+	bc.SetOnExecHook(nil)
+	defer bc.SetOnExecHook(e.profile.Hook)
+	f()
+}
+
 // NativeHash returns native contract hash by name.
 func (e *Executor) NativeHash(t *testing.T, name string) util.Uint160 {
 	h, err := e.Chain.GetNativeContractScriptHash(name)
@@ -92,7 +128,7 @@ func (e *Executor) SignTx(t *testing.T, tx *transaction.Transaction, sysFee int6
 		})
 	}
 	addNetworkFee(e.Chain, tx, signers...)
-	addSystemFee(e.Chain, tx, sysFee)
+	e.withoutProfile(func() { addSystemFee(e.Chain, tx, sysFee) })
 
 	for _, acc := range signers {
 		require.NoError(t, acc.SignTx(e.Chain.GetConfig().Magic, tx))
@@ -1,8 +1,10 @@
 package chain
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
 	"github.com/stretchr/testify/require"
 )
@@ -19,3 +21,31 @@ func TestNewMulti(t *testing.T) {
 	c := e.CommitteeInvoker(bc.UtilityTokenHash()).WithSigners(vAcc)
 	c.Invoke(t, true, "transfer", e.Validator.ScriptHash(), e.Committee.ScriptHash(), amount, nil)
 }
+
+func TestExecutor_Profile(t *testing.T) {
+	bc, acc := NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	src := `package foo
+	func Double(a int) int {
+		return a * 2
+	}`
+	ctr := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(src), &compiler.Options{Name: "foo"})
+	e.DeployContract(t, ctr, nil)
+
+	p := e.Profile(t)
+	require.Equal(t, p, e.Profile(t))
+
+	c := e.CommitteeInvoker(ctr.Hash)
+	c.Invoke(t, 4, "double", int64(2))
+	_, err := c.TestInvoke(t, "double", int64(3))
+	require.NoError(t, err)
+
+	r := p.Report(ctr.Hash, ctr.DebugInfo)
+	require.Equal(t, 1, len(r.Methods))
+	require.Equal(t, 2, r.Methods[0].Calls)
+	require.Equal(t, 1, len(r.Lines))
+	require.Equal(t, 3, r.Lines[0].Line)
+	require.Equal(t, 2, r.Lines[0].Count)
+	require.True(t, r.GAS > 0)
+}
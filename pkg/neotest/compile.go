@@ -19,6 +19,9 @@ type Contract struct {
 	Hash     util.Uint160
 	NEF      *nef.File
 	Manifest *manifest.Manifest
+	// DebugInfo is the contract debug information, it can be used to
+	// map execution profile (see Executor.Profile) to contract sources.
+	DebugInfo *compiler.DebugInfo
 }
 
 // contracts caches compiled contracts from FS across multiple tests.
@@ -39,9 +42,10 @@ func CompileSource(t *testing.T, sender util.Uint160, src io.Reader, opts *compi
 	require.NoError(t, err)
 
 	return &Contract{
-		Hash:     state.CreateContractHash(sender, ne.Checksum, m.Name),
-		NEF:      ne,
-		Manifest: m,
+		Hash:      state.CreateContractHash(sender, ne.Checksum, m.Name),
+		NEF:       ne,
+		Manifest:  m,
+		DebugInfo: di,
 	}
 }
 
@@ -76,9 +80,10 @@ func CompileFile(t *testing.T, sender util.Uint160, srcPath string, configPath s
 	require.NoError(t, err)
 
 	c := &Contract{
-		Hash:     state.CreateContractHash(sender, ne.Checksum, m.Name),
-		NEF:      ne,
-		Manifest: m,
+		Hash:      state.CreateContractHash(sender, ne.Checksum, m.Name),
+		NEF:       ne,
+		Manifest:  m,
+		DebugInfo: di,
 	}
 	contracts[srcPath] = c
 	return c
@@ -0,0 +1,106 @@
+package bloom
+
+import (
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/twmb/murmur3"
+)
+
+// seedMultiplier is used to derive seeds of the hash functions from the tweak.
+const seedMultiplier = 0xFBA4C795
+
+// Filter is a bloom filter used by light (SPV) clients to receive only
+// relevant transactions from the node (see filterload, filteradd and
+// filterclear P2P commands). It's compatible with the C# node implementation
+// and is safe for concurrent use.
+type Filter struct {
+	lock  sync.RWMutex
+	bits  []byte
+	tweak uint32
+	seeds []uint32
+}
+
+// New creates a filter with the given contents (its length in bits is the
+// size of the filter), number of hash functions k and tweak. The contents
+// are copied, so bits can be an empty slice of the desired size.
+func New(bits []byte, k uint8, tweak uint32) *Filter {
+	f := &Filter{
+		bits:  make([]byte, len(bits)),
+		tweak: tweak,
+		seeds: make([]uint32, k),
+	}
+	copy(f.bits, bits)
+	for i := range f.seeds {
+		f.seeds[i] = uint32(i)*seedMultiplier + tweak
+	}
+	return f
+}
+
+// Bits returns a copy of the filter contents.
+func (f *Filter) Bits() []byte {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	res := make([]byte, len(f.bits))
+	copy(res, f.bits)
+	return res
+}
+
+// K returns the number of hash functions used by the filter.
+func (f *Filter) K() uint8 {
+	return uint8(len(f.seeds))
+}
+
+// Tweak returns the tweak used by the filter.
+func (f *Filter) Tweak() uint32 {
+	return f.tweak
+}
+
+// Add adds data to the filter.
+func (f *Filter) Add(data []byte) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for _, s := range f.seeds {
+		if i, ok := f.index(s, data); ok {
+			f.bits[i/8] |= 1 << (i % 8)
+		}
+	}
+}
+
+// Check returns true if data may be in the filter and false if it's
+// definitely not there.
+func (f *Filter) Check(data []byte) bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	for _, s := range f.seeds {
+		i, ok := f.index(s, data)
+		if !ok || f.bits[i/8]&(1<<(i%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// index returns the bit index for data using hash function with the given seed.
+func (f *Filter) index(seed uint32, data []byte) (uint32, bool) {
+	m := uint32(len(f.bits)) * 8
+	if m == 0 {
+		return 0, false
+	}
+	return murmur3.SeedSum32(seed, data) % m, true
+}
+
+// MatchTx checks whether transaction matches the filter, that is its hash or
+// any of its signers' accounts are in the filter.
+func (f *Filter) MatchTx(tx *transaction.Transaction) bool {
+	h := tx.Hash()
+	if f.Check(h.BytesBE()) {
+		return true
+	}
+	for i := range tx.Signers {
+		if f.Check(tx.Signers[i].Account.BytesBE()) {
+			return true
+		}
+	}
+	return false
+}
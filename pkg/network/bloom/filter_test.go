@@ -0,0 +1,57 @@
+package bloom
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	f := New(make([]byte, 128), 5, 42)
+	require.Equal(t, uint8(5), f.K())
+	require.Equal(t, uint32(42), f.Tweak())
+
+	data := [][]byte{[]byte("one"), []byte("two"), random.Bytes(20)}
+	for _, d := range data {
+		require.False(t, f.Check(d))
+		f.Add(d)
+		require.True(t, f.Check(d))
+	}
+
+	// Restored filter contains the same elements.
+	restored := New(f.Bits(), f.K(), f.Tweak())
+	for _, d := range data {
+		require.True(t, restored.Check(d))
+	}
+	// Different tweak gives different hash functions.
+	other := New(make([]byte, 128), 5, 43)
+	for _, d := range data {
+		other.Add(d)
+	}
+	require.NotEqual(t, f.Bits(), other.Bits())
+
+	t.Run("empty", func(t *testing.T) {
+		f := New(nil, 5, 0)
+		f.Add(data[0])
+		require.False(t, f.Check(data[0]))
+	})
+}
+
+func TestFilterMatchTx(t *testing.T) {
+	tx := transaction.New([]byte{byte(0x51)}, 1)
+	tx.Signers = []transaction.Signer{{Account: util.Uint160{1, 2, 3}}, {Account: util.Uint160{4, 5, 6}}}
+
+	f := New(make([]byte, 64), 3, 0)
+	require.False(t, f.MatchTx(tx))
+
+	f.Add(tx.Signers[1].Account.BytesBE())
+	require.True(t, f.MatchTx(tx))
+
+	f = New(make([]byte, 64), 3, 0)
+	h := tx.Hash()
+	f.Add(h.BytesBE())
+	require.True(t, f.MatchTx(tx))
+}
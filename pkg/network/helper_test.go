@@ -11,6 +11,7 @@ import (
 	"github.com/nspcc-dev/neo-go/internal/fakechain"
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/bloom"
 	"github.com/nspcc-dev/neo-go/pkg/network/capability"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/stretchr/testify/require"
@@ -77,6 +78,7 @@ type localPeer struct {
 	pingSent       int
 	getAddrSent    int
 	droppedWith    atomic.Value
+	filter         *bloom.Filter
 }
 
 func newLocalPeer(t *testing.T, s *Server) *localPeer {
@@ -186,6 +188,12 @@ func (p *localPeer) CanProcessAddr() bool {
 	p.getAddrSent--
 	return p.getAddrSent >= 0
 }
+func (p *localPeer) BloomFilter() *bloom.Filter {
+	return p.filter
+}
+func (p *localPeer) SetBloomFilter(f *bloom.Filter) {
+	p.filter = f
+}
 
 func newTestServer(t *testing.T, serverConfig ServerConfig) *Server {
 	return newTestServerWithCustomCfg(t, serverConfig, nil)
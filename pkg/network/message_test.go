@@ -244,13 +244,25 @@ func TestEncodeDecodeMerkleBlock(t *testing.T) {
 	t.Run("bad, invalid TxCount", func(t *testing.T) {
 		testEncodeDecodeFail(t, CMDMerkleBlock, &payload.MerkleBlock{
 			Header:  base,
-			TxCount: 2,
-			Hashes:  []util.Uint256{random.Uint256()},
+			TxCount: 1,
+			Hashes:  []util.Uint256{random.Uint256(), random.Uint256()},
 			Flags:   []byte{0},
 		})
 	})
 }
 
+func TestEncodeDecodeFilterLoad(t *testing.T) {
+	testEncodeDecode(t, CMDFilterLoad, &payload.FilterLoad{
+		Filter: random.Bytes(100),
+		K:      5,
+		Tweak:  rand.Uint32(),
+	})
+}
+
+func TestEncodeDecodeFilterAdd(t *testing.T) {
+	testEncodeDecode(t, CMDFilterAdd, &payload.FilterAdd{Data: random.Bytes(20)})
+}
+
 func TestEncodeDecodeNotFound(t *testing.T) {
 	testEncodeDecode(t, CMDNotFound, &payload.Inventory{
 		Type:   payload.TXType,
@@ -0,0 +1,24 @@
+package payload
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/io"
+)
+
+// MaxFilterAddDataSize is the maximum size of data added to bloom filter.
+const MaxFilterAddDataSize = 520
+
+// FilterAdd payload for filteradd command, it adds data to the bloom filter
+// of the peer.
+type FilterAdd struct {
+	Data []byte
+}
+
+// DecodeBinary implements Serializable interface.
+func (f *FilterAdd) DecodeBinary(br *io.BinReader) {
+	f.Data = br.ReadVarBytes(MaxFilterAddDataSize)
+}
+
+// EncodeBinary implements Serializable interface.
+func (f *FilterAdd) EncodeBinary(bw *io.BinWriter) {
+	bw.WriteVarBytes(f.Data)
+}
@@ -0,0 +1,17 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/internal/testserdes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterAdd_EncodeDecodeBinary(t *testing.T) {
+	testserdes.EncodeDecodeBinary(t, &FilterAdd{Data: random.Bytes(20)}, new(FilterAdd))
+
+	data, err := testserdes.EncodeBinary(&FilterAdd{Data: make([]byte, MaxFilterAddDataSize+1)})
+	require.NoError(t, err)
+	require.Error(t, testserdes.DecodeBinary(data, new(FilterAdd)))
+}
@@ -0,0 +1,43 @@
+package payload
+
+import (
+	"errors"
+
+	"github.com/nspcc-dev/neo-go/pkg/io"
+)
+
+const (
+	// MaxFilterSize is the maximum size of bloom filter in bytes.
+	MaxFilterSize = 36000
+	// MaxFilterHashFuncs is the maximum number of hash functions used by bloom filter.
+	MaxFilterHashFuncs = 50
+)
+
+// FilterLoad payload for filterload command, it sets bloom filter for the
+// peer, so that only matching transactions are relayed to it.
+type FilterLoad struct {
+	// Filter is a bloom filter contents.
+	Filter []byte
+	// K is the number of hash functions.
+	K uint8
+	// Tweak is used to derive hash function seeds.
+	Tweak uint32
+}
+
+// DecodeBinary implements Serializable interface.
+func (f *FilterLoad) DecodeBinary(br *io.BinReader) {
+	f.Filter = br.ReadVarBytes(MaxFilterSize)
+	f.K = br.ReadB()
+	if br.Err == nil && f.K > MaxFilterHashFuncs {
+		br.Err = errors.New("too many hash functions")
+		return
+	}
+	f.Tweak = br.ReadU32LE()
+}
+
+// EncodeBinary implements Serializable interface.
+func (f *FilterLoad) EncodeBinary(bw *io.BinWriter) {
+	bw.WriteVarBytes(f.Filter)
+	bw.WriteB(f.K)
+	bw.WriteU32LE(f.Tweak)
+}
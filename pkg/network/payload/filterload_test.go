@@ -0,0 +1,30 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/internal/testserdes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterLoad_EncodeDecodeBinary(t *testing.T) {
+	t.Run("good", func(t *testing.T) {
+		f := &FilterLoad{
+			Filter: random.Bytes(100),
+			K:      5,
+			Tweak:  42,
+		}
+		testserdes.EncodeDecodeBinary(t, f, new(FilterLoad))
+	})
+	t.Run("too big filter", func(t *testing.T) {
+		data, err := testserdes.EncodeBinary(&FilterLoad{Filter: make([]byte, MaxFilterSize+1)})
+		require.NoError(t, err)
+		require.Error(t, testserdes.DecodeBinary(data, new(FilterLoad)))
+	})
+	t.Run("too many hash functions", func(t *testing.T) {
+		data, err := testserdes.EncodeBinary(&FilterLoad{Filter: random.Bytes(10), K: MaxFilterHashFuncs + 1})
+		require.NoError(t, err)
+		require.Error(t, testserdes.DecodeBinary(data, new(FilterLoad)))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 )
@@ -11,9 +12,37 @@ import (
 // MerkleBlock represents a merkle block packet payload.
 type MerkleBlock struct {
 	*block.Header
+	// TxCount is the number of transactions in the block.
 	TxCount int
-	Hashes  []util.Uint256
-	Flags   []byte
+	// Hashes are partial merkle tree hashes (see hash.MerkleTree.PartialHashes).
+	Hashes []util.Uint256
+	// Flags is a bit array with bits set for matching transactions.
+	Flags []byte
+}
+
+// NewMerkleBlock creates merkle block payload for the block with transactions
+// marked by flags (flags[i] corresponds to the i-th transaction).
+func NewMerkleBlock(b *block.Block, flags []bool) *MerkleBlock {
+	m := &MerkleBlock{
+		Header:  &b.Header,
+		TxCount: len(b.Transactions),
+		Hashes:  []util.Uint256{},
+		Flags:   make([]byte, (len(b.Transactions)+7)/8),
+	}
+	for i := range flags {
+		if i < len(b.Transactions) && flags[i] {
+			m.Flags[i/8] |= 1 << (i % 8)
+		}
+	}
+	if len(b.Transactions) != 0 {
+		hashes := make([]util.Uint256, len(b.Transactions))
+		for i, tx := range b.Transactions {
+			hashes[i] = tx.Hash()
+		}
+		tree, _ := hash.NewMerkleTree(hashes)
+		m.Hashes = tree.PartialHashes(flags)
+	}
+	return m
 }
 
 // DecodeBinary implements Serializable interface.
@@ -28,8 +57,9 @@ func (m *MerkleBlock) DecodeBinary(br *io.BinReader) {
 	}
 	m.TxCount = txCount
 	br.ReadArray(&m.Hashes, m.TxCount)
-	if txCount != len(m.Hashes) {
-		br.Err = errors.New("invalid tx count")
+	if br.Err == nil && txCount != 0 && len(m.Hashes) == 0 {
+		br.Err = errors.New("no hashes")
+		return
 	}
 	m.Flags = br.ReadVarBytes((txCount + 7) / 8)
 }
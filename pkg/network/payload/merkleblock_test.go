@@ -55,6 +55,32 @@ func TestMerkleBlock_EncodeDecodeBinary(t *testing.T) {
 		require.True(t, errors.Is(block.ErrMaxContentsPerBlock, testserdes.DecodeBinary(data, new(MerkleBlock))))
 	})
 
+	t.Run("partial hashes", func(t *testing.T) {
+		b := newDumbBlock()
+		_ = b.Hash()
+		expected := &MerkleBlock{
+			Header:  b,
+			TxCount: 3,
+			Hashes:  []util.Uint256{{1}},
+			Flags:   []byte{0},
+		}
+		testserdes.EncodeDecodeBinary(t, expected, new(MerkleBlock))
+	})
+
+	t.Run("no hashes", func(t *testing.T) {
+		b := newDumbBlock()
+		_ = b.Hash()
+		expected := &MerkleBlock{
+			Header:  b,
+			TxCount: 3,
+			Hashes:  []util.Uint256{},
+			Flags:   []byte{0},
+		}
+		data, err := testserdes.EncodeBinary(expected)
+		require.NoError(t, err)
+		require.Error(t, testserdes.DecodeBinary(data, new(MerkleBlock)))
+	})
+
 	t.Run("bad flags size", func(t *testing.T) {
 		b := newDumbBlock()
 		_ = b.Hash()
@@ -69,3 +95,30 @@ func TestMerkleBlock_EncodeDecodeBinary(t *testing.T) {
 		require.Error(t, testserdes.DecodeBinary(data, new(MerkleBlock)))
 	})
 }
+
+func TestNewMerkleBlock(t *testing.T) {
+	b := &block.Block{Header: *newDumbBlock()}
+	_ = b.Hash()
+	for i := 0; i < 10; i++ {
+		b.Transactions = append(b.Transactions, transaction.New([]byte{byte(i)}, 1))
+	}
+	hashes := make([]util.Uint256, len(b.Transactions))
+	for i := range b.Transactions {
+		hashes[i] = b.Transactions[i].Hash()
+	}
+	tree, err := hash.NewMerkleTree(hashes)
+	require.NoError(t, err)
+
+	flags := make([]bool, len(b.Transactions))
+	flags[1], flags[9] = true, true
+	m := NewMerkleBlock(b, flags)
+	require.Equal(t, &b.Header, m.Header)
+	require.Equal(t, 10, m.TxCount)
+	require.Equal(t, []byte{0x02, 0x02}, m.Flags)
+	require.Equal(t, tree.PartialHashes(flags), m.Hashes)
+	testserdes.EncodeDecodeBinary(t, m, new(MerkleBlock))
+
+	m = NewMerkleBlock(b, nil)
+	require.Equal(t, []util.Uint256{tree.Root()}, m.Hashes)
+	require.Equal(t, []byte{0, 0}, m.Flags)
+}
@@ -3,6 +3,7 @@ package network
 import (
 	"net"
 
+	"github.com/nspcc-dev/neo-go/pkg/network/bloom"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 )
 
@@ -81,4 +82,10 @@ type Peer interface {
 	// CanProcessAddr checks whether an addr command is expected to come from
 	// this peer and can be processed.
 	CanProcessAddr() bool
+
+	// BloomFilter returns bloom filter loaded by the peer or nil if there is
+	// no filter, in this case all transactions are relayed to the peer.
+	BloomFilter() *bloom.Filter
+	// SetBloomFilter sets (or removes if nil) bloom filter for the peer.
+	SetBloomFilter(*bloom.Filter)
 }
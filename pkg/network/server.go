@@ -14,6 +14,7 @@ import (
 
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
 	"github.com/nspcc-dev/neo-go/pkg/consensus"
+	"github.com/nspcc-dev/neo-go/pkg/consensus/monitor"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/blockchainer"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
@@ -21,6 +22,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/bloom"
 	"github.com/nspcc-dev/neo-go/pkg/network/capability"
 	"github.com/nspcc-dev/neo-go/pkg/network/extpool"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
@@ -49,6 +51,7 @@ var (
 	errMaxPeers         = errors.New("max peers reached")
 	errServerShutdown   = errors.New("server shutdown")
 	errInvalidInvType   = errors.New("invalid inventory type")
+	errBloomDisabled    = errors.New("bloom filters are disabled")
 )
 
 type (
@@ -72,6 +75,7 @@ type (
 		bQueue            *blockQueue
 		bSyncQueue        *blockQueue
 		consensus         consensus.Service
+		consensusMonitor  *monitor.Monitor
 		mempool           *mempool.Pool
 		notaryRequestPool *mempool.Pool
 		extensiblePool    *extpool.Pool
@@ -118,9 +122,20 @@ func randomID() uint32 {
 
 // NewServer returns a new Server, initialized with the given configuration.
 func NewServer(config ServerConfig, chain blockchainer.Blockchainer, log *zap.Logger) (*Server, error) {
-	return newServerFromConstructors(config, chain, log, func(s *Server) Transporter {
-		return NewTCPTransport(s, net.JoinHostPort(s.ServerConfig.Address, strconv.Itoa(int(s.ServerConfig.Port))), s.log)
-	}, consensus.NewService, newDefaultDiscovery)
+	addr := net.JoinHostPort(config.Address, strconv.Itoa(int(config.Port)))
+	newTransport := func(s *Server) Transporter {
+		return NewTCPTransport(s, addr, s.log)
+	}
+	if config.P2PEncryptionCfg.Enabled {
+		tlsConfig, err := newTLSConfigFromCfg(config.P2PEncryptionCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize P2P encryption: %w", err)
+		}
+		newTransport = func(s *Server) Transporter {
+			return NewTLSTransport(s, addr, tlsConfig, s.log)
+		}
+	}
+	return newServerFromConstructors(config, chain, log, newTransport, consensus.NewService, newDefaultDiscovery)
 }
 
 func newServerFromConstructors(config ServerConfig, chain blockchainer.Blockchainer, log *zap.Logger,
@@ -138,6 +153,13 @@ func newServerFromConstructors(config ServerConfig, chain blockchainer.Blockchai
 			zap.Int("ExtensiblePoolSize", config.ExtensiblePoolSize))
 	}
 
+	if config.BloomFilterCfg.Enabled &&
+		(config.BloomFilterCfg.MaxSize <= 0 || config.BloomFilterCfg.MaxSize > payload.MaxFilterSize) {
+		config.BloomFilterCfg.MaxSize = payload.MaxFilterSize
+		log.Info("BloomFilter.MaxSize is not set or wrong, using default value",
+			zap.Int("MaxSize", config.BloomFilterCfg.MaxSize))
+	}
+
 	s := &Server{
 		ServerConfig:      config,
 		chain:             chain,
@@ -224,6 +246,10 @@ func newServerFromConstructors(config ServerConfig, chain blockchainer.Blockchai
 		chain.SetOracle(orc)
 	}
 
+	if config.ConsensusMonitorCfg.Enabled {
+		s.consensusMonitor = monitor.New(config.ConsensusMonitorCfg.HistorySize)
+	}
+
 	srv, err := newConsensus(consensus.Config{
 		Logger:                log,
 		Broadcast:             s.handleNewPayload,
@@ -231,6 +257,7 @@ func newServerFromConstructors(config ServerConfig, chain blockchainer.Blockchai
 		ProtocolConfiguration: chain.GetConfig(),
 		RequestTx:             s.requestTx,
 		Wallet:                config.Wallet,
+		Monitor:               s.consensusMonitor,
 
 		TimePerBlock: config.TimePerBlock,
 	})
@@ -282,6 +309,9 @@ func (s *Server) Start(errChan chan error) {
 		zap.Uint32("blockHeight", s.chain.BlockHeight()),
 		zap.Uint32("headerHeight", s.chain.HeaderHeight()))
 
+	if s.consensusMonitor != nil {
+		s.consensusMonitor.Run()
+	}
 	s.tryStartServices()
 	s.initStaleMemPools()
 
@@ -300,6 +330,9 @@ func (s *Server) Shutdown() {
 	s.transport.Close()
 	s.discovery.Close()
 	s.consensus.Shutdown()
+	if s.consensusMonitor != nil {
+		s.consensusMonitor.Shutdown()
+	}
 	for _, p := range s.getPeers(nil) {
 		p.Disconnect(errServerShutdown)
 	}
@@ -460,7 +493,7 @@ func (s *Server) tryStartServices() {
 
 	if s.IsInSync() && s.syncReached.CAS(false, true) {
 		s.log.Info("node reached synchronized state, starting services")
-		if s.Wallet != nil {
+		if s.Wallet != nil || s.consensusMonitor != nil {
 			s.consensus.Start()
 		}
 		if s.StateRootCfg.Enabled {
@@ -502,6 +535,12 @@ func (s *Server) UnsubscribeFromNotaryRequests(ch chan<- mempoolevent.Event) {
 	s.notaryRequestPool.UnsubscribeFromTransactions(ch)
 }
 
+// ConsensusMonitor returns consensus rounds monitor or nil if consensus
+// monitoring is disabled.
+func (s *Server) ConsensusMonitor() *monitor.Monitor {
+	return s.consensusMonitor
+}
+
 // getPeers returns current list of peers connected to the server filtered by
 // isOK function if it's given.
 func (s *Server) getPeers(isOK func(Peer) bool) []Peer {
@@ -747,6 +786,9 @@ func (s *Server) handleInvCmd(p Peer, inv *payload.Inventory) error {
 // handleMempoolCmd handles getmempool command.
 func (s *Server) handleMempoolCmd(p Peer) error {
 	txs := s.mempool.GetVerifiedTransactions()
+	if f := p.BloomFilter(); f != nil {
+		txs = filterTxs(f, txs)
+	}
 	hs := make([]util.Uint256, 0, payload.MaxHashesCount)
 	for i := range txs {
 		hs = append(hs, txs[i].Hash())
@@ -780,7 +822,11 @@ func (s *Server) handleGetDataCmd(p Peer, inv *payload.Inventory) error {
 		case payload.BlockType:
 			b, err := s.chain.GetBlock(hash)
 			if err == nil {
-				msg = NewMessage(CMDBlock, b)
+				if f := p.BloomFilter(); f != nil {
+					msg = NewMessage(CMDMerkleBlock, newMerkleBlock(f, b))
+				} else {
+					msg = NewMessage(CMDBlock, b)
+				}
 			} else {
 				notFound = append(notFound, hash)
 			}
@@ -815,6 +861,59 @@ func (s *Server) handleGetDataCmd(p Peer, inv *payload.Inventory) error {
 	return nil
 }
 
+// newMerkleBlock creates merkle block payload for the block with transactions
+// matching the bloom filter.
+func newMerkleBlock(f *bloom.Filter, b *block.Block) *payload.MerkleBlock {
+	flags := make([]bool, len(b.Transactions))
+	for i := range b.Transactions {
+		flags[i] = f.MatchTx(b.Transactions[i])
+	}
+	return payload.NewMerkleBlock(b, flags)
+}
+
+// filterTxs returns transactions matching the bloom filter.
+func filterTxs(f *bloom.Filter, txs []*transaction.Transaction) []*transaction.Transaction {
+	var res []*transaction.Transaction
+	for _, tx := range txs {
+		if f.MatchTx(tx) {
+			res = append(res, tx)
+		}
+	}
+	return res
+}
+
+// handleFilterLoadCmd sets bloom filter for the peer.
+func (s *Server) handleFilterLoadCmd(p Peer, fl *payload.FilterLoad) error {
+	if !s.BloomFilterCfg.Enabled {
+		return errBloomDisabled
+	}
+	if len(fl.Filter) > s.BloomFilterCfg.MaxSize {
+		return fmt.Errorf("bloom filter is too big: %d bytes, max %d", len(fl.Filter), s.BloomFilterCfg.MaxSize)
+	}
+	p.SetBloomFilter(bloom.New(fl.Filter, fl.K, fl.Tweak))
+	return nil
+}
+
+// handleFilterAddCmd adds data to the bloom filter of the peer.
+func (s *Server) handleFilterAddCmd(p Peer, fa *payload.FilterAdd) error {
+	if !s.BloomFilterCfg.Enabled {
+		return errBloomDisabled
+	}
+	if f := p.BloomFilter(); f != nil {
+		f.Add(fa.Data)
+	}
+	return nil
+}
+
+// handleFilterClearCmd removes bloom filter of the peer.
+func (s *Server) handleFilterClearCmd(p Peer) error {
+	if !s.BloomFilterCfg.Enabled {
+		return errBloomDisabled
+	}
+	p.SetBloomFilter(nil)
+	return nil
+}
+
 // handleGetMPTDataCmd processes the received MPT inventory.
 func (s *Server) handleGetMPTDataCmd(p Peer, inv *payload.MPTInventory) error {
 	if !s.chain.GetConfig().P2PStateExchangeExtensions {
@@ -920,7 +1019,12 @@ func (s *Server) handleGetBlockByIndexCmd(p Peer, gbd *payload.GetBlockByIndex)
 		if err != nil {
 			break
 		}
-		msg := NewMessage(CMDBlock, b)
+		var msg *Message
+		if f := p.BloomFilter(); f != nil {
+			msg = NewMessage(CMDMerkleBlock, newMerkleBlock(f, b))
+		} else {
+			msg = NewMessage(CMDBlock, b)
+		}
 		if err = p.EnqueueP2PMessage(msg); err != nil {
 			return err
 		}
@@ -1201,6 +1305,15 @@ func (s *Server) handleMessage(peer Peer, msg *Message) error {
 		case CMDP2PNotaryRequest:
 			r := msg.Payload.(*payload.P2PNotaryRequest)
 			return s.handleP2PNotaryRequestCmd(r)
+		case CMDFilterLoad:
+			fl := msg.Payload.(*payload.FilterLoad)
+			return s.handleFilterLoadCmd(peer, fl)
+		case CMDFilterAdd:
+			fa := msg.Payload.(*payload.FilterAdd)
+			return s.handleFilterAddCmd(peer, fa)
+		case CMDFilterClear:
+			// no payload
+			return s.handleFilterClearCmd(peer)
 		case CMDPing:
 			ping := msg.Payload.(*payload.Ping)
 			return s.handlePing(peer, ping)
@@ -1422,12 +1535,49 @@ func (s *Server) broadcastTX(t *transaction.Transaction, _ interface{}) {
 	}
 }
 
-func (s *Server) broadcastTxHashes(hs []util.Uint256) {
+func (s *Server) broadcastTxs(txs []*transaction.Transaction) {
+	hs := make([]util.Uint256, len(txs))
+	for i := range txs {
+		hs[i] = txs[i].Hash()
+	}
 	msg := NewMessage(CMDInv, payload.NewInventory(payload.TXType, hs))
 
 	// We need to filter out non-relaying nodes, so plain broadcast
 	// functions don't fit here.
-	s.iteratePeersWithSendMsg(msg, Peer.EnqueuePacket, Peer.IsFullNode)
+	s.iteratePeersWithSendMsg(msg, Peer.EnqueuePacket, func(p Peer) bool {
+		return p.IsFullNode() && p.BloomFilter() == nil
+	})
+	if s.BloomFilterCfg.Enabled {
+		s.broadcastFilteredTxs(txs)
+	}
+}
+
+// broadcastFilteredTxs sends inventory messages with transactions matching
+// bloom filters of the peers that have them. Unlike regular broadcast every
+// peer gets its own message and it's never blocked on.
+func (s *Server) broadcastFilteredTxs(txs []*transaction.Transaction) {
+	peers := s.getPeers(func(p Peer) bool {
+		return p.IsFullNode() && p.BloomFilter() != nil
+	})
+	for _, p := range peers {
+		f := p.BloomFilter()
+		if f == nil { // Could've been cleared already.
+			continue
+		}
+		matched := filterTxs(f, txs)
+		if len(matched) == 0 {
+			continue
+		}
+		hs := make([]util.Uint256, len(matched))
+		for i := range matched {
+			hs[i] = matched[i].Hash()
+		}
+		pkt, err := NewMessage(CMDInv, payload.NewInventory(payload.TXType, hs)).Bytes()
+		if err != nil {
+			return
+		}
+		_ = p.EnqueuePacket(false, pkt)
+	}
 }
 
 // initStaleMemPools initializes mempools for stale tx/payload processing.
@@ -1452,7 +1602,7 @@ func (s *Server) broadcastTxLoop() {
 		batchSize = 32
 	)
 
-	txs := make([]util.Uint256, 0, batchSize)
+	txs := make([]*transaction.Transaction, 0, batchSize)
 	var timer *time.Timer
 
 	timerCh := func() <-chan time.Time {
@@ -1463,7 +1613,7 @@ func (s *Server) broadcastTxLoop() {
 	}
 
 	broadcast := func() {
-		s.broadcastTxHashes(txs)
+		s.broadcastTxs(txs)
 		txs = txs[:0]
 		if timer != nil {
 			timer.Stop()
@@ -1491,7 +1641,7 @@ func (s *Server) broadcastTxLoop() {
 				timer = time.NewTimer(batchTime)
 			}
 
-			txs = append(txs, tx.Hash())
+			txs = append(txs, tx)
 			if len(txs) == batchSize {
 				broadcast()
 			}
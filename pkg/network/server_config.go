@@ -81,6 +81,15 @@ type (
 
 		// ExtensiblePoolSize is size of the pool for extensible payloads from a single sender.
 		ExtensiblePoolSize int
+
+		// BloomFilterCfg is configuration of bloom filters used by SPV clients.
+		BloomFilterCfg config.BloomFilter
+
+		// ConsensusMonitorCfg is configuration of consensus rounds monitoring.
+		ConsensusMonitorCfg config.ConsensusMonitor
+
+		// P2PEncryptionCfg is configuration of P2P connections encryption.
+		P2PEncryptionCfg config.P2PEncryption
 	}
 )
 
@@ -96,25 +105,28 @@ func NewServerConfig(cfg config.Config) ServerConfig {
 	}
 
 	return ServerConfig{
-		UserAgent:          cfg.GenerateUserAgent(),
-		Address:            appConfig.Address,
-		AnnouncedPort:      appConfig.AnnouncedNodePort,
-		Port:               appConfig.NodePort,
-		Net:                protoConfig.Magic,
-		Relay:              appConfig.Relay,
-		Seeds:              protoConfig.SeedList,
-		DialTimeout:        time.Duration(appConfig.DialTimeout) * time.Second,
-		ProtoTickInterval:  time.Duration(appConfig.ProtoTickInterval) * time.Second,
-		PingInterval:       time.Duration(appConfig.PingInterval) * time.Second,
-		PingTimeout:        time.Duration(appConfig.PingTimeout) * time.Second,
-		MaxPeers:           appConfig.MaxPeers,
-		AttemptConnPeers:   appConfig.AttemptConnPeers,
-		MinPeers:           appConfig.MinPeers,
-		Wallet:             wc,
-		TimePerBlock:       time.Duration(protoConfig.SecondsPerBlock) * time.Second,
-		OracleCfg:          appConfig.Oracle,
-		P2PNotaryCfg:       appConfig.P2PNotary,
-		StateRootCfg:       appConfig.StateRoot,
-		ExtensiblePoolSize: appConfig.ExtensiblePoolSize,
+		UserAgent:           cfg.GenerateUserAgent(),
+		Address:             appConfig.Address,
+		AnnouncedPort:       appConfig.AnnouncedNodePort,
+		Port:                appConfig.NodePort,
+		Net:                 protoConfig.Magic,
+		Relay:               appConfig.Relay,
+		Seeds:               protoConfig.SeedList,
+		DialTimeout:         time.Duration(appConfig.DialTimeout) * time.Second,
+		ProtoTickInterval:   time.Duration(appConfig.ProtoTickInterval) * time.Second,
+		PingInterval:        time.Duration(appConfig.PingInterval) * time.Second,
+		PingTimeout:         time.Duration(appConfig.PingTimeout) * time.Second,
+		MaxPeers:            appConfig.MaxPeers,
+		AttemptConnPeers:    appConfig.AttemptConnPeers,
+		MinPeers:            appConfig.MinPeers,
+		Wallet:              wc,
+		TimePerBlock:        time.Duration(protoConfig.SecondsPerBlock) * time.Second,
+		OracleCfg:           appConfig.Oracle,
+		P2PNotaryCfg:        appConfig.P2PNotary,
+		StateRootCfg:        appConfig.StateRoot,
+		ExtensiblePoolSize:  appConfig.ExtensiblePoolSize,
+		BloomFilterCfg:      appConfig.BloomFilter,
+		ConsensusMonitorCfg: appConfig.ConsensusMonitor,
+		P2PEncryptionCfg:    appConfig.P2PEncryption,
 	}
 }
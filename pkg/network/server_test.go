@@ -6,6 +6,7 @@ import (
 	"math/big"
 	"net"
 	"strconv"
+	"sync"
 	atomic2 "sync/atomic"
 	"testing"
 	"time"
@@ -127,6 +128,21 @@ func TestServerStartAndShutdown(t *testing.T) {
 		s.Shutdown()
 		<-ch
 
+		require.True(t, s.consensus.(*fakeConsensus).stopped.Load())
+	})
+	t.Run("watch-only consensus", func(t *testing.T) {
+		s := newTestServer(t, ServerConfig{ConsensusMonitorCfg: config.ConsensusMonitor{Enabled: true}})
+		require.NotNil(t, s.ConsensusMonitor())
+
+		ch := startWithChannel(s)
+		p := newLocalPeer(t, s)
+		s.register <- p
+
+		assert.True(t, s.consensus.(*fakeConsensus).started.Load())
+
+		s.Shutdown()
+		<-ch
+
 		require.True(t, s.consensus.(*fakeConsensus).stopped.Load())
 	})
 }
@@ -1018,6 +1034,118 @@ func TestMemPool(t *testing.T) {
 	require.ElementsMatch(t, expected, actual)
 }
 
+func TestBloomFilter(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		s := startTestServer(t)
+		p := newLocalPeer(t, s)
+		p.handshaked = true
+		require.Error(t, s.handleMessage(p, NewMessage(CMDFilterLoad, &payload.FilterLoad{Filter: make([]byte, 10), K: 1})))
+		require.Error(t, s.handleMessage(p, NewMessage(CMDFilterAdd, &payload.FilterAdd{Data: []byte{1}})))
+		require.Error(t, s.handleMessage(p, NewMessage(CMDFilterClear, payload.NewNullPayload())))
+		require.Nil(t, p.BloomFilter())
+	})
+
+	s := newTestServer(t, ServerConfig{UserAgent: "/test/", BloomFilterCfg: config.BloomFilter{Enabled: true, MaxSize: 100}})
+	startWithCleanup(t, s)
+	bc := s.chain.(*fakechain.FakeChain)
+	bc.UtilityTokenBalance = big.NewInt(1000000)
+
+	var (
+		lock     sync.Mutex
+		inv      []util.Uint256
+		merkleBl *payload.MerkleBlock
+	)
+	p := newLocalPeer(t, s)
+	p.handshaked = true
+	p.isFullNode = true
+	p.messageHandler = func(t *testing.T, msg *Message) {
+		lock.Lock()
+		defer lock.Unlock()
+		switch msg.Command {
+		case CMDInv:
+			inv = append(inv, msg.Payload.(*payload.Inventory).Hashes...)
+		case CMDMerkleBlock:
+			merkleBl = msg.Payload.(*payload.MerkleBlock)
+		}
+	}
+	s.register <- p
+	require.Eventually(t, func() bool { return 1 == s.PeerCount() }, time.Second, time.Millisecond*10)
+
+	b := newDummyBlock(5, 3)
+	bc.PutBlock(b)
+	matching := b.Transactions[1]
+
+	t.Run("too big filter", func(t *testing.T) {
+		require.Error(t, s.handleMessage(p, NewMessage(CMDFilterLoad, &payload.FilterLoad{Filter: make([]byte, 101), K: 1})))
+		require.Nil(t, p.BloomFilter())
+	})
+	s.testHandleMessage(t, p, CMDFilterLoad, &payload.FilterLoad{Filter: make([]byte, 100), K: 5, Tweak: 42})
+	require.NotNil(t, p.BloomFilter())
+	s.testHandleMessage(t, p, CMDFilterAdd, &payload.FilterAdd{Data: matching.Signers[0].Account.BytesBE()})
+	require.True(t, p.BloomFilter().MatchTx(matching))
+
+	t.Run("merkle block", func(t *testing.T) {
+		s.testHandleMessage(t, p, CMDGetData, payload.NewInventory(payload.BlockType, []util.Uint256{b.Hash()}))
+		require.Eventually(t, func() bool {
+			lock.Lock()
+			defer lock.Unlock()
+			return merkleBl != nil
+		}, time.Second, time.Millisecond*10)
+		expected := newMerkleBlock(p.BloomFilter(), b)
+		require.Equal(t, []byte{0x02}, expected.Flags)
+		require.Equal(t, b.Hash(), merkleBl.Header.Hash())
+		require.Equal(t, expected.TxCount, merkleBl.TxCount)
+		require.Equal(t, expected.Hashes, merkleBl.Hashes)
+		require.Equal(t, expected.Flags, merkleBl.Flags)
+	})
+	t.Run("merkle block by index", func(t *testing.T) {
+		lock.Lock()
+		merkleBl = nil
+		lock.Unlock()
+		s.testHandleMessage(t, p, CMDGetBlockByIndex, &payload.GetBlockByIndex{IndexStart: b.Index, Count: 1})
+		require.Eventually(t, func() bool {
+			lock.Lock()
+			defer lock.Unlock()
+			return merkleBl != nil
+		}, time.Second, time.Millisecond*10)
+		expected := newMerkleBlock(p.BloomFilter(), b)
+		require.Equal(t, b.Hash(), merkleBl.Header.Hash())
+		require.Equal(t, expected.Hashes, merkleBl.Hashes)
+		require.Equal(t, expected.Flags, merkleBl.Flags)
+	})
+	t.Run("mempool", func(t *testing.T) {
+		lock.Lock()
+		inv = nil
+		lock.Unlock()
+		for _, tx := range b.Transactions {
+			require.NoError(t, bc.Pool.Add(tx, &feerStub{blockHeight: 10}))
+		}
+		s.testHandleMessage(t, p, CMDMempool, payload.NullPayload{})
+		lock.Lock()
+		defer lock.Unlock()
+		require.Equal(t, []util.Uint256{matching.Hash()}, inv)
+	})
+	t.Run("relay", func(t *testing.T) {
+		lock.Lock()
+		inv = nil
+		lock.Unlock()
+		tx := newDummyTx()
+		p.BloomFilter().Add(tx.Signers[0].Account.BytesBE())
+		s.testHandleMessage(t, nil, CMDTX, newDummyTx())
+		s.testHandleMessage(t, nil, CMDTX, tx)
+		require.Eventually(t, func() bool {
+			lock.Lock()
+			defer lock.Unlock()
+			return len(inv) != 0
+		}, time.Second, time.Millisecond*10)
+		lock.Lock()
+		defer lock.Unlock()
+		require.Equal(t, []util.Uint256{tx.Hash()}, inv)
+	})
+	s.testHandleMessage(t, p, CMDFilterClear, payload.NewNullPayload())
+	require.Nil(t, p.BloomFilter())
+}
+
 func TestVerifyNotaryRequest(t *testing.T) {
 	bc := fakechain.NewFakeChain()
 	bc.MaxVerificationGAS = 10
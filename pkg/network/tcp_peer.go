@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/bloom"
 	"github.com/nspcc-dev/neo-go/pkg/network/capability"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"go.uber.org/atomic"
@@ -66,6 +67,9 @@ type TCPPeer struct {
 	// number of sent pings.
 	pingSent  int
 	pingTimer *time.Timer
+
+	// bloom filter loaded by the peer (SPV client).
+	filter *bloom.Filter
 }
 
 // NewTCPPeer returns a TCPPeer structure based on the given connection.
@@ -496,3 +500,17 @@ func (p *TCPPeer) CanProcessAddr() bool {
 	v := p.getAddrSent.Dec()
 	return v >= 0
 }
+
+// BloomFilter implements the Peer interface.
+func (p *TCPPeer) BloomFilter() *bloom.Filter {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.filter
+}
+
+// SetBloomFilter implements the Peer interface.
+func (p *TCPPeer) SetBloomFilter(f *bloom.Filter) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.filter = f
+}
@@ -1,6 +1,7 @@
 package network
 
 import (
+	"crypto/tls"
 	"net"
 	"regexp"
 	"sync"
@@ -9,14 +10,16 @@ import (
 	"go.uber.org/zap"
 )
 
-// TCPTransport allows network communication over TCP.
+// TCPTransport allows network communication over TCP, connections can be
+// encrypted with TLS (see NewTLSTransport).
 type TCPTransport struct {
-	log      *zap.Logger
-	server   *Server
-	listener net.Listener
-	bindAddr string
-	lock     sync.RWMutex
-	quit     bool
+	log       *zap.Logger
+	server    *Server
+	listener  net.Listener
+	bindAddr  string
+	tlsConfig *tls.Config
+	lock      sync.RWMutex
+	quit      bool
 }
 
 var reClosedNetwork = regexp.MustCompile(".* use of closed network connection")
@@ -37,6 +40,12 @@ func (t *TCPTransport) Dial(addr string, timeout time.Duration) error {
 	if err != nil {
 		return err
 	}
+	if t.tlsConfig != nil {
+		conn, err = t.handshake(tls.Client(conn, t.tlsConfig), timeout)
+		if err != nil {
+			return err
+		}
+	}
 	p := NewTCPPeer(conn, t.server)
 	go p.handleConn()
 	return nil
@@ -71,6 +80,10 @@ func (t *TCPTransport) Accept() {
 			t.log.Warn("TCP accept error", zap.Error(err))
 			continue
 		}
+		if t.tlsConfig != nil {
+			go t.acceptTLS(conn)
+			continue
+		}
 		p := NewTCPPeer(conn, t.server)
 		go p.handleConn()
 	}
@@ -0,0 +1,152 @@
+package network
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"go.uber.org/zap"
+)
+
+// defaultTLSHandshakeTimeout is used for TLS handshakes when dial timeout is
+// not specified and for incoming connections.
+const defaultTLSHandshakeTimeout = 10 * time.Second
+
+// NewTLSTransport returns a new TCPTransport that will listen for new
+// incoming peer connections, all connections are encrypted and authenticated
+// with TLS using the given configuration (see NewTLSConfig).
+func NewTLSTransport(s *Server, bindAddr string, cfg *tls.Config, log *zap.Logger) *TCPTransport {
+	t := NewTCPTransport(s, bindAddr, log)
+	t.tlsConfig = cfg
+	return t
+}
+
+// NewTLSConfig creates TLS configuration for P2P connections using the
+// self-signed certificate made of the node key. Peers are authenticated by
+// their secp256r1 keys, if allowed list is not empty only peers with keys
+// from it can connect.
+func NewTLSConfig(key *keys.PrivateKey, allowed keys.PublicKeys) (*tls.Config, error) {
+	pub := key.PublicKey()
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: hex.EncodeToString(pub.Bytes())},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(100, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PrivateKey.PublicKey, &key.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	allowed = allowed.Copy()
+	return &tls.Config{
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{cert},
+			PrivateKey:  &key.PrivateKey,
+		}},
+		// Certificates are self-signed, so they're checked by
+		// VerifyPeerCertificate on both sides.
+		ClientAuth:         tls.RequireAnyClientCert,
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS13,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyPeerCertificate(rawCerts, allowed)
+		},
+	}, nil
+}
+
+// newTLSConfigFromCfg creates TLS configuration for P2P connections using
+// the node key from the wallet specified in the configuration.
+func newTLSConfigFromCfg(cfg config.P2PEncryption) (*tls.Config, error) {
+	w, err := wallet.NewWalletFromFile(cfg.UnlockWallet.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer w.Close()
+
+	var key *keys.PrivateKey
+	for _, acc := range w.Accounts {
+		if err := acc.Decrypt(cfg.UnlockWallet.Password, w.Scrypt); err == nil {
+			key = acc.PrivateKey()
+			break
+		}
+	}
+	if key == nil {
+		return nil, errors.New("no wallet account could be unlocked")
+	}
+
+	allowed := make(keys.PublicKeys, len(cfg.AllowedPeers))
+	for i := range cfg.AllowedPeers {
+		allowed[i], err = keys.NewPublicKeyFromString(cfg.AllowedPeers[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed peer key #%d: %w", i, err)
+		}
+	}
+	return NewTLSConfig(key, allowed)
+}
+
+// verifyPeerCertificate checks that the peer certificate contains secp256r1
+// key allowed to connect.
+func verifyPeerCertificate(rawCerts [][]byte, allowed keys.PublicKeys) error {
+	if len(rawCerts) == 0 {
+		return errors.New("no peer certificate")
+	}
+	cert, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return fmt.Errorf("invalid peer certificate: %w", err)
+	}
+	pub, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok || pub.Curve != elliptic.P256() {
+		return errors.New("peer key is not a secp256r1 key")
+	}
+	if len(allowed) != 0 && !allowed.Contains((*keys.PublicKey)(pub)) {
+		return fmt.Errorf("peer %s is not allowed", hex.EncodeToString((*keys.PublicKey)(pub).Bytes()))
+	}
+	return nil
+}
+
+// handshake performs TLS handshake with the given timeout, conn is closed if
+// it fails.
+func (t *TCPTransport) handshake(conn *tls.Conn, timeout time.Duration) (net.Conn, error) {
+	if timeout <= 0 {
+		timeout = defaultTLSHandshakeTimeout
+	}
+	err := conn.SetDeadline(time.Now().Add(timeout))
+	if err == nil {
+		err = conn.Handshake()
+	}
+	if err == nil {
+		err = conn.SetDeadline(time.Time{})
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	return conn, nil
+}
+
+// acceptTLS performs TLS handshake for the incoming connection and starts
+// peer handling if it succeeds.
+func (t *TCPTransport) acceptTLS(conn net.Conn) {
+	addr := conn.RemoteAddr().String()
+	c, err := t.handshake(tls.Server(conn, t.tlsConfig), 0)
+	if err != nil {
+		t.log.Info("incoming connection rejected", zap.String("addr", addr), zap.Error(err))
+		return
+	}
+	p := NewTCPPeer(c, t.server)
+	p.handleConn()
+}
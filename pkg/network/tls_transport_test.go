@@ -0,0 +1,181 @@
+package network
+
+import (
+	"crypto/tls"
+	"encoding/hex"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestTLSConfig(t *testing.T, allowed ...*keys.PublicKey) (*keys.PrivateKey, *tls.Config) {
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	cfg, err := NewTLSConfig(key, allowed)
+	require.NoError(t, err)
+	return key, cfg
+}
+
+// tlsHandshake performs TLS handshake over the loopback connection and
+// returns server and client errors.
+func tlsHandshake(t *testing.T, srvCfg, cliCfg *tls.Config) (*tls.Conn, error, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	cliConn, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	srvConn, err := l.Accept()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		srvConn.Close()
+		cliConn.Close()
+	})
+	require.NoError(t, cliConn.SetDeadline(time.Now().Add(5*time.Second)))
+	require.NoError(t, srvConn.SetDeadline(time.Now().Add(5*time.Second)))
+	srv := tls.Server(srvConn, srvCfg)
+	cli := tls.Client(cliConn, cliCfg)
+	errCh := make(chan error, 1)
+	go func() {
+		err := cli.Handshake()
+		if err == nil {
+			// TLS 1.3 client finishes handshake before the server
+			// verifies its certificate, so wait for the server.
+			_, err = cli.Read(make([]byte, 1))
+		}
+		errCh <- err
+	}()
+	srvErr := srv.Handshake()
+	if srvErr == nil {
+		_, srvErr = srv.Write([]byte{1})
+	} else {
+		srvConn.Close()
+	}
+	return cli, srvErr, <-errCh
+}
+
+func TestTLSConfig(t *testing.T) {
+	t.Run("any peer", func(t *testing.T) {
+		srvKey, srvCfg := newTestTLSConfig(t)
+		_, cliCfg := newTestTLSConfig(t)
+		cli, srvErr, cliErr := tlsHandshake(t, srvCfg, cliCfg)
+		require.NoError(t, srvErr)
+		require.NoError(t, cliErr)
+		st := cli.ConnectionState()
+		require.Equal(t, uint16(tls.VersionTLS13), st.Version)
+		require.Equal(t, hex.EncodeToString(srvKey.PublicKey().Bytes()), st.PeerCertificates[0].Subject.CommonName)
+	})
+	t.Run("allowed", func(t *testing.T) {
+		srvKey, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		cliKey, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		srvCfg, err := NewTLSConfig(srvKey, keys.PublicKeys{cliKey.PublicKey()})
+		require.NoError(t, err)
+		cliCfg, err := NewTLSConfig(cliKey, keys.PublicKeys{srvKey.PublicKey()})
+		require.NoError(t, err)
+		_, srvErr, cliErr := tlsHandshake(t, srvCfg, cliCfg)
+		require.NoError(t, srvErr)
+		require.NoError(t, cliErr)
+	})
+	t.Run("client not allowed", func(t *testing.T) {
+		other, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		_, srvCfg := newTestTLSConfig(t, other.PublicKey())
+		_, cliCfg := newTestTLSConfig(t)
+		_, srvErr, cliErr := tlsHandshake(t, srvCfg, cliCfg)
+		require.Error(t, srvErr)
+		require.Error(t, cliErr)
+	})
+	t.Run("server not allowed", func(t *testing.T) {
+		other, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		_, srvCfg := newTestTLSConfig(t)
+		_, cliCfg := newTestTLSConfig(t, other.PublicKey())
+		_, srvErr, cliErr := tlsHandshake(t, srvCfg, cliCfg)
+		require.Error(t, srvErr)
+		require.Error(t, cliErr)
+	})
+	t.Run("plain client", func(t *testing.T) {
+		_, srvCfg := newTestTLSConfig(t)
+		_, srvErr, cliErr := tlsHandshake(t, srvCfg, &tls.Config{InsecureSkipVerify: true})
+		require.Error(t, srvErr)
+		require.Error(t, cliErr)
+	})
+}
+
+func TestTLSTransportDial(t *testing.T) {
+	other, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	_, srvCfg := newTestTLSConfig(t)
+	_, cliCfg := newTestTLSConfig(t, other.PublicKey())
+
+	s := newTestServer(t, ServerConfig{})
+	srv := NewTLSTransport(s, "127.0.0.1:0", srvCfg, zaptest.NewLogger(t))
+	go srv.Accept()
+	t.Cleanup(srv.Close)
+	require.Eventually(t, func() bool { return srv.Address() != "" }, time.Second, 10*time.Millisecond)
+
+	cli := NewTLSTransport(s, "", cliCfg, zaptest.NewLogger(t))
+	require.Error(t, cli.Dial(srv.Address(), time.Second))
+
+	// Plain TCP peers are disconnected.
+	conn, err := net.Dial("tcp", srv.Address())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write(make([]byte, 64))
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	for err == nil {
+		_, err = conn.Read(make([]byte, 64))
+	}
+	netErr, ok := err.(net.Error)
+	require.False(t, ok && netErr.Timeout(), err)
+}
+
+func TestNewTLSConfigFromCfg(t *testing.T) {
+	cfg := config.P2PEncryption{
+		Enabled: true,
+		UnlockWallet: config.Wallet{
+			Path:     "../consensus/testdata/wallet1.json",
+			Password: "one",
+		},
+	}
+	_, err := newTLSConfigFromCfg(cfg)
+	require.NoError(t, err)
+
+	t.Run("bad password", func(t *testing.T) {
+		cfg := cfg
+		cfg.UnlockWallet.Password = "two"
+		_, err := newTLSConfigFromCfg(cfg)
+		require.Error(t, err)
+	})
+	t.Run("missing wallet", func(t *testing.T) {
+		cfg := cfg
+		cfg.UnlockWallet.Path = "./unknown.json"
+		_, err := newTLSConfigFromCfg(cfg)
+		require.Error(t, err)
+	})
+	t.Run("bad allowed peer", func(t *testing.T) {
+		cfg := cfg
+		cfg.AllowedPeers = []string{"abc"}
+		_, err := newTLSConfigFromCfg(cfg)
+		require.Error(t, err)
+	})
+	t.Run("server", func(t *testing.T) {
+		// No logger, but the transport is fine.
+		_, err := NewServer(ServerConfig{P2PEncryptionCfg: cfg}, nil, nil)
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), "P2P encryption")
+
+		cfg := cfg
+		cfg.UnlockWallet.Password = "two"
+		_, err = NewServer(ServerConfig{P2PEncryptionCfg: cfg}, nil, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "P2P encryption")
+	})
+}
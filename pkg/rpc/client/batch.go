@@ -0,0 +1,128 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/nspcc-dev/neo-go/pkg/rpc/request"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response"
+)
+
+// BatchCall represents a single JSON-RPC call performed as a part of batch
+// request via Client.Batch.
+type BatchCall struct {
+	// Method is the JSON-RPC method name.
+	Method string
+	// Params is a set of call parameters.
+	Params request.RawParams
+	// Result is a pointer to the value the call result is unmarshaled into
+	// (like *result.ApplicationLog for `getapplicationlog`). It's not
+	// touched if the call fails.
+	Result interface{}
+	// Err is the call error (either returned by the server or occurred
+	// during result unmarshaling), it's set by Client.Batch.
+	Err error
+}
+
+// NewBatchCall creates a new BatchCall for the specified method with the
+// specified parameters, v is a pointer to the value call result is
+// unmarshaled into.
+func NewBatchCall(v interface{}, method string, params ...interface{}) *BatchCall {
+	return &BatchCall{
+		Method: method,
+		Params: request.NewRawParams(params...),
+		Result: v,
+	}
+}
+
+// Batch performs the specified calls via JSON-RPC 2.0 batch requests,
+// calls are split into several batches if there are more than
+// request.MaxBatchSize of them. Every call's result is unmarshaled into its
+// Result field, per-call errors are stored in Err fields. The error
+// returned by Batch itself means that the whole batch request has failed
+// (and no results are available).
+func (c *Client) Batch(calls []*BatchCall) error {
+	for start := 0; start < len(calls); start += request.MaxBatchSize {
+		end := start + request.MaxBatchSize
+		if end > len(calls) {
+			end = len(calls)
+		}
+		if err := c.performBatch(calls[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// performBatch performs a single batch request for the specified calls.
+func (c *Client) performBatch(calls []*BatchCall) error {
+	var reqs = make([]*request.Raw, len(calls))
+
+	for i := range calls {
+		reqs[i] = &request.Raw{
+			JSONRPC:   request.JSONRPCVersion,
+			Method:    calls[i].Method,
+			RawParams: calls[i].Params.Values,
+			ID:        i + 1,
+		}
+		if reqs[i].RawParams == nil {
+			reqs[i].RawParams = []interface{}{}
+		}
+	}
+	resps, err := c.requestBatchF(reqs)
+	if err != nil {
+		return err
+	}
+
+	var answered = make([]bool, len(calls))
+	for _, resp := range resps {
+		id, err := strconv.Atoi(string(resp.ID))
+		if err != nil || id < 1 || id > len(calls) || answered[id-1] {
+			return fmt.Errorf("unexpected response ID %s", resp.ID)
+		}
+		answered[id-1] = true
+		call := calls[id-1]
+		switch {
+		case resp.Error != nil:
+			call.Err = resp.Error
+		case resp.Result == nil:
+			call.Err = errors.New("no result returned")
+		default:
+			call.Err = json.Unmarshal(resp.Result, call.Result)
+		}
+	}
+	for i := range answered {
+		if !answered[i] {
+			calls[i].Err = errors.New("no response returned")
+		}
+	}
+	return nil
+}
+
+func (c *Client) makeHTTPBatchRequest(reqs []*request.Raw) ([]response.Raw, error) {
+	var raw json.RawMessage
+
+	if err := c.doHTTPRequest(reqs, &raw); err != nil {
+		return nil, err
+	}
+	// The server answers with a single error response if it fails to
+	// process the batch as a whole.
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) != 0 && trimmed[0] == '{' {
+		var single response.Raw
+		if err := json.Unmarshal(trimmed, &single); err != nil {
+			return nil, fmt.Errorf("JSON decoding: %w", err)
+		}
+		if single.Error != nil {
+			return nil, single.Error
+		}
+		return nil, errors.New("unexpected non-batch response")
+	}
+	var resps []response.Raw
+	if err := json.Unmarshal(raw, &resps); err != nil {
+		return nil, fmt.Errorf("JSON decoding: %w", err)
+	}
+	return resps, nil
+}
@@ -36,6 +36,7 @@ type Client struct {
 	ctx               context.Context
 	opts              Options
 	requestF          func(*request.Raw) (*response.Raw, error)
+	requestBatchF     func([]*request.Raw) ([]response.Raw, error)
 	cache             cache
 }
 
@@ -107,6 +108,7 @@ func New(ctx context.Context, endpoint string, opts Options) (*Client, error) {
 	}
 	cl.opts = opts
 	cl.requestF = cl.makeHTTPRequest
+	cl.requestBatchF = cl.makeHTTPBatchRequest
 	return cl, nil
 }
 
@@ -165,28 +167,36 @@ func (c *Client) performRequest(method string, p request.RawParams, v interface{
 }
 
 func (c *Client) makeHTTPRequest(r *request.Raw) (*response.Raw, error) {
-	var (
-		buf = new(bytes.Buffer)
-		raw = new(response.Raw)
-	)
+	var raw = new(response.Raw)
 
-	if err := json.NewEncoder(buf).Encode(r); err != nil {
+	if err := c.doHTTPRequest(r, raw); err != nil {
 		return nil, err
 	}
+	return raw, nil
+}
+
+// doHTTPRequest sends JSON-encoded payload to the endpoint and decodes the
+// response into v.
+func (c *Client) doHTTPRequest(payload interface{}, v interface{}) error {
+	var buf = new(bytes.Buffer)
+
+	if err := json.NewEncoder(buf).Encode(payload); err != nil {
+		return err
+	}
 
 	req, err := http.NewRequest("POST", c.endpoint.String(), buf)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	resp, err := c.cli.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
 	// The node might send us proper JSON anyway, so look there first and if
 	// it parses, then it has more relevant data than HTTP error code.
-	err = json.NewDecoder(resp.Body).Decode(raw)
+	err = json.NewDecoder(resp.Body).Decode(v)
 	if err != nil {
 		if resp.StatusCode != http.StatusOK {
 			err = fmt.Errorf("HTTP %d/%s", resp.StatusCode, http.StatusText(resp.StatusCode))
@@ -194,10 +204,7 @@ func (c *Client) makeHTTPRequest(r *request.Raw) (*response.Raw, error) {
 			err = fmt.Errorf("JSON decoding: %w", err)
 		}
 	}
-	if err != nil {
-		return nil, err
-	}
-	return raw, nil
+	return err
 }
 
 // Ping attempts to create a connection to the endpoint.
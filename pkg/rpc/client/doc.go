@@ -11,6 +11,10 @@ Some of the methods also allow to pass a verbose bool. This will
 return a more pretty printed response from the server instead of
 a raw hex string.
 
+Multiple calls can be performed via a single JSON-RPC 2.0 batch request
+using Batch method, every BatchCall passed to it gets its own typed result
+and error.
+
 TODO:
 	Allow client to connect using client cert.
 	More in-depth examples.
@@ -54,6 +58,7 @@ Supported methods
 Extensions:
 
 	getblocksysfee
+	getnotifications
 	submitnotaryrequest
 
 Unsupported methods
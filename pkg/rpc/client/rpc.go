@@ -3,10 +3,12 @@ package client
 import (
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/consensus/monitor"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/fee"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
@@ -24,6 +26,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
 )
 
@@ -230,6 +233,52 @@ func (c *Client) GetCommittee() (keys.PublicKeys, error) {
 	return *resp, nil
 }
 
+// GetConsensusHistory returns consensus messages traced by the node for the
+// last count heights (all heights kept by the node if count is 0) along with
+// per-validator statistics. It's a NeoGo extension available only if
+// consensus monitoring is enabled on the node.
+func (c *Client) GetConsensusHistory(count int) (*monitor.History, error) {
+	var (
+		params = request.NewRawParams()
+		resp   = new(monitor.History)
+	)
+	if count != 0 {
+		params.Values = append(params.Values, count)
+	}
+	if err := c.performRequest("getconsensushistory", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetConsensusRounds returns consensus rounds tracked by the node (finished
+// ones and the current one). It's a NeoGo extension available only if
+// consensus monitoring is enabled on the node.
+func (c *Client) GetConsensusRounds() ([]monitor.Round, error) {
+	var (
+		params = request.NewRawParams()
+		resp   = new([]monitor.Round)
+	)
+	if err := c.performRequest("getconsensusrounds", params, resp); err != nil {
+		return nil, err
+	}
+	return *resp, nil
+}
+
+// GetConsensusStatus returns the current consensus round along with messages
+// received for its height and current validators statistics. It's a NeoGo
+// extension available only if consensus monitoring is enabled on the node.
+func (c *Client) GetConsensusStatus() (*monitor.Status, error) {
+	var (
+		params = request.NewRawParams()
+		resp   = new(monitor.Status)
+	)
+	if err := c.performRequest("getconsensusstatus", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetContractStateByHash queries contract information, according to the contract script hash.
 func (c *Client) GetContractStateByHash(hash util.Uint160) (*state.Contract, error) {
 	return c.getContractState(hash.StringLE())
@@ -483,6 +532,50 @@ func (c *Client) GetStateHeight() (*result.StateHeight, error) {
 	return resp, nil
 }
 
+// GetStateRootByHeight returns state root for the specified height.
+func (c *Client) GetStateRootByHeight(height uint32) (*state.MPTRoot, error) {
+	return c.getStateRoot(request.NewRawParams(height))
+}
+
+// GetStateRootByBlockHash returns state root for the block with the specified hash.
+func (c *Client) GetStateRootByBlockHash(hash util.Uint256) (*state.MPTRoot, error) {
+	return c.getStateRoot(request.NewRawParams(hash.StringLE()))
+}
+
+func (c *Client) getStateRoot(params request.RawParams) (*state.MPTRoot, error) {
+	var resp = new(state.MPTRoot)
+	if err := c.performRequest("getstateroot", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetProof returns existence proof of storage item state by the given stateroot,
+// historical contract hash and historical item key.
+func (c *Client) GetProof(stateroot util.Uint256, historicalContractHash util.Uint160, historicalKey []byte) (*result.ProofWithKey, error) {
+	var (
+		params = request.NewRawParams(stateroot.StringLE(), historicalContractHash.StringLE(), historicalKey)
+		resp   = new(result.ProofWithKey)
+	)
+	if err := c.performRequest("getproof", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// VerifyProof returns value by the given stateroot and proof. Note that the
+// proof is verified by the RPC node, use mpt.VerifyProof to check it locally.
+func (c *Client) VerifyProof(stateroot util.Uint256, proof *result.ProofWithKey) ([]byte, error) {
+	var (
+		params = request.NewRawParams(stateroot.StringLE(), proof.String())
+		resp   = new(result.VerifyProof)
+	)
+	if err := c.performRequest("verifyproof", params, resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
 // GetStorageByID returns the stored value, according to the contract ID and the stored key.
 func (c *Client) GetStorageByID(id int32, key []byte) ([]byte, error) {
 	return c.getStorage(request.NewRawParams(id, key))
@@ -537,6 +630,27 @@ func (c *Client) GetNextBlockValidators() ([]result.Validator, error) {
 	return *resp, nil
 }
 
+// GetNotifications returns notifications emitted by the specified contract in
+// the blocks from start to end (both inclusive), only notifications with the
+// specified name are returned if it's not empty. Notifications of a single
+// block are never split, so to get the next portion of notifications start
+// from the block following the last returned one. Server-side limit is
+// used if limit is not positive. This call is a NeoGo extension that requires
+// notification index to be enabled on the server.
+func (c *Client) GetNotifications(contract util.Uint160, name string, start, end uint32, limit int) ([]state.IndexedNotificationEvent, error) {
+	var (
+		params = request.NewRawParams(contract.StringLE(), start, end, name)
+		resp   = new([]state.IndexedNotificationEvent)
+	)
+	if limit > 0 {
+		params.Values = append(params.Values, limit)
+	}
+	if err := c.performRequest("getnotifications", params, resp); err != nil {
+		return nil, err
+	}
+	return *resp, nil
+}
+
 // GetVersion returns the version information about the queried node.
 func (c *Client) GetVersion() (*result.Version, error) {
 	var (
@@ -556,6 +670,23 @@ func (c *Client) InvokeScript(script []byte, signers []transaction.Signer) (*res
 	return c.invokeSomething("invokescript", p, signers)
 }
 
+// InvokeScriptAtHeight returns the result of the given script after running it
+// true the VM using the state at the specified height.
+// NOTE: This is a test invoke and will not affect the blockchain.
+func (c *Client) InvokeScriptAtHeight(height uint32, script []byte, signers []transaction.Signer) (*result.Invoke, error) {
+	var p = request.NewRawParams(height, script)
+	return c.invokeSomething("invokescripthistoric", p, signers)
+}
+
+// InvokeScriptWithState returns the result of the given script after running it
+// true the VM using the state with the specified state root (or the state after
+// the block with the specified hash).
+// NOTE: This is a test invoke and will not affect the blockchain.
+func (c *Client) InvokeScriptWithState(stateOrBlock util.Uint256, script []byte, signers []transaction.Signer) (*result.Invoke, error) {
+	var p = request.NewRawParams(stateOrBlock.StringLE(), script)
+	return c.invokeSomething("invokescripthistoric", p, signers)
+}
+
 // InvokeFunction returns the results after calling the smart contract scripthash
 // with the given operation and parameters.
 // NOTE: this is test invoke and will not affect the blockchain.
@@ -564,6 +695,23 @@ func (c *Client) InvokeFunction(contract util.Uint160, operation string, params
 	return c.invokeSomething("invokefunction", p, signers)
 }
 
+// InvokeFunctionAtHeight returns the results after calling the smart contract
+// scripthash with the given operation and parameters at the given height.
+// NOTE: this is test invoke and will not affect the blockchain.
+func (c *Client) InvokeFunctionAtHeight(height uint32, contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
+	var p = request.NewRawParams(height, contract.StringLE(), operation, params)
+	return c.invokeSomething("invokefunctionhistoric", p, signers)
+}
+
+// InvokeFunctionWithState returns the results after calling the smart contract
+// scripthash with the given operation and parameters using the state with the
+// specified state root (or the state after the block with the specified hash).
+// NOTE: this is test invoke and will not affect the blockchain.
+func (c *Client) InvokeFunctionWithState(stateOrBlock util.Uint256, contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
+	var p = request.NewRawParams(stateOrBlock.StringLE(), contract.StringLE(), operation, params)
+	return c.invokeSomething("invokefunctionhistoric", p, signers)
+}
+
 // InvokeContractVerify returns the results after calling `verify` method of the smart contract
 // with the given parameters under verification trigger type.
 // NOTE: this is test invoke and will not affect the blockchain.
@@ -572,6 +720,25 @@ func (c *Client) InvokeContractVerify(contract util.Uint160, params []smartcontr
 	return c.invokeSomething("invokecontractverify", p, signers, witnesses...)
 }
 
+// InvokeContractVerifyAtHeight returns the results after calling `verify` method
+// of the smart contract with the given parameters under verification trigger type
+// at the specified height.
+// NOTE: this is test invoke and will not affect the blockchain.
+func (c *Client) InvokeContractVerifyAtHeight(height uint32, contract util.Uint160, params []smartcontract.Parameter, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
+	var p = request.NewRawParams(height, contract.StringLE(), params)
+	return c.invokeSomething("invokecontractverifyhistoric", p, signers, witnesses...)
+}
+
+// InvokeContractVerifyWithState returns the results after calling `verify` method
+// of the smart contract with the given parameters under verification trigger type
+// using the state with the specified state root (or the state after the block
+// with the specified hash).
+// NOTE: this is test invoke and will not affect the blockchain.
+func (c *Client) InvokeContractVerifyWithState(stateOrBlock util.Uint256, contract util.Uint160, params []smartcontract.Parameter, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
+	var p = request.NewRawParams(stateOrBlock.StringLE(), contract.StringLE(), params)
+	return c.invokeSomething("invokecontractverifyhistoric", p, signers, witnesses...)
+}
+
 // invokeSomething is an inner wrapper for Invoke* functions.
 func (c *Client) invokeSomething(method string, p request.RawParams, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
 	var resp = new(result.Invoke)
@@ -865,6 +1032,40 @@ func (c *Client) SubmitP2PNotaryRequest(req *payload.P2PNotaryRequest) (util.Uin
 	return resp.Hash, nil
 }
 
+// TerminateSession tries to terminate the specified session and returns `true` iff
+// the specified session was found on server.
+func (c *Client) TerminateSession(sessionID string) (bool, error) {
+	var resp bool
+	params := request.NewRawParams(sessionID)
+	if err := c.performRequest("terminatesession", params, &resp); err != nil {
+		return false, err
+	}
+	return resp, nil
+}
+
+// TraverseIterator returns a set of iterator values (maxItemsCount at max) for
+// the specified iterator and session. If result contains no elements, then either
+// Iterator has no elements or session was expired and terminated by the server.
+// Each subsequent call returns the next portion of iterator values.
+func (c *Client) TraverseIterator(sessionID, iteratorID string, maxItemsCount int) ([]stackitem.Item, error) {
+	var (
+		params = request.NewRawParams(sessionID, iteratorID, maxItemsCount)
+		resp   []json.RawMessage
+	)
+	if err := c.performRequest("traverseiterator", params, &resp); err != nil {
+		return nil, err
+	}
+	items := make([]stackitem.Item, len(resp))
+	for i, iBytes := range resp {
+		itm, err := stackitem.FromJSONWithTypes(iBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %d-th iterator value: %w", i, err)
+		}
+		items[i] = itm
+	}
+	return items, nil
+}
+
 // ValidateAddress verifies that the address is a correct NEO address.
 func (c *Client) ValidateAddress(address string) error {
 	var (
@@ -17,6 +17,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/nspcc-dev/neo-go/internal/testserdes"
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/consensus/monitor"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
@@ -315,6 +316,98 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"getconsensushistory": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (interface{}, error) {
+				return c.GetConsensusHistory(1)
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"heights":[{"height":10,"start":1634567890000,"messages":[{"type":"PrepareRequest","view":0,"validator":2,"time":1634567890123},{"type":"ChangeView","view":1,"validator":0,"time":1634567893123,"reason":"Timeout"}],"truncated":false}],"validators":[{"publickey":"02103a7f7dd016558597f7960d27c516a4394fd968b9e65155eb4b013e4040406e","acceptedblocks":10,"missedcommits":2,"changeviews":1}]}}`,
+			result: func(c *Client) interface{} {
+				member, err := keys.NewPublicKeyFromString("02103a7f7dd016558597f7960d27c516a4394fd968b9e65155eb4b013e4040406e")
+				if err != nil {
+					panic(fmt.Errorf("failed to decode public key: %w", err))
+				}
+				return &monitor.History{
+					Heights: []monitor.HeightTrace{{
+						Height: 10,
+						Start:  1634567890000,
+						Messages: []monitor.Message{
+							{Type: monitor.PrepareRequest, Validator: 2, Time: 1634567890123},
+							{Type: monitor.ChangeView, View: 1, Time: 1634567893123, Reason: "Timeout"},
+						},
+					}},
+					Validators: []monitor.ValidatorStats{{
+						PublicKey:      member,
+						AcceptedBlocks: 10,
+						MissedCommits:  2,
+						ChangeViews:    1,
+					}},
+				}
+			},
+		},
+	},
+	"getconsensusrounds": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (interface{}, error) {
+				return c.GetConsensusRounds()
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":[{"height":10,"view":0,"primary":2,"start":1634567890123,"duration":0,"finished":false,"blockaccepted":false,"validators":[{"index":0,"publickey":"02103a7f7dd016558597f7960d27c516a4394fd968b9e65155eb4b013e4040406e","prepareresponse":150,"changeview":3000,"changeviewreason":"Timeout"}]}]}`,
+			result: func(c *Client) interface{} {
+				member, err := keys.NewPublicKeyFromString("02103a7f7dd016558597f7960d27c516a4394fd968b9e65155eb4b013e4040406e")
+				if err != nil {
+					panic(fmt.Errorf("failed to decode public key: %w", err))
+				}
+				resp, cv := uint64(150), uint64(3000)
+				return []monitor.Round{{
+					Height:  10,
+					Primary: 2,
+					Start:   1634567890123,
+					Validators: []monitor.Validator{{
+						PublicKey:        member,
+						PrepareResponse:  &resp,
+						ChangeView:       &cv,
+						ChangeViewReason: "Timeout",
+					}},
+				}}
+			},
+		},
+	},
+	"getconsensusstatus": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (interface{}, error) {
+				return c.GetConsensusStatus()
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"round":{"height":10,"view":0,"primary":2,"start":1634567890123,"duration":0,"finished":false,"blockaccepted":false,"validators":[{"index":0,"publickey":"02103a7f7dd016558597f7960d27c516a4394fd968b9e65155eb4b013e4040406e","commit":150}]},"messages":[{"type":"Commit","view":0,"validator":0,"time":1634567890273}],"validators":[{"publickey":"02103a7f7dd016558597f7960d27c516a4394fd968b9e65155eb4b013e4040406e","acceptedblocks":10,"missedcommits":0,"changeviews":0}]}}`,
+			result: func(c *Client) interface{} {
+				member, err := keys.NewPublicKeyFromString("02103a7f7dd016558597f7960d27c516a4394fd968b9e65155eb4b013e4040406e")
+				if err != nil {
+					panic(fmt.Errorf("failed to decode public key: %w", err))
+				}
+				commit := uint64(150)
+				return &monitor.Status{
+					Round: &monitor.Round{
+						Height:  10,
+						Primary: 2,
+						Start:   1634567890123,
+						Validators: []monitor.Validator{{
+							PublicKey: member,
+							Commit:    &commit,
+						}},
+					},
+					Messages: []monitor.Message{
+						{Type: monitor.Commit, Time: 1634567890273},
+					},
+					Validators: []monitor.ValidatorStats{{
+						PublicKey:      member,
+						AcceptedBlocks: 10,
+					}},
+				}
+			},
+		},
+	},
 	"getconnectioncount": {
 		{
 			name: "positive",
@@ -821,6 +914,75 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"getstateroot": {
+		{
+			name: "by height, positive",
+			invoke: func(c *Client) (interface{}, error) {
+				return c.GetStateRootByHeight(5)
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"version":0,"index":5,"roothash":"0x252e9d73d49c95c7618d40650da504e05183a1b2eed0685e42c360413c329170","witnesses":[{"invocation":"AQI=","verification":"AwQ="}]}}`,
+			result: func(c *Client) interface{} {
+				root, _ := util.Uint256DecodeStringLE("252e9d73d49c95c7618d40650da504e05183a1b2eed0685e42c360413c329170")
+				return &state.MPTRoot{
+					Index: 5,
+					Root:  root,
+					Witness: []transaction.Witness{{
+						InvocationScript:   []byte{1, 2},
+						VerificationScript: []byte{3, 4},
+					}},
+				}
+			},
+		},
+		{
+			name: "by block hash, positive",
+			invoke: func(c *Client) (interface{}, error) {
+				hash, _ := util.Uint256DecodeStringLE("e93d17a52967f9e69314385482bf86f85260e811b46bf4d4b261a7f4135a623c")
+				return c.GetStateRootByBlockHash(hash)
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"version":0,"index":5,"roothash":"0x252e9d73d49c95c7618d40650da504e05183a1b2eed0685e42c360413c329170","witnesses":[]}}`,
+			result: func(c *Client) interface{} {
+				root, _ := util.Uint256DecodeStringLE("252e9d73d49c95c7618d40650da504e05183a1b2eed0685e42c360413c329170")
+				return &state.MPTRoot{
+					Index:   5,
+					Root:    root,
+					Witness: []transaction.Witness{},
+				}
+			},
+		},
+	},
+	"getproof": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (interface{}, error) {
+				root, _ := util.Uint256DecodeStringLE("252e9d73d49c95c7618d40650da504e05183a1b2eed0685e42c360413c329170")
+				cHash, _ := util.Uint160DecodeStringLE("5c9e40a12055c6b9e3f72271c9779958c842135d")
+				return c.GetProof(root, cHash, []byte("ab"))
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":"AmFiAQN4eXo="}`,
+			result: func(c *Client) interface{} {
+				return &result.ProofWithKey{
+					Key:   []byte("ab"),
+					Proof: [][]byte{[]byte("xyz")},
+				}
+			},
+		},
+	},
+	"verifyproof": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (interface{}, error) {
+				root, _ := util.Uint256DecodeStringLE("252e9d73d49c95c7618d40650da504e05183a1b2eed0685e42c360413c329170")
+				return c.VerifyProof(root, &result.ProofWithKey{
+					Key:   []byte("ab"),
+					Proof: [][]byte{[]byte("xyz")},
+				})
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":"dGVzdHZhbHVl"}`,
+			result: func(c *Client) interface{} {
+				return []byte("testvalue")
+			},
+		},
+	},
 	"getstorage": {
 		{
 			name: "by hash, positive",
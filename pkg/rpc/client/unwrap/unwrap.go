@@ -0,0 +1,159 @@
+/*
+Package unwrap provides a set of functions converting results of test
+invocations (as returned by invoke* RPC calls) into regular Go values. Every
+function accepts the invocation result along with an error, so that RPC call
+results can be passed to them directly, checks the VM state and the resulting
+stack and converts the only stack item returned into the requested type.
+*/
+package unwrap
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+	"unicode/utf8"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// Nothing checks the invocation result for errors ignoring any resulting
+// items, it's used for methods that don't return anything.
+func Nothing(r *result.Invoke, err error) error {
+	if err != nil {
+		return err
+	}
+	if r.State != "HALT" {
+		return fmt.Errorf("invocation failed: %s", r.FaultException)
+	}
+	return nil
+}
+
+// Item returns the only stack item from the invocation result.
+func Item(r *result.Invoke, err error) (stackitem.Item, error) {
+	if err := Nothing(r, err); err != nil {
+		return nil, err
+	}
+	if len(r.Stack) == 0 {
+		return nil, errors.New("result stack is empty")
+	}
+	if len(r.Stack) > 1 {
+		return nil, fmt.Errorf("too many result items: %d", len(r.Stack))
+	}
+	return r.Stack[0], nil
+}
+
+// Bool returns the boolean value from the invocation result.
+func Bool(r *result.Invoke, err error) (bool, error) {
+	itm, err := Item(r, err)
+	if err != nil {
+		return false, err
+	}
+	return itm.TryBool()
+}
+
+// BigInt returns the integer value from the invocation result.
+func BigInt(r *result.Invoke, err error) (*big.Int, error) {
+	itm, err := Item(r, err)
+	if err != nil {
+		return nil, err
+	}
+	return itm.TryInteger()
+}
+
+// Int64 returns the integer value from the invocation result, it fails if
+// the value doesn't fit into int64.
+func Int64(r *result.Invoke, err error) (int64, error) {
+	i, err := BigInt(r, err)
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsInt64() {
+		return 0, errors.New("int64 overflow")
+	}
+	return i.Int64(), nil
+}
+
+// Bytes returns the byte slice value from the invocation result.
+func Bytes(r *result.Invoke, err error) ([]byte, error) {
+	itm, err := Item(r, err)
+	if err != nil {
+		return nil, err
+	}
+	return bytesOrNil(itm)
+}
+
+// String returns the UTF-8 string value from the invocation result.
+func String(r *result.Invoke, err error) (string, error) {
+	b, err := Bytes(r, err)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", errors.New("not a UTF-8 string")
+	}
+	return string(b), nil
+}
+
+// Uint160 returns the util.Uint160 value from the invocation result.
+func Uint160(r *result.Invoke, err error) (util.Uint160, error) {
+	b, err := Bytes(r, err)
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	return util.Uint160DecodeBytesBE(b)
+}
+
+// Uint256 returns the util.Uint256 value from the invocation result.
+func Uint256(r *result.Invoke, err error) (util.Uint256, error) {
+	b, err := Bytes(r, err)
+	if err != nil {
+		return util.Uint256{}, err
+	}
+	return util.Uint256DecodeBytesBE(b)
+}
+
+// PublicKey returns the public key from the invocation result.
+func PublicKey(r *result.Invoke, err error) (*keys.PublicKey, error) {
+	b, err := Bytes(r, err)
+	if err != nil {
+		return nil, err
+	}
+	return keys.NewPublicKeyFromBytes(b, elliptic.P256())
+}
+
+// Array returns the elements of the Array or Struct from the invocation
+// result.
+func Array(r *result.Invoke, err error) ([]stackitem.Item, error) {
+	itm, err := Item(r, err)
+	if err != nil {
+		return nil, err
+	}
+	if t := itm.Type(); t != stackitem.ArrayT && t != stackitem.StructT {
+		return nil, fmt.Errorf("invalid stack item type: %s", t)
+	}
+	return itm.Value().([]stackitem.Item), nil
+}
+
+// Map returns the Map from the invocation result.
+func Map(r *result.Invoke, err error) (*stackitem.Map, error) {
+	itm, err := Item(r, err)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := itm.(*stackitem.Map)
+	if !ok {
+		return nil, fmt.Errorf("invalid stack item type: %s", itm.Type())
+	}
+	return m, nil
+}
+
+func bytesOrNil(itm stackitem.Item) ([]byte, error) {
+	if itm.Type() == stackitem.AnyT {
+		return nil, nil
+	}
+	return itm.TryBytes()
+}
@@ -0,0 +1,97 @@
+package unwrap
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+func halt(items ...stackitem.Item) *result.Invoke {
+	return &result.Invoke{State: "HALT", Stack: items}
+}
+
+func TestStdErrors(t *testing.T) {
+	_, err := Item(nil, errors.New("some"))
+	require.Error(t, err)
+
+	_, err = Item(&result.Invoke{State: "FAULT", FaultException: "bad"}, nil)
+	require.Error(t, err)
+
+	_, err = Item(halt(), nil)
+	require.Error(t, err)
+
+	_, err = Item(halt(stackitem.Make(1), stackitem.Make(2)), nil)
+	require.Error(t, err)
+
+	require.NoError(t, Nothing(halt(), nil))
+	require.Error(t, Nothing(&result.Invoke{State: "FAULT"}, nil))
+}
+
+func TestValues(t *testing.T) {
+	b, err := Bool(halt(stackitem.Make(true)), nil)
+	require.NoError(t, err)
+	require.True(t, b)
+
+	i, err := BigInt(halt(stackitem.Make(42)), nil)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(42), i)
+
+	i64, err := Int64(halt(stackitem.Make(-7)), nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(-7), i64)
+
+	huge := new(big.Int).Lsh(big.NewInt(1), 100)
+	_, err = Int64(halt(stackitem.NewBigInteger(huge)), nil)
+	require.Error(t, err)
+
+	bs, err := Bytes(halt(stackitem.Make([]byte{1, 2, 3})), nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, bs)
+
+	bs, err = Bytes(halt(stackitem.Null{}), nil)
+	require.NoError(t, err)
+	require.Nil(t, bs)
+
+	s, err := String(halt(stackitem.Make("neo")), nil)
+	require.NoError(t, err)
+	require.Equal(t, "neo", s)
+
+	_, err = String(halt(stackitem.Make([]byte{0xff})), nil)
+	require.Error(t, err)
+
+	u160 := util.Uint160{1, 2, 3}
+	h160, err := Uint160(halt(stackitem.Make(u160.BytesBE())), nil)
+	require.NoError(t, err)
+	require.Equal(t, u160, h160)
+
+	u256 := util.Uint256{1, 2, 3}
+	h256, err := Uint256(halt(stackitem.Make(u256.BytesBE())), nil)
+	require.NoError(t, err)
+	require.Equal(t, u256, h256)
+
+	priv, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	pub, err := PublicKey(halt(stackitem.Make(priv.PublicKey().Bytes())), nil)
+	require.NoError(t, err)
+	require.Equal(t, priv.PublicKey(), pub)
+
+	arr, err := Array(halt(stackitem.Make([]stackitem.Item{stackitem.Make(1)})), nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(arr))
+
+	_, err = Array(halt(stackitem.Make(1)), nil)
+	require.Error(t, err)
+
+	m, err := Map(halt(stackitem.NewMap()), nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, m.Len())
+
+	_, err = Map(halt(stackitem.Make(1)), nil)
+	require.Error(t, err)
+}
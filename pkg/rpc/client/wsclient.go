@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/nspcc-dev/neo-go/pkg/consensus/monitor"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
@@ -99,6 +100,7 @@ func NewWS(ctx context.Context, endpoint string, opts Options) (*WSClient, error
 	go wsc.wsReader()
 	go wsc.wsWriter()
 	wsc.requestF = wsc.makeWsRequest
+	wsc.requestBatchF = wsc.makeWsBatchRequest
 	return wsc, nil
 }
 
@@ -150,6 +152,8 @@ readloop:
 				val = new(state.AppExecResult)
 			case response.NotaryRequestEventID:
 				val = new(subscriptions.NotaryRequestEvent)
+			case response.ConsensusRoundEventID:
+				val = new(monitor.Round)
 			case response.MissedEventID:
 				// No value.
 			default:
@@ -226,6 +230,12 @@ func (c *WSClient) makeWsRequest(r *request.Raw) (*response.Raw, error) {
 	}
 }
 
+// makeWsBatchRequest always returns an error, batch requests are not
+// supported by WSClient.
+func (c *WSClient) makeWsBatchRequest([]*request.Raw) ([]response.Raw, error) {
+	return nil, errors.New("batch requests are not supported over websocket")
+}
+
 func (c *WSClient) performSubscription(params request.RawParams) (string, error) {
 	var resp string
 
@@ -313,6 +323,14 @@ func (c *WSClient) SubscribeForNotaryRequests(sender *util.Uint160, mainSigner *
 	return c.performSubscription(params)
 }
 
+// SubscribeForConsensusRounds adds subscription for finished consensus rounds
+// events to this instance of client. Consensus monitoring must be enabled on
+// the server.
+func (c *WSClient) SubscribeForConsensusRounds() (string, error) {
+	params := request.NewRawParams("consensus_round")
+	return c.performSubscription(params)
+}
+
 // Unsubscribe removes subscription for given event stream.
 func (c *WSClient) Unsubscribe(id string) error {
 	return c.performUnsubscription(id)
@@ -12,8 +12,8 @@ const (
 	// JSONRPCVersion is the only JSON-RPC protocol version supported.
 	JSONRPCVersion = "2.0"
 
-	// maxBatchSize is the maximum number of request per batch.
-	maxBatchSize = 100
+	// MaxBatchSize is the maximum number of requests per batch.
+	MaxBatchSize = 100
 )
 
 // RawParams is just a slice of abstract values, used to represent parameters
@@ -91,8 +91,8 @@ func (r *Request) UnmarshalJSON(data []byte) error {
 	}
 	count := 0
 	for decoder.More() {
-		if count > maxBatchSize {
-			return fmt.Errorf("the number of requests in batch shouldn't exceed %d", maxBatchSize)
+		if count > MaxBatchSize {
+			return fmt.Errorf("the number of requests in batch shouldn't exceed %d", MaxBatchSize)
 		}
 		in = &In{}
 		decodeErr := decoder.Decode(in)
@@ -22,6 +22,8 @@ const (
 	ExecutionEventID
 	// NotaryRequestEventID is used for `notary_request_event` event.
 	NotaryRequestEventID
+	// ConsensusRoundEventID is used for `consensus_round` event.
+	ConsensusRoundEventID
 	// MissedEventID notifies user of missed events.
 	MissedEventID EventID = 255
 )
@@ -39,6 +41,8 @@ func (e EventID) String() string {
 		return "transaction_executed"
 	case NotaryRequestEventID:
 		return "notary_request_event"
+	case ConsensusRoundEventID:
+		return "consensus_round"
 	case MissedEventID:
 		return "event_missed"
 	default:
@@ -59,6 +63,8 @@ func GetEventIDFromString(s string) (EventID, error) {
 		return ExecutionEventID, nil
 	case "notary_request_event":
 		return NotaryRequestEventID, nil
+	case "consensus_round":
+		return ConsensusRoundEventID, nil
 	case "event_missed":
 		return MissedEventID, nil
 	default:
@@ -19,6 +19,7 @@ type Invoke struct {
 	Stack                  []stackitem.Item
 	FaultException         string
 	Transaction            *transaction.Transaction
+	Session                string
 	maxIteratorResultItems int
 	finalize               func()
 }
@@ -43,16 +44,35 @@ type invokeAux struct {
 	Stack          json.RawMessage `json:"stack"`
 	FaultException string          `json:"exception,omitempty"`
 	Transaction    []byte          `json:"tx,omitempty"`
+	Session        string          `json:"session,omitempty"`
 }
 
+// iteratorInterfaceName is a string used to mark Iterator inside the InteropInterface.
+const iteratorInterfaceName = "IIterator"
+
 type iteratorAux struct {
 	Type      string            `json:"type"`
+	Interface string            `json:"interface,omitempty"`
+	ID        string            `json:"id,omitempty"`
 	Value     []json.RawMessage `json:"iterator"`
 	Truncated bool              `json:"truncated"`
 }
 
-// Iterator represents deserialized VM iterator values with truncated flag.
+type iteratorInterfaceAux struct {
+	Type      string `json:"type"`
+	Interface string `json:"interface"`
+	ID        string `json:"id"`
+}
+
+// Iterator represents VM iterator identifier. It either has ID set (for those
+// JSON-RPC servers that support sessions) or non-nil Values and Truncated set
+// (for those JSON-RPC servers that don't support sessions but perform
+// in-place iterator traversing).
 type Iterator struct {
+	// ID is iterator identifier inside the session, it can be used to
+	// traverse the iterator via `traverseiterator` call.
+	ID string
+	// Values contains deserialized VM iterator values with truncated flag.
 	Values    []stackitem.Item
 	Truncated bool
 }
@@ -75,8 +95,17 @@ func (r Invoke) MarshalJSON() ([]byte, error) {
 			data []byte
 			err  error
 		)
-		if (r.Stack[i].Type() == stackitem.InteropT) && iterator.IsIterator(r.Stack[i]) {
-			iteratorValues, truncated := iterator.Values(r.Stack[i], r.maxIteratorResultItems)
+		if it, ok := r.Stack[i].Value().(Iterator); ok && r.Stack[i].Type() == stackitem.InteropT && it.ID != "" {
+			data, err = json.Marshal(iteratorInterfaceAux{
+				Type:      stackitem.InteropT.String(),
+				Interface: iteratorInterfaceName,
+				ID:        it.ID,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal iterator: %w", err)
+			}
+		} else if (r.Stack[i].Type() == stackitem.InteropT) && iterator.IsIterator(r.Stack[i]) {
+			iteratorValues, truncated := iterator.ValuesTruncated(r.Stack[i], r.maxIteratorResultItems)
 			value := make([]json.RawMessage, len(iteratorValues))
 			for j := range iteratorValues {
 				value[j], err = stackitem.ToJSONWithTypes(iteratorValues[j])
@@ -121,6 +150,7 @@ func (r Invoke) MarshalJSON() ([]byte, error) {
 		Stack:          st,
 		FaultException: r.FaultException,
 		Transaction:    txbytes,
+		Session:        r.Session,
 	})
 }
 
@@ -142,6 +172,12 @@ func (r *Invoke) UnmarshalJSON(data []byte) error {
 			if st[i].Type() == stackitem.InteropT {
 				iteratorAux := new(iteratorAux)
 				if json.Unmarshal(arr[i], iteratorAux) == nil {
+					if iteratorAux.Interface == iteratorInterfaceName && iteratorAux.ID != "" {
+						st[i] = stackitem.NewInterop(Iterator{
+							ID: iteratorAux.ID,
+						})
+						continue
+					}
 					iteratorValues := make([]stackitem.Item, len(iteratorAux.Value))
 					for j := range iteratorValues {
 						iteratorValues[j], err = stackitem.FromJSONWithTypes(iteratorAux.Value[j])
@@ -176,5 +212,6 @@ func (r *Invoke) UnmarshalJSON(data []byte) error {
 	r.State = aux.State
 	r.FaultException = aux.FaultException
 	r.Transaction = tx
+	r.Session = aux.Session
 	return nil
 }
@@ -45,3 +45,34 @@ func TestInvoke_MarshalJSON(t *testing.T) {
 	require.NoError(t, json.Unmarshal(data, actual))
 	require.Equal(t, result, actual)
 }
+
+func TestInvoke_MarshalJSONSession(t *testing.T) {
+	result := &Invoke{
+		State:       "HALT",
+		GasConsumed: 237626000,
+		Script:      []byte{10},
+		Stack: []stackitem.Item{
+			stackitem.NewBigInteger(big.NewInt(1)),
+			stackitem.NewInterop(Iterator{ID: "6ab79ba3-12d5-4b8d-a0e1-5e4ff6ba6a09"}),
+		},
+		Session: "e1a4d6e7-1db0-4d8a-9b5b-12c8f0b3c1a2",
+	}
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	expected := `{
+		"state":"HALT",
+		"gasconsumed":"237626000",
+		"script":"` + base64.StdEncoding.EncodeToString(result.Script) + `",
+		"stack":[
+			{"type":"Integer","value":"1"},
+			{"type":"Interop","interface":"IIterator","id":"6ab79ba3-12d5-4b8d-a0e1-5e4ff6ba6a09"}
+		],
+		"session":"e1a4d6e7-1db0-4d8a-9b5b-12c8f0b3c1a2"
+}`
+	require.JSONEq(t, expected, string(data))
+
+	actual := new(Invoke)
+	require.NoError(t, json.Unmarshal(data, actual))
+	require.Equal(t, result, actual)
+}
@@ -10,6 +10,10 @@ type (
 		Address              string `yaml:"Address"`
 		Enabled              bool   `yaml:"Enabled"`
 		EnableCORSWorkaround bool   `yaml:"EnableCORSWorkaround"`
+		// MaxBatchWorkers is the maximum number of batch request entries
+		// processed concurrently, entries are processed sequentially if
+		// it's not greater than 1.
+		MaxBatchWorkers int `yaml:"MaxBatchWorkers"`
 		// MaxGasInvoke is a maximum amount of gas which
 		// can be spent during RPC call.
 		MaxGasInvoke           fixedn.Fixed8 `yaml:"MaxGasInvoke"`
@@ -17,7 +21,16 @@ type (
 		MaxFindResultItems     int           `yaml:"MaxFindResultItems"`
 		MaxNEP11Tokens         int           `yaml:"MaxNEP11Tokens"`
 		Port                   uint16        `yaml:"Port"`
-		TLSConfig              TLSConfig     `yaml:"TLSConfig"`
+		// SessionEnabled enables iterator sessions, so that iterators
+		// returned from invoke* calls can be traversed later via
+		// traverseiterator call instead of being expanded in-place.
+		SessionEnabled bool `yaml:"SessionEnabled"`
+		// SessionExpirationTime is a session lifetime in seconds, it's
+		// prolonged by every traverseiterator call.
+		SessionExpirationTime int `yaml:"SessionExpirationTime"`
+		// SessionPoolSize is the maximum number of concurrent sessions.
+		SessionPoolSize int       `yaml:"SessionPoolSize"`
+		TLSConfig       TLSConfig `yaml:"TLSConfig"`
 	}
 
 	// TLSConfig describes SSL/TLS configuration.
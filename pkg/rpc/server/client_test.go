@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/base64"
 	"testing"
+	"time"
 
 	nns "github.com/nspcc-dev/neo-go/examples/nft-nd-nns"
 	"github.com/nspcc-dev/neo-go/internal/testchain"
+	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/fee"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
@@ -15,6 +17,9 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/request"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
@@ -787,6 +792,62 @@ func TestInvokeVerify(t *testing.T) {
 	})
 }
 
+func TestInvokeHistoric(t *testing.T) {
+	chain, rpcSrv, httpSrv := initServerWithInMemoryChain(t)
+	defer chain.Close()
+	defer func() { _ = rpcSrv.Shutdown() }()
+
+	c, err := client.New(context.Background(), httpSrv.URL, client.Options{})
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	contract, err := util.Uint160DecodeStringLE(testContractHash)
+	require.NoError(t, err)
+
+	t.Run("function, at height", func(t *testing.T) {
+		res, err := c.InvokeFunctionAtHeight(1, contract, "symbol", nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, "FAULT", res.State) // not yet deployed
+
+		res, err = c.InvokeFunctionAtHeight(2, contract, "symbol", nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, "HALT", res.State, res.FaultException)
+		require.Equal(t, 1, len(res.Stack))
+		require.Equal(t, []byte("RUB"), res.Stack[0].Value())
+	})
+
+	t.Run("function, with state", func(t *testing.T) {
+		sr, err := chain.GetStateModule().GetStateRoot(2)
+		require.NoError(t, err)
+		res, err := c.InvokeFunctionWithState(sr.Root, contract, "symbol", nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, "HALT", res.State, res.FaultException)
+		require.Equal(t, []byte("RUB"), res.Stack[0].Value())
+	})
+
+	t.Run("script, with block hash", func(t *testing.T) {
+		script := []byte{byte(opcode.PUSH1), byte(opcode.RET)}
+		res, err := c.InvokeScriptWithState(chain.GetHeaderHash(1), script, nil)
+		require.NoError(t, err)
+		require.Equal(t, "HALT", res.State)
+		require.Equal(t, 1, len(res.Stack))
+	})
+
+	t.Run("verify, at height", func(t *testing.T) {
+		verify, err := util.Uint160DecodeStringLE(verifyContractHash)
+		require.NoError(t, err)
+		signers := []transaction.Signer{{Account: testchain.PrivateKeyByID(0).PublicKey().GetScriptHash()}}
+
+		_, err = c.InvokeContractVerifyAtHeight(1, verify, smartcontract.Params{}, signers)
+		require.Error(t, err) // not yet deployed
+
+		res, err := c.InvokeContractVerifyAtHeight(chain.BlockHeight(), verify, smartcontract.Params{}, signers)
+		require.NoError(t, err)
+		require.Equal(t, "HALT", res.State)
+		require.True(t, res.Stack[0].Value().(bool))
+	})
+}
+
 func TestClient_GetNativeContracts(t *testing.T) {
 	chain, rpcSrv, httpSrv := initServerWithInMemoryChain(t)
 	defer chain.Close()
@@ -918,3 +979,234 @@ func TestClient_NNS(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestClient_Batch(t *testing.T) {
+	chain, rpcSrv, httpSrv := initServerWithInMemoryChain(t)
+	defer chain.Close()
+	defer func() { _ = rpcSrv.Shutdown() }()
+
+	c, err := client.New(context.Background(), httpSrv.URL, client.Options{})
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	t.Run("mixed", func(t *testing.T) {
+		var (
+			count   uint32
+			hash    util.Uint256
+			version result.Version
+			bad     result.ApplicationLog
+		)
+		calls := []*client.BatchCall{
+			client.NewBatchCall(&count, "getblockcount"),
+			client.NewBatchCall(&hash, "getblockhash", 1),
+			client.NewBatchCall(&bad, "getapplicationlog", "notahash"),
+			client.NewBatchCall(&version, "getversion"),
+		}
+		require.NoError(t, c.Batch(calls))
+
+		require.NoError(t, calls[0].Err)
+		require.Equal(t, chain.BlockHeight()+1, count)
+		require.NoError(t, calls[1].Err)
+		require.Equal(t, chain.GetHeaderHash(1), hash)
+		require.Error(t, calls[2].Err)
+		require.NoError(t, calls[3].Err)
+		require.Equal(t, chain.GetConfig().Magic, version.Protocol.Network)
+	})
+	t.Run("split", func(t *testing.T) {
+		var (
+			n      = 2*request.MaxBatchSize + 1
+			hashes = make([]util.Uint256, n)
+			calls  = make([]*client.BatchCall, n)
+		)
+		for i := range calls {
+			calls[i] = client.NewBatchCall(&hashes[i], "getblockhash", i%int(chain.BlockHeight()+1))
+		}
+		require.NoError(t, c.Batch(calls))
+		for i := range calls {
+			require.NoError(t, calls[i].Err)
+			require.Equal(t, chain.GetHeaderHash(i%int(chain.BlockHeight()+1)), hashes[i])
+		}
+	})
+	t.Run("panic", func(t *testing.T) {
+		rpcHandlers["testpanic"] = func(*Server, request.Params) (interface{}, *response.Error) {
+			panic("oops")
+		}
+		t.Cleanup(func() { delete(rpcHandlers, "testpanic") })
+
+		var count uint32
+		calls := []*client.BatchCall{
+			client.NewBatchCall(nil, "testpanic"),
+			client.NewBatchCall(&count, "getblockcount"),
+		}
+		require.NoError(t, c.Batch(calls))
+		require.Error(t, calls[0].Err)
+		require.Contains(t, calls[0].Err.Error(), "Internal error")
+		require.NoError(t, calls[1].Err)
+		require.Equal(t, chain.BlockHeight()+1, count)
+	})
+}
+
+func TestClient_GetNotifications(t *testing.T) {
+	chain, rpcSrv, httpSrv := initClearServerWithCustomConfig(t, false, false, func(cfg *config.Config) {
+		cfg.ProtocolConfiguration.NotificationIndex = true
+	})
+	defer chain.Close()
+	defer func() { _ = rpcSrv.Shutdown() }()
+	for _, b := range getTestBlocks(t) {
+		require.NoError(t, chain.AddBlock(b))
+	}
+
+	c, err := client.New(context.Background(), httpSrv.URL, client.Options{})
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	h, err := util.Uint160DecodeStringLE(testContractHash)
+	require.NoError(t, err)
+
+	t.Run("all", func(t *testing.T) {
+		expected, err := chain.GetNotifications(h, "", 0, chain.BlockHeight(), 0)
+		require.NoError(t, err)
+		require.True(t, len(expected) > 0)
+
+		res, err := c.GetNotifications(h, "", 0, chain.BlockHeight(), 0)
+		require.NoError(t, err)
+		require.Equal(t, expected, res)
+	})
+	t.Run("by name with limit", func(t *testing.T) {
+		expected, err := chain.GetNotifications(h, "Transfer", 0, chain.BlockHeight(), 1)
+		require.NoError(t, err)
+		require.True(t, len(expected) > 0)
+
+		res, err := c.GetNotifications(h, "Transfer", 0, chain.BlockHeight(), 1)
+		require.NoError(t, err)
+		require.Equal(t, expected, res)
+		for _, ev := range res {
+			require.Equal(t, "Transfer", ev.Name)
+			require.Equal(t, res[0].BlockIndex, ev.BlockIndex)
+		}
+	})
+	t.Run("unknown contract", func(t *testing.T) {
+		res, err := c.GetNotifications(util.Uint160{1, 2, 3}, "", 0, chain.BlockHeight(), 0)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(res))
+	})
+	t.Run("bad range", func(t *testing.T) {
+		_, err := c.GetNotifications(h, "", 2, 1, 0)
+		require.Error(t, err)
+		_, err = c.GetNotifications(h, "", 0, chain.BlockHeight()+1, 0)
+		require.Error(t, err)
+	})
+	t.Run("bad limit", func(t *testing.T) {
+		_, err := c.GetNotifications(h, "", 0, chain.BlockHeight(), maxNotificationsLimit+1)
+		require.Error(t, err)
+	})
+}
+
+func TestClient_IteratorSessions(t *testing.T) {
+	initSessionServer := func(t *testing.T, customCfg func(*config.Config)) (*Server, *client.Client) {
+		chain, rpcSrv, httpSrv := initClearServerWithCustomConfig(t, false, false, customCfg)
+		t.Cleanup(func() {
+			_ = rpcSrv.Shutdown()
+			chain.Close()
+		})
+		for _, b := range getTestBlocks(t) {
+			require.NoError(t, chain.AddBlock(b))
+		}
+		c, err := client.New(context.Background(), httpSrv.URL, client.Options{})
+		require.NoError(t, err)
+		require.NoError(t, c.Init())
+		return rpcSrv, c
+	}
+	enableSessions := func(cfg *config.Config) {
+		cfg.ApplicationConfiguration.RPC.SessionEnabled = true
+		cfg.ApplicationConfiguration.RPC.SessionPoolSize = 2
+	}
+
+	h, err := util.Uint160DecodeStringLE(nameServiceContractHash)
+	require.NoError(t, err)
+
+	invokeTokens := func(t *testing.T, c *client.Client) (string, string) {
+		res, err := c.InvokeFunction(h, "tokens", nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, "HALT", res.State)
+		require.NotEmpty(t, res.Session)
+		require.Equal(t, 1, len(res.Stack))
+		require.Equal(t, stackitem.InteropT, res.Stack[0].Type())
+		iter, ok := res.Stack[0].Value().(result.Iterator)
+		require.True(t, ok)
+		require.NotEmpty(t, iter.ID)
+		return res.Session, iter.ID
+	}
+
+	t.Run("traverse", func(t *testing.T) {
+		rpcSrv, c := initSessionServer(t, enableSessions)
+
+		sID, iID := invokeTokens(t, c)
+		items, err := c.TraverseIterator(sID, iID, 1)
+		require.NoError(t, err)
+		require.Equal(t, []stackitem.Item{stackitem.Make("neo.com")}, items)
+
+		// Iterator is exhausted.
+		items, err = c.TraverseIterator(sID, iID, 1)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(items))
+
+		t.Run("bad parameters", func(t *testing.T) {
+			_, err := c.TraverseIterator(sID, iID, 0)
+			require.Error(t, err)
+			_, err = c.TraverseIterator(sID, iID, rpcSrv.config.MaxIteratorResultItems+1)
+			require.Error(t, err)
+			_, err = c.TraverseIterator(sID, "unknown", 1)
+			require.Error(t, err)
+		})
+
+		ok, err := c.TerminateSession(sID)
+		require.NoError(t, err)
+		require.True(t, ok)
+		ok, err = c.TerminateSession(sID)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		_, err = c.TraverseIterator(sID, iID, 1)
+		require.Error(t, err)
+	})
+	t.Run("pool size", func(t *testing.T) {
+		_, c := initSessionServer(t, enableSessions)
+
+		s1, _ := invokeTokens(t, c)
+		s2, _ := invokeTokens(t, c)
+		_, err := c.InvokeFunction(h, "tokens", nil, nil)
+		require.Error(t, err)
+		for _, s := range []string{s1, s2} {
+			ok, err := c.TerminateSession(s)
+			require.NoError(t, err)
+			require.True(t, ok)
+		}
+		invokeTokens(t, c)
+	})
+	t.Run("expiration", func(t *testing.T) {
+		_, c := initSessionServer(t, func(cfg *config.Config) {
+			enableSessions(cfg)
+			cfg.ApplicationConfiguration.RPC.SessionExpirationTime = 1
+		})
+
+		sID, iID := invokeTokens(t, c)
+		// Every traversal resets session expiration timer, so check it
+		// less often than the session expires.
+		require.Eventually(t, func() bool {
+			_, err := c.TraverseIterator(sID, iID, 1)
+			return err != nil
+		}, 5*time.Second, 1500*time.Millisecond)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		_, c := initSessionServer(t, nil)
+
+		res, err := c.InvokeFunction(h, "tokens", nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, res.Session)
+		_, err = c.TraverseIterator("some", "thing", 1)
+		require.Error(t, err)
+		_, err = c.TerminateSession("some")
+		require.Error(t, err)
+	})
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/consensus/monitor"
 	"github.com/nspcc-dev/neo-go/pkg/core"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/blockchainer"
@@ -46,6 +47,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
@@ -66,6 +68,9 @@ type (
 		https            *http.Server
 		shutdown         chan struct{}
 
+		sessionsLock sync.Mutex
+		sessions     map[string]*session
+
 		subsLock          sync.RWMutex
 		subscribers       map[*subscriber]bool
 		blockSubs         int
@@ -73,11 +78,13 @@ type (
 		notificationSubs  int
 		transactionSubs   int
 		notaryRequestSubs int
+		consensusSubs     int
 		blockCh           chan *block.Block
 		executionCh       chan *state.AppExecResult
 		notificationCh    chan *subscriptions.NotificationEvent
 		transactionCh     chan *transaction.Transaction
 		notaryRequestCh   chan mempoolevent.Event
+		consensusCh       chan *monitor.Round
 	}
 )
 
@@ -101,49 +108,61 @@ const (
 
 	// Maximum number of elements for get*transfers requests.
 	maxTransfersLimit = 1000
+
+	// Maximum number of elements for getnotifications requests.
+	maxNotificationsLimit = 1000
 )
 
 var rpcHandlers = map[string]func(*Server, request.Params) (interface{}, *response.Error){
-	"calculatenetworkfee":    (*Server).calculateNetworkFee,
-	"findstates":             (*Server).findStates,
-	"getapplicationlog":      (*Server).getApplicationLog,
-	"getbestblockhash":       (*Server).getBestBlockHash,
-	"getblock":               (*Server).getBlock,
-	"getblockcount":          (*Server).getBlockCount,
-	"getblockhash":           (*Server).getBlockHash,
-	"getblockheader":         (*Server).getBlockHeader,
-	"getblockheadercount":    (*Server).getBlockHeaderCount,
-	"getblocksysfee":         (*Server).getBlockSysFee,
-	"getcommittee":           (*Server).getCommittee,
-	"getconnectioncount":     (*Server).getConnectionCount,
-	"getcontractstate":       (*Server).getContractState,
-	"getnativecontracts":     (*Server).getNativeContracts,
-	"getnep11balances":       (*Server).getNEP11Balances,
-	"getnep11properties":     (*Server).getNEP11Properties,
-	"getnep11transfers":      (*Server).getNEP11Transfers,
-	"getnep17balances":       (*Server).getNEP17Balances,
-	"getnep17transfers":      (*Server).getNEP17Transfers,
-	"getpeers":               (*Server).getPeers,
-	"getproof":               (*Server).getProof,
-	"getrawmempool":          (*Server).getRawMempool,
-	"getrawtransaction":      (*Server).getrawtransaction,
-	"getstate":               (*Server).getState,
-	"getstateheight":         (*Server).getStateHeight,
-	"getstateroot":           (*Server).getStateRoot,
-	"getstorage":             (*Server).getStorage,
-	"gettransactionheight":   (*Server).getTransactionHeight,
-	"getunclaimedgas":        (*Server).getUnclaimedGas,
-	"getnextblockvalidators": (*Server).getNextBlockValidators,
-	"getversion":             (*Server).getVersion,
-	"invokefunction":         (*Server).invokeFunction,
-	"invokescript":           (*Server).invokescript,
-	"invokecontractverify":   (*Server).invokeContractVerify,
-	"sendrawtransaction":     (*Server).sendrawtransaction,
-	"submitblock":            (*Server).submitBlock,
-	"submitnotaryrequest":    (*Server).submitNotaryRequest,
-	"submitoracleresponse":   (*Server).submitOracleResponse,
-	"validateaddress":        (*Server).validateAddress,
-	"verifyproof":            (*Server).verifyProof,
+	"calculatenetworkfee":          (*Server).calculateNetworkFee,
+	"findstates":                   (*Server).findStates,
+	"getapplicationlog":            (*Server).getApplicationLog,
+	"getbestblockhash":             (*Server).getBestBlockHash,
+	"getblock":                     (*Server).getBlock,
+	"getblockcount":                (*Server).getBlockCount,
+	"getblockhash":                 (*Server).getBlockHash,
+	"getblockheader":               (*Server).getBlockHeader,
+	"getblockheadercount":          (*Server).getBlockHeaderCount,
+	"getblocksysfee":               (*Server).getBlockSysFee,
+	"getcommittee":                 (*Server).getCommittee,
+	"getconnectioncount":           (*Server).getConnectionCount,
+	"getconsensushistory":          (*Server).getConsensusHistory,
+	"getconsensusrounds":           (*Server).getConsensusRounds,
+	"getconsensusstatus":           (*Server).getConsensusStatus,
+	"getcontractstate":             (*Server).getContractState,
+	"getnativecontracts":           (*Server).getNativeContracts,
+	"getnep11balances":             (*Server).getNEP11Balances,
+	"getnep11properties":           (*Server).getNEP11Properties,
+	"getnep11transfers":            (*Server).getNEP11Transfers,
+	"getnep17balances":             (*Server).getNEP17Balances,
+	"getnep17transfers":            (*Server).getNEP17Transfers,
+	"getnotifications":             (*Server).getNotifications,
+	"getpeers":                     (*Server).getPeers,
+	"getproof":                     (*Server).getProof,
+	"getrawmempool":                (*Server).getRawMempool,
+	"getrawtransaction":            (*Server).getrawtransaction,
+	"getstate":                     (*Server).getState,
+	"getstateheight":               (*Server).getStateHeight,
+	"getstateroot":                 (*Server).getStateRoot,
+	"getstorage":                   (*Server).getStorage,
+	"gettransactionheight":         (*Server).getTransactionHeight,
+	"getunclaimedgas":              (*Server).getUnclaimedGas,
+	"getnextblockvalidators":       (*Server).getNextBlockValidators,
+	"getversion":                   (*Server).getVersion,
+	"invokefunction":               (*Server).invokeFunction,
+	"invokescript":                 (*Server).invokescript,
+	"invokecontractverify":         (*Server).invokeContractVerify,
+	"invokecontractverifyhistoric": (*Server).invokeContractVerifyHistoric,
+	"invokefunctionhistoric":       (*Server).invokeFunctionHistoric,
+	"invokescripthistoric":         (*Server).invokescripthistoric,
+	"sendrawtransaction":           (*Server).sendrawtransaction,
+	"submitblock":                  (*Server).submitBlock,
+	"submitnotaryrequest":          (*Server).submitNotaryRequest,
+	"submitoracleresponse":         (*Server).submitOracleResponse,
+	"terminatesession":             (*Server).terminateSession,
+	"traverseiterator":             (*Server).traverseIterator,
+	"validateaddress":              (*Server).validateAddress,
+	"verifyproof":                  (*Server).verifyProof,
 }
 
 var rpcWsHandlers = map[string]func(*Server, request.Params, *subscriber) (interface{}, *response.Error){
@@ -176,6 +195,16 @@ func New(chain blockchainer.Blockchainer, conf rpc.Config, coreServer *network.S
 	if orc != nil {
 		orc.SetBroadcaster(broadcaster.New(orc.MainCfg, log))
 	}
+	if conf.SessionEnabled {
+		if conf.SessionExpirationTime <= 0 {
+			conf.SessionExpirationTime = defaultSessionExpirationTime
+			log.Info("SessionExpirationTime is not set or wrong, setting default value", zap.Int("SessionExpirationTime", defaultSessionExpirationTime))
+		}
+		if conf.SessionPoolSize <= 0 {
+			conf.SessionPoolSize = defaultSessionPoolSize
+			log.Info("SessionPoolSize is not set or wrong, setting default value", zap.Int("SessionPoolSize", defaultSessionPoolSize))
+		}
+	}
 	return Server{
 		Server:           httpServer,
 		chain:            chain,
@@ -188,6 +217,8 @@ func New(chain blockchainer.Blockchainer, conf rpc.Config, coreServer *network.S
 		https:            tlsServer,
 		shutdown:         make(chan struct{}),
 
+		sessions: make(map[string]*session),
+
 		subscribers: make(map[*subscriber]bool),
 		// These are NOT buffered to preserve original order of events.
 		blockCh:         make(chan *block.Block),
@@ -195,6 +226,7 @@ func New(chain blockchainer.Blockchainer, conf rpc.Config, coreServer *network.S
 		notificationCh:  make(chan *subscriptions.NotificationEvent),
 		transactionCh:   make(chan *transaction.Transaction),
 		notaryRequestCh: make(chan mempoolevent.Event),
+		consensusCh:     make(chan *monitor.Round),
 	}
 }
 
@@ -261,6 +293,8 @@ func (s *Server) Shutdown() error {
 	// Wait for handleSubEvents to finish.
 	<-s.executionCh
 
+	s.dropAllSessions()
+
 	if err == nil {
 		return httpsErr
 	}
@@ -328,9 +362,38 @@ func (s *Server) handleRequest(req *request.Request, sub *subscriber) response.A
 		return s.handleIn(req.In, sub)
 	}
 	resp := make(response.AbstractBatch, len(req.Batch))
-	for i, in := range req.Batch {
-		resp[i] = s.handleIn(&in, sub)
+	if s.config.MaxBatchWorkers <= 1 || len(req.Batch) == 1 {
+		for i := range req.Batch {
+			resp[i] = s.handleIn(&req.Batch[i], sub)
+		}
+		return resp
+	}
+	// Batch entries are independent of each other, so they're processed
+	// concurrently with at most MaxBatchWorkers goroutines, responses are
+	// returned in the same order as requests.
+	var (
+		wg      sync.WaitGroup
+		workers = make(chan struct{}, s.config.MaxBatchWorkers)
+	)
+	wg.Add(len(req.Batch))
+	for i := range req.Batch {
+		workers <- struct{}{}
+		go func(i int) {
+			defer func() {
+				// Panic in a separate goroutine can't be handled by the
+				// HTTP server, so it's turned into an error response for
+				// this entry only.
+				if r := recover(); r != nil {
+					resp[i] = s.packResponse(&req.Batch[i], nil,
+						response.NewInternalServerError("Internal error", fmt.Errorf("panic: %v", r)))
+				}
+				<-workers
+				wg.Done()
+			}()
+			resp[i] = s.handleIn(&req.Batch[i], sub)
+		}(i)
 	}
+	wg.Wait()
 	return resp
 }
 
@@ -657,6 +720,67 @@ func (s *Server) getApplicationLog(reqParams request.Params) (interface{}, *resp
 	return result.NewApplicationLog(hash, appExecResults, trig), nil
 }
 
+// getNotifications returns notifications of the specified contract emitted in
+// the specified range of blocks using notification index.
+func (s *Server) getNotifications(reqParams request.Params) (interface{}, *response.Error) {
+	contract, respErr := s.contractScriptHashFromParam(reqParams.Value(0))
+	if respErr != nil {
+		return nil, respErr
+	}
+	var (
+		height = s.chain.BlockHeight()
+		start  uint32
+		end    = height
+		name   string
+		limit  = maxNotificationsLimit
+	)
+	if p := reqParams.Value(1); p != nil {
+		val, err := p.GetInt()
+		if err != nil || val < 0 || uint32(val) > height {
+			return nil, invalidBlockHeightError(1, val)
+		}
+		start = uint32(val)
+	}
+	if p := reqParams.Value(2); p != nil {
+		val, err := p.GetInt()
+		if err != nil || val < 0 || uint32(val) > height {
+			return nil, invalidBlockHeightError(2, val)
+		}
+		end = uint32(val)
+	}
+	if start > end {
+		return nil, response.WrapErrorWithData(response.ErrInvalidParams, errors.New("start block is greater than the end one"))
+	}
+	if p := reqParams.Value(3); p != nil {
+		val, err := p.GetString()
+		if err != nil {
+			return nil, response.WrapErrorWithData(response.ErrInvalidParams, fmt.Errorf("invalid event name: %w", err))
+		}
+		name = val
+	}
+	if p := reqParams.Value(4); p != nil {
+		val, err := p.GetInt()
+		if err != nil {
+			return nil, response.WrapErrorWithData(response.ErrInvalidParams, fmt.Errorf("invalid limit: %w", err))
+		}
+		if val <= 0 || val > maxNotificationsLimit {
+			return nil, response.WrapErrorWithData(response.ErrInvalidParams, fmt.Errorf("limit should be in range [1, %d]", maxNotificationsLimit))
+		}
+		limit = val
+	}
+	events, err := s.chain.GetNotifications(contract, name, start, end, limit)
+	if err != nil {
+		if errors.Is(err, core.ErrNotificationIndexDisabled) {
+			return nil, response.NewInvalidRequestError("notification index is disabled", err)
+		}
+		return nil, response.NewInternalServerError("failed to get notifications", err)
+	}
+	if events == nil {
+		events = []state.IndexedNotificationEvent{}
+	}
+	return events, nil
+}
+
 func (s *Server) getNEP11Tokens(h util.Uint160, acc util.Uint160, bw *io.BufBinWriter) ([]stackitem.Item, error) {
 	item, finalize, err := s.invokeReadOnly(bw, h, "tokensOf", acc)
 	if err != nil {
@@ -664,7 +788,7 @@ func (s *Server) getNEP11Tokens(h util.Uint160, acc util.Uint160, bw *io.BufBinW
 	}
 	defer finalize()
 	if (item.Type() == stackitem.InteropT) && iterator.IsIterator(item) {
-		vals, _ := iterator.Values(item, s.config.MaxNEP11Tokens)
+		vals := iterator.Values(item, s.config.MaxNEP11Tokens)
 		return vals, nil
 	}
 	return nil, fmt.Errorf("invalid `tokensOf` result type %s", item.String())
@@ -849,7 +973,7 @@ func (s *Server) invokeReadOnly(bw *io.BufBinWriter, h util.Uint160, method stri
 	}
 	script := bw.Bytes()
 	tx := &transaction.Transaction{Script: script}
-	b, err := s.getFakeNextBlock()
+	b, err := s.getFakeNextBlock(s.chain.BlockHeight() + 1)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1552,8 +1676,73 @@ func (s *Server) getCommittee(_ request.Params) (interface{}, *response.Error) {
 	return keys, nil
 }
 
+var errConsensusMonitorDisabled = errors.New("consensus monitoring is disabled")
+
+// getConsensusRounds returns consensus rounds tracked by the node.
+func (s *Server) getConsensusRounds(_ request.Params) (interface{}, *response.Error) {
+	m := s.coreServer.ConsensusMonitor()
+	if m == nil {
+		return nil, response.NewInvalidRequestError("'getconsensusrounds' is not supported", errConsensusMonitorDisabled)
+	}
+	return m.Rounds(), nil
+}
+
+// getConsensusStatus returns the current consensus round with messages
+// received for its height.
+func (s *Server) getConsensusStatus(_ request.Params) (interface{}, *response.Error) {
+	m := s.coreServer.ConsensusMonitor()
+	if m == nil {
+		return nil, response.NewInvalidRequestError("'getconsensusstatus' is not supported", errConsensusMonitorDisabled)
+	}
+	return m.Status(), nil
+}
+
+// getConsensusHistory returns consensus messages traced for the last heights.
+func (s *Server) getConsensusHistory(reqParams request.Params) (interface{}, *response.Error) {
+	m := s.coreServer.ConsensusMonitor()
+	if m == nil {
+		return nil, response.NewInvalidRequestError("'getconsensushistory' is not supported", errConsensusMonitorDisabled)
+	}
+	var count int
+	if len(reqParams) > 0 {
+		var err error
+		count, err = reqParams[0].GetInt()
+		if err != nil || count < 0 {
+			return nil, response.ErrInvalidParams
+		}
+	}
+	return m.History(count), nil
+}
+
 // invokeFunction implements the `invokeFunction` RPC call.
 func (s *Server) invokeFunction(reqParams request.Params) (interface{}, *response.Error) {
+	tx, respErr := s.getInvokeFunctionParams(reqParams)
+	if respErr != nil {
+		return nil, respErr
+	}
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil)
+}
+
+// invokeFunctionHistoric implements the `invokeFunctionHistoric` RPC call.
+func (s *Server) invokeFunctionHistoric(reqParams request.Params) (interface{}, *response.Error) {
+	nextH, respErr := s.getHistoricParams(reqParams)
+	if respErr != nil {
+		return nil, respErr
+	}
+	if len(reqParams) < 2 {
+		return nil, response.ErrInvalidParams
+	}
+	tx, respErr := s.getInvokeFunctionParams(reqParams[1:])
+	if respErr != nil {
+		return nil, respErr
+	}
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, &nextH)
+}
+
+func (s *Server) getInvokeFunctionParams(reqParams request.Params) (*transaction.Transaction, *response.Error) {
+	if len(reqParams) < 2 {
+		return nil, response.ErrInvalidParams
+	}
 	scriptHash, responseErr := s.contractScriptHashFromParam(reqParams.Value(0))
 	if responseErr != nil {
 		return nil, responseErr
@@ -1575,16 +1764,45 @@ func (s *Server) invokeFunction(reqParams request.Params) (interface{}, *respons
 	if err != nil {
 		return nil, response.ErrInvalidParams
 	}
-	script, err := request.CreateFunctionInvocationScript(scriptHash, method, reqParams[2:checkWitnessHashesIndex])
+	params := reqParams[2:checkWitnessHashesIndex]
+	if len(params) == 1 && params[0].IsNull() {
+		// Parameters are omitted by the client.
+		params = nil
+	}
+	script, err := request.CreateFunctionInvocationScript(scriptHash, method, params)
 	if err != nil {
 		return nil, response.NewInternalServerError("can't create invocation script", err)
 	}
 	tx.Script = script
-	return s.runScriptInVM(trigger.Application, script, util.Uint160{}, tx)
+	return tx, nil
 }
 
 // invokescript implements the `invokescript` RPC call.
 func (s *Server) invokescript(reqParams request.Params) (interface{}, *response.Error) {
+	tx, respErr := s.getInvokeScriptParams(reqParams)
+	if respErr != nil {
+		return nil, respErr
+	}
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil)
+}
+
+// invokescripthistoric implements the `invokescripthistoric` RPC call.
+func (s *Server) invokescripthistoric(reqParams request.Params) (interface{}, *response.Error) {
+	nextH, respErr := s.getHistoricParams(reqParams)
+	if respErr != nil {
+		return nil, respErr
+	}
+	if len(reqParams) < 2 {
+		return nil, response.ErrInvalidParams
+	}
+	tx, respErr := s.getInvokeScriptParams(reqParams[1:])
+	if respErr != nil {
+		return nil, respErr
+	}
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, &nextH)
+}
+
+func (s *Server) getInvokeScriptParams(reqParams request.Params) (*transaction.Transaction, *response.Error) {
 	if len(reqParams) < 1 {
 		return nil, response.ErrInvalidParams
 	}
@@ -1607,26 +1825,50 @@ func (s *Server) invokescript(reqParams request.Params) (interface{}, *response.
 		tx.Signers = []transaction.Signer{{Account: util.Uint160{}, Scopes: transaction.None}}
 	}
 	tx.Script = script
-	return s.runScriptInVM(trigger.Application, script, util.Uint160{}, tx)
+	return tx, nil
 }
 
 // invokeContractVerify implements the `invokecontractverify` RPC call.
 func (s *Server) invokeContractVerify(reqParams request.Params) (interface{}, *response.Error) {
+	scriptHash, tx, invocationScript, respErr := s.getInvokeContractVerifyParams(reqParams)
+	if respErr != nil {
+		return nil, respErr
+	}
+	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, nil)
+}
+
+// invokeContractVerifyHistoric implements the `invokecontractverifyhistoric` RPC call.
+func (s *Server) invokeContractVerifyHistoric(reqParams request.Params) (interface{}, *response.Error) {
+	nextH, respErr := s.getHistoricParams(reqParams)
+	if respErr != nil {
+		return nil, respErr
+	}
+	if len(reqParams) < 2 {
+		return nil, response.ErrInvalidParams
+	}
+	scriptHash, tx, invocationScript, respErr := s.getInvokeContractVerifyParams(reqParams[1:])
+	if respErr != nil {
+		return nil, respErr
+	}
+	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, &nextH)
+}
+
+func (s *Server) getInvokeContractVerifyParams(reqParams request.Params) (util.Uint160, *transaction.Transaction, []byte, *response.Error) {
 	scriptHash, responseErr := s.contractScriptHashFromParam(reqParams.Value(0))
 	if responseErr != nil {
-		return nil, responseErr
+		return util.Uint160{}, nil, nil, responseErr
 	}
 
 	bw := io.NewBufBinWriter()
 	if len(reqParams) > 1 {
 		args, err := reqParams[1].GetArray() // second `invokecontractverify` parameter is an array of arguments for `verify` method
 		if err != nil {
-			return nil, response.WrapErrorWithData(response.ErrInvalidParams, err)
+			return util.Uint160{}, nil, nil, response.WrapErrorWithData(response.ErrInvalidParams, err)
 		}
 		if len(args) > 0 {
 			err := request.ExpandArrayIntoScript(bw.BinWriter, args)
 			if err != nil {
-				return nil, response.NewRPCError("can't create witness invocation script", err.Error(), err)
+				return util.Uint160{}, nil, nil, response.NewRPCError("can't create witness invocation script", err.Error(), err)
 			}
 		}
 	}
@@ -1636,7 +1878,7 @@ func (s *Server) invokeContractVerify(reqParams request.Params) (interface{}, *r
 	if len(reqParams) > 2 {
 		signers, witnesses, err := reqParams[2].GetSignersWithWitnesses()
 		if err != nil {
-			return nil, response.ErrInvalidParams
+			return util.Uint160{}, nil, nil, response.ErrInvalidParams
 		}
 		tx.Signers = signers
 		tx.Scripts = witnesses
@@ -1644,16 +1886,48 @@ func (s *Server) invokeContractVerify(reqParams request.Params) (interface{}, *r
 		tx.Signers = []transaction.Signer{{Account: scriptHash}}
 		tx.Scripts = []transaction.Witness{{InvocationScript: invocationScript, VerificationScript: []byte{}}}
 	}
-	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx)
+	return scriptHash, tx, invocationScript, nil
+}
+
+// getHistoricParams checks that historic calls are supported and returns index of
+// a fake next block to perform the historic call. It also checks that
+// specified stateroot is stored at the specified height for further request
+// handling consistency.
+func (s *Server) getHistoricParams(reqParams request.Params) (uint32, *response.Error) {
+	if s.chain.GetConfig().KeepOnlyLatestState {
+		return 0, response.NewInvalidRequestError("historic invocations are not supported", errKeepOnlyLatestState)
+	}
+	if len(reqParams) < 1 {
+		return 0, response.ErrInvalidParams
+	}
+	// Hashes are checked first, because zero-prefixed hash strings are
+	// valid integers.
+	hash, err := reqParams.Value(0).GetUint256()
+	if err != nil {
+		height, respErr := s.blockHeightFromParam(reqParams.Value(0))
+		if respErr != nil {
+			return 0, response.NewInvalidParamsError("invalid block hash or index or stateroot hash", err)
+		}
+		return uint32(height) + 1, nil
+	}
+	b, err := s.chain.GetBlock(hash)
+	if err == nil {
+		return b.Index + 1, nil
+	}
+	stateH, err := s.chain.GetStateModule().GetLatestStateHeight(hash)
+	if err != nil {
+		return 0, response.NewInvalidParamsError("unknown block or stateroot", err)
+	}
+	return stateH + 1, nil
 }
 
-func (s *Server) getFakeNextBlock() (*block.Block, error) {
+func (s *Server) getFakeNextBlock(nextBlockHeight uint32) (*block.Block, error) {
 	// When transferring funds, script execution does no auto GAS claim,
 	// because it depends on persisting tx height.
 	// This is why we provide block here.
 	b := block.New(s.stateRootEnabled)
-	b.Index = s.chain.BlockHeight() + 1
-	hdr, err := s.chain.GetHeader(s.chain.GetHeaderHash(int(s.chain.BlockHeight())))
+	b.Index = nextBlockHeight
+	hdr, err := s.chain.GetHeader(s.chain.GetHeaderHash(int(nextBlockHeight - 1)))
 	if err != nil {
 		return nil, err
 	}
@@ -1665,41 +1939,163 @@ func (s *Server) getFakeNextBlock() (*block.Block, error) {
 // result. The script is either a simple script in case of `application` trigger
 // witness invocation script in case of `verification` trigger (it pushes `verify`
 // arguments on stack before verification). In case of contract verification
-// contractScriptHash should be specified.
-func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash util.Uint160, tx *transaction.Transaction) (*result.Invoke, *response.Error) {
-	b, err := s.getFakeNextBlock()
+// contractScriptHash should be specified. If nextH is specified, then the script
+// is run over the historic state at the nextH-1 height.
+func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash util.Uint160, tx *transaction.Transaction, nextH *uint32) (*result.Invoke, *response.Error) {
+	var (
+		nextHeight = s.chain.BlockHeight() + 1
+		v          *vm.VM
+		finalize   func()
+		err        error
+	)
+	if nextH != nil {
+		nextHeight = *nextH
+	}
+	b, err := s.getFakeNextBlock(nextHeight)
 	if err != nil {
 		return nil, response.NewInternalServerError("can't create fake block", err)
 	}
-	vm, finalize := s.chain.GetTestVM(t, tx, b)
-	vm.GasLimit = int64(s.config.MaxGasInvoke)
+	getContract := func(h util.Uint160) (*state.Contract, error) {
+		res := s.chain.GetContractState(h)
+		if res == nil {
+			return nil, fmt.Errorf("unknown contract: %s", h.StringBE())
+		}
+		return res, nil
+	}
+	if nextH == nil {
+		v, finalize = s.chain.GetTestVM(t, tx, b)
+	} else {
+		v, finalize, err = s.chain.GetTestHistoricVM(t, tx, b)
+		if err != nil {
+			return nil, response.NewInternalServerError("failed to create historic VM", err)
+		}
+		sr, err := s.chain.GetStateModule().GetStateRoot(nextHeight - 1)
+		if err != nil {
+			return nil, response.NewInternalServerError("failed to get historic state root", err)
+		}
+		getContract = func(h util.Uint160) (*state.Contract, error) {
+			res, respErr := s.getHistoricalContractState(sr.Root, h)
+			if respErr != nil {
+				return nil, fmt.Errorf("unknown contract: %s", h.StringBE())
+			}
+			return res, nil
+		}
+	}
+	v.GasLimit = int64(s.config.MaxGasInvoke)
 	if t == trigger.Verification {
 		// We need this special case because witnesses verification is not the simple System.Contract.Call,
 		// and we need to define exactly the amount of gas consumed for a contract witness verification.
 		gasPolicy := s.chain.GetPolicer().GetMaxVerificationGAS()
-		if vm.GasLimit > gasPolicy {
-			vm.GasLimit = gasPolicy
+		if v.GasLimit > gasPolicy {
+			v.GasLimit = gasPolicy
 		}
 
-		err := s.chain.InitVerificationVM(vm, func(h util.Uint160) (*state.Contract, error) {
-			res := s.chain.GetContractState(h)
-			if res == nil {
-				return nil, fmt.Errorf("unknown contract: %s", h.StringBE())
-			}
-			return res, nil
-		}, contractScriptHash, &transaction.Witness{InvocationScript: script, VerificationScript: []byte{}})
+		err := s.chain.InitVerificationVM(v, getContract, contractScriptHash, &transaction.Witness{InvocationScript: script, VerificationScript: []byte{}})
 		if err != nil {
 			return nil, response.NewInternalServerError("can't prepare verification VM", err)
 		}
 	} else {
-		vm.LoadScriptWithFlags(script, callflag.All)
+		v.LoadScriptWithFlags(script, callflag.All)
 	}
-	err = vm.Run()
+	err = v.Run()
 	var faultException string
 	if err != nil {
 		faultException = err.Error()
 	}
-	return result.NewInvoke(vm, finalize, script, faultException, s.config.MaxIteratorResultItems), nil
+	if !s.config.SessionEnabled {
+		return result.NewInvoke(v, finalize, script, faultException, s.config.MaxIteratorResultItems), nil
+	}
+	// Finalizer is called on session termination if there are iterators
+	// to be traversed later.
+	res := result.NewInvoke(v, nil, script, faultException, s.config.MaxIteratorResultItems)
+	registered, err := s.registerSession(res, finalize)
+	if err != nil {
+		finalize()
+		return nil, response.NewInternalServerError("can't create session", err)
+	}
+	if !registered {
+		finalize()
+	}
+	return res, nil
+}
+
+// traverseIterator implements the `traverseiterator` RPC call.
+func (s *Server) traverseIterator(reqParams request.Params) (interface{}, *response.Error) {
+	if !s.config.SessionEnabled {
+		return nil, response.NewInvalidRequestError("sessions are disabled", nil)
+	}
+	sID, err := reqParams.Value(0).GetString()
+	if err != nil {
+		return nil, response.WrapErrorWithData(response.ErrInvalidParams, fmt.Errorf("invalid session ID: %w", err))
+	}
+	iID, err := reqParams.Value(1).GetString()
+	if err != nil {
+		return nil, response.WrapErrorWithData(response.ErrInvalidParams, fmt.Errorf("invalid iterator ID: %w", err))
+	}
+	count, err := reqParams.Value(2).GetInt()
+	if err != nil {
+		return nil, response.WrapErrorWithData(response.ErrInvalidParams, fmt.Errorf("invalid iterator items count: %w", err))
+	}
+	if count <= 0 || count > s.config.MaxIteratorResultItems {
+		return nil, response.WrapErrorWithData(response.ErrInvalidParams, fmt.Errorf("iterator items count is out of range (%d at max)", s.config.MaxIteratorResultItems))
+	}
+
+	s.sessionsLock.Lock()
+	sess, ok := s.sessions[sID]
+	if !ok {
+		s.sessionsLock.Unlock()
+		return nil, response.WrapErrorWithData(response.ErrInvalidParams, fmt.Errorf("unknown session %s", sID))
+	}
+	// Iterator traversal prolongs the session lifetime.
+	sess.timer.Reset(time.Second * time.Duration(s.config.SessionExpirationTime))
+	sess.iteratorsLock.Lock()
+	// Release sessions lock as far as we have a lock for the current session iterators.
+	s.sessionsLock.Unlock()
+	defer sess.iteratorsLock.Unlock()
+
+	var item stackitem.Item
+	for _, it := range sess.iteratorIdentifiers {
+		if it.ID == iID {
+			item = it.Item
+			break
+		}
+	}
+	if item == nil {
+		return nil, response.WrapErrorWithData(response.ErrInvalidParams, fmt.Errorf("unknown iterator %s", iID))
+	}
+	vals := iterator.Values(item, count)
+	res := make([]json.RawMessage, len(vals))
+	for i := range vals {
+		res[i], err = stackitem.ToJSONWithTypes(vals[i])
+		if err != nil {
+			return nil, response.NewInternalServerError("failed to marshal iterator value", err)
+		}
+	}
+	return res, nil
+}
+
+// terminateSession implements the `terminatesession` RPC call.
+func (s *Server) terminateSession(reqParams request.Params) (interface{}, *response.Error) {
+	if !s.config.SessionEnabled {
+		return nil, response.NewInvalidRequestError("sessions are disabled", nil)
+	}
+	sID, err := reqParams.Value(0).GetString()
+	if err != nil {
+		return nil, response.WrapErrorWithData(response.ErrInvalidParams, fmt.Errorf("invalid session ID: %w", err))
+	}
+	s.sessionsLock.Lock()
+	defer s.sessionsLock.Unlock()
+	sess, ok := s.sessions[sID]
+	if ok {
+		// Iterators may use storage seek channel under the hood, the finalizer
+		// closes it, thus finalization is performed under iteratorsLock.
+		sess.timer.Stop()
+		sess.iteratorsLock.Lock()
+		sess.finalize()
+		delete(s.sessions, sID)
+		sess.iteratorsLock.Unlock()
+	}
+	return ok, nil
 }
 
 // submitBlock broadcasts a raw block over the NEO network.
@@ -1823,6 +2219,9 @@ func (s *Server) subscribe(reqParams request.Params, sub *subscriber) (interface
 	if event == response.NotaryRequestEventID && !s.chain.P2PSigExtensionsEnabled() {
 		return nil, response.WrapErrorWithData(response.ErrInvalidParams, errors.New("P2PSigExtensions are disabled"))
 	}
+	if event == response.ConsensusRoundEventID && s.coreServer.ConsensusMonitor() == nil {
+		return nil, response.WrapErrorWithData(response.ErrInvalidParams, errConsensusMonitorDisabled)
+	}
 	// Optional filter.
 	var filter interface{}
 	if p := reqParams.Value(1); p != nil {
@@ -1908,6 +2307,11 @@ func (s *Server) subscribeToChannel(event response.EventID) {
 			s.coreServer.SubscribeForNotaryRequests(s.notaryRequestCh)
 		}
 		s.notaryRequestSubs++
+	case response.ConsensusRoundEventID:
+		if s.consensusSubs == 0 {
+			s.coreServer.ConsensusMonitor().SubscribeForRounds(s.consensusCh)
+		}
+		s.consensusSubs++
 	}
 }
 
@@ -1959,6 +2363,11 @@ func (s *Server) unsubscribeFromChannel(event response.EventID) {
 		if s.notaryRequestSubs == 0 {
 			s.coreServer.UnsubscribeFromNotaryRequests(s.notaryRequestCh)
 		}
+	case response.ConsensusRoundEventID:
+		s.consensusSubs--
+		if s.consensusSubs == 0 {
+			s.coreServer.ConsensusMonitor().UnsubscribeFromRounds(s.consensusCh)
+		}
 	}
 }
 
@@ -2005,6 +2414,9 @@ chloop:
 				Type:          e.Type,
 				NotaryRequest: e.Data.(*payload.P2PNotaryRequest),
 			}
+		case r := <-s.consensusCh:
+			resp.Event = response.ConsensusRoundEventID
+			resp.Payload[0] = r
 		}
 		s.subsLock.RLock()
 	subloop:
@@ -2060,6 +2472,9 @@ chloop:
 	if s.chain.P2PSigExtensionsEnabled() {
 		s.coreServer.UnsubscribeFromNotaryRequests(s.notaryRequestCh)
 	}
+	if m := s.coreServer.ConsensusMonitor(); m != nil {
+		m.UnsubscribeFromRounds(s.consensusCh)
+	}
 	s.subsLock.Unlock()
 drainloop:
 	for {
@@ -2069,6 +2484,7 @@ drainloop:
 		case <-s.notificationCh:
 		case <-s.transactionCh:
 		case <-s.notaryRequestCh:
+		case <-s.consensusCh:
 		default:
 			break drainloop
 		}
@@ -2080,6 +2496,7 @@ drainloop:
 	close(s.notificationCh)
 	close(s.executionCh)
 	close(s.notaryRequestCh)
+	close(s.consensusCh)
 }
 
 func (s *Server) blockHeightFromParam(param *request.Param) (int, *response.Error) {
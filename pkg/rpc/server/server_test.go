@@ -393,6 +393,23 @@ var rpcTestCases = map[string][]rpcTestCase{
 			check:  checkNep17Transfers,
 		},
 	},
+	"getnotifications": {
+		{
+			name:   "no params",
+			params: `[]`,
+			fail:   true,
+		},
+		{
+			name:   "unknown contract",
+			params: `["notacontract"]`,
+			fail:   true,
+		},
+		{
+			name:   "index is disabled",
+			params: `["` + testContractHash + `"]`,
+			fail:   true,
+		},
+	},
 	"getproof": {
 		{
 			name:   "no params",
@@ -702,6 +719,27 @@ var rpcTestCases = map[string][]rpcTestCase{
 			},
 		},
 	},
+	"getconsensushistory": {
+		{
+			name:   "monitoring disabled",
+			params: "[]",
+			fail:   true,
+		},
+	},
+	"getconsensusrounds": {
+		{
+			name:   "monitoring disabled",
+			params: "[]",
+			fail:   true,
+		},
+	},
+	"getconsensusstatus": {
+		{
+			name:   "monitoring disabled",
+			params: "[]",
+			fail:   true,
+		},
+	},
 	"getconnectioncount": {
 		{
 			params: "[]",
@@ -1081,6 +1119,106 @@ var rpcTestCases = map[string][]rpcTestCase{
 			fail:   true,
 		},
 	},
+	"invokecontractverifyhistoric": {
+		{
+			name:   "positive, by index",
+			params: fmt.Sprintf(`[12, "%s", [], [{"account":"%s"}]]`, verifyContractHash, testchain.PrivateKeyByID(0).PublicKey().GetScriptHash().StringLE()),
+			result: func(e *executor) interface{} { return &result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv interface{}) {
+				res, ok := inv.(*result.Invoke)
+				require.True(t, ok)
+				assert.Nil(t, res.Script)
+				assert.Equal(t, "HALT", res.State)
+				assert.NotEqual(t, 0, res.GasConsumed)
+				assert.Equal(t, true, res.Stack[0].Value().(bool))
+			},
+		},
+		{
+			name:   "contract is not yet deployed",
+			params: fmt.Sprintf(`[1, "%s", [], [{"account":"%s"}]]`, verifyContractHash, testchain.PrivateKeyByID(0).PublicKey().GetScriptHash().StringLE()),
+			fail:   true,
+		},
+		{
+			name:   "no params",
+			params: `[]`,
+			fail:   true,
+		},
+		{
+			name:   "no contract",
+			params: `[12]`,
+			fail:   true,
+		},
+	},
+	"invokefunctionhistoric": {
+		{
+			name:   "positive, by index",
+			params: fmt.Sprintf(`[2, "%s", "symbol", []]`, testContractHash),
+			result: func(e *executor) interface{} { return &result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv interface{}) {
+				res, ok := inv.(*result.Invoke)
+				require.True(t, ok)
+				assert.NotNil(t, res.Script)
+				assert.Equal(t, "HALT", res.State, res.FaultException)
+				require.Equal(t, 1, len(res.Stack))
+				assert.Equal(t, []byte("RUB"), res.Stack[0].Value())
+			},
+		},
+		{
+			name:   "positive, by block hash",
+			params: fmt.Sprintf(`["%s", "%s", "symbol", []]`, genesisBlockHash, testContractHash),
+			result: func(e *executor) interface{} { return &result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv interface{}) {
+				res, ok := inv.(*result.Invoke)
+				require.True(t, ok)
+				// Contract isn't yet deployed at the genesis block.
+				assert.Equal(t, "FAULT", res.State)
+			},
+		},
+		{
+			name:   "no params",
+			params: `[]`,
+			fail:   true,
+		},
+		{
+			name:   "invalid height",
+			params: fmt.Sprintf(`[100500, "%s", "symbol", []]`, testContractHash),
+			fail:   true,
+		},
+		{
+			name:   "no method",
+			params: fmt.Sprintf(`[2, "%s"]`, testContractHash),
+			fail:   true,
+		},
+	},
+	"invokescripthistoric": {
+		{
+			name:   "positive",
+			params: `[1, "UcVrDUhlbGxvLCB3b3JsZCFoD05lby5SdW50aW1lLkxvZ2FsdWY="]`,
+			result: func(e *executor) interface{} { return &result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv interface{}) {
+				res, ok := inv.(*result.Invoke)
+				require.True(t, ok)
+				assert.NotEqual(t, "", res.Script)
+				assert.NotEqual(t, "", res.State)
+				assert.NotEqual(t, 0, res.GasConsumed)
+			},
+		},
+		{
+			name:   "no params",
+			params: `[]`,
+			fail:   true,
+		},
+		{
+			name:   "no script",
+			params: `[1]`,
+			fail:   true,
+		},
+		{
+			name:   "unknown stateroot",
+			params: `["0000000000000000000000000000000000000000000000000000000000000000", "UcVrDUhlbGxvLCB3b3JsZCFoD05lby5SdW50aW1lLkxvZ2FsdWY="]`,
+			fail:   true,
+		},
+	},
 	"sendrawtransaction": {
 		{
 			name:   "positive",
@@ -1571,6 +1709,19 @@ func testRPCProtocol(t *testing.T, doRPCCall func(string, string, *testing.T) []
 			testGetState(t, params, base64.StdEncoding.EncodeToString([]byte("newtestvalue")))
 		})
 	})
+	t.Run("invokefunctionhistoric by stateroot", func(t *testing.T) {
+		root, err := e.chain.GetStateModule().GetStateRoot(2)
+		require.NoError(t, err)
+		rpc := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "invokefunctionhistoric", "params": ["%s", "%s", "symbol", []]}`, root.Root.StringLE(), testContractHash)
+		body := doRPCCall(rpc, httpSrv.URL, t)
+		rawRes := checkErrGetResult(t, body, false)
+
+		res := new(result.Invoke)
+		require.NoError(t, json.Unmarshal(rawRes, res))
+		require.Equal(t, "HALT", res.State, res.FaultException)
+		require.Equal(t, 1, len(res.Stack))
+		require.Equal(t, []byte("RUB"), res.Stack[0].Value())
+	})
 	t.Run("findstates", func(t *testing.T) {
 		testFindStates := func(t *testing.T, p string, root util.Uint256, expected result.FindStates) {
 			rpc := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "findstates", "params": [%s]}`, p)
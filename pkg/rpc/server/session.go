@@ -0,0 +1,124 @@
+package server
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/iterator"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+type (
+	// session holds a set of iterators got after invoke* call with corresponding
+	// finalizer and session expiration timer.
+	session struct {
+		// iteratorsLock protects iteratorIdentifiers of the current session.
+		iteratorsLock sync.Mutex
+		// iteratorIdentifiers stores the set of Iterator stackitems got from
+		// the original invocation.
+		iteratorIdentifiers []*iteratorIdentifier
+		timer               *time.Timer
+		finalize            func()
+	}
+	// iteratorIdentifier represents Iterator on the server side, holding iterator ID and Iterator stackitem.
+	iteratorIdentifier struct {
+		ID   string
+		Item stackitem.Item
+	}
+)
+
+const (
+	// defaultSessionExpirationTime is the default session lifetime (in seconds)
+	// used if SessionExpirationTime is not set in the configuration.
+	defaultSessionExpirationTime = 15
+
+	// defaultSessionPoolSize is the default maximum number of concurrently
+	// running sessions used if SessionPoolSize is not set in the configuration.
+	defaultSessionPoolSize = 20
+)
+
+// newSessionID returns new random RFC 4122 version 4 UUID string that is used
+// as a session or iterator identifier.
+func newSessionID() (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		return "", err
+	}
+	u[6] = (u[6] & 0x0f) | 0x40 // Version 4.
+	u[8] = (u[8] & 0x3f) | 0x80 // Variant is 10.
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:]), nil
+}
+
+// registerSession checks invocation result stack for iterators and creates a
+// new session holding them if there are any. Iterators are replaced with
+// their identifiers in the resulting stack then. It returns false if there
+// are no iterators on the stack (and no session was created).
+func (s *Server) registerSession(res *result.Invoke, finalize func()) (bool, error) {
+	var iterators []*iteratorIdentifier
+	for i, item := range res.Stack {
+		if item.Type() != stackitem.InteropT || !iterator.IsIterator(item) {
+			continue
+		}
+		id, err := newSessionID()
+		if err != nil {
+			return false, fmt.Errorf("failed to generate iterator ID: %w", err)
+		}
+		iterators = append(iterators, &iteratorIdentifier{
+			ID:   id,
+			Item: item,
+		})
+		res.Stack[i] = stackitem.NewInterop(result.Iterator{ID: id})
+	}
+	if len(iterators) == 0 {
+		return false, nil
+	}
+	sessionID, err := newSessionID()
+	if err != nil {
+		return false, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	sess := &session{
+		iteratorIdentifiers: iterators,
+		finalize:            finalize,
+	}
+	s.sessionsLock.Lock()
+	if len(s.sessions) >= s.config.SessionPoolSize {
+		s.sessionsLock.Unlock()
+		return false, fmt.Errorf("max capacity reached: %d", s.config.SessionPoolSize)
+	}
+	sess.timer = time.AfterFunc(time.Second*time.Duration(s.config.SessionExpirationTime), func() {
+		s.sessionsLock.Lock()
+		defer s.sessionsLock.Unlock()
+		if len(s.sessions) == 0 {
+			return
+		}
+		sess, ok := s.sessions[sessionID]
+		if !ok {
+			return
+		}
+		sess.iteratorsLock.Lock()
+		sess.finalize()
+		delete(s.sessions, sessionID)
+		sess.iteratorsLock.Unlock()
+	})
+	s.sessions[sessionID] = sess
+	s.sessionsLock.Unlock()
+	res.Session = sessionID
+	return true, nil
+}
+
+// dropAllSessions finalizes and removes all active sessions, it's used on
+// server shutdown.
+func (s *Server) dropAllSessions() {
+	s.sessionsLock.Lock()
+	defer s.sessionsLock.Unlock()
+	for id, sess := range s.sessions {
+		sess.timer.Stop()
+		sess.iteratorsLock.Lock()
+		sess.finalize()
+		sess.iteratorsLock.Unlock()
+		delete(s.sessions, id)
+	}
+}
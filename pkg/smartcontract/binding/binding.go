@@ -0,0 +1,137 @@
+/*
+Package binding contains the code shared by contract binding generators. Every
+generator creates a Go package providing typed wrappers for the contract
+methods described by the contract manifest. Types used for method parameters
+and return values are taken from the manifest ABI and can be refined with type
+hints (e.g. a method returning ByteArray may actually return a String).
+*/
+package binding
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+type (
+	// Config contains parameters of the binding generator.
+	Config struct {
+		// Package is the name of the generated package.
+		Package string
+		// Manifest is the manifest of the contract to generate bindings for.
+		Manifest *manifest.Manifest
+		// Hash is the contract hash, it can be omitted for some generators.
+		Hash util.Uint160
+		// TypeHints contains types overriding the ones from the manifest ABI.
+		// Method return types are specified via "method" keys and types of
+		// method parameters via "method.parameter" keys.
+		TypeHints map[string]smartcontract.ParamType
+		// Output is where the generated code is written to.
+		Output io.Writer
+	}
+
+	// Method describes a contract method along with its Go name and types
+	// with type hints applied.
+	Method struct {
+		manifest.Method
+		// GoName is an exported Go identifier for the method.
+		GoName string
+		// Params contains method parameters with Go-compatible names.
+		Params []manifest.Parameter
+	}
+)
+
+// ParseTypeHints parses type hints specified as strings (e.g. in the contract
+// configuration file) into parameter types.
+func ParseTypeHints(hints map[string]string) (map[string]smartcontract.ParamType, error) {
+	res := make(map[string]smartcontract.ParamType, len(hints))
+	for k, v := range hints {
+		typ, err := smartcontract.ParseParamType(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid type hint for %s: %w", k, err)
+		}
+		res[k] = typ
+	}
+	return res, nil
+}
+
+// reservedNames are Go identifiers declared by the generated code itself.
+var reservedNames = map[string]bool{
+	"Hash": true,
+}
+
+// Methods returns all methods from the contract ABI except the ones starting
+// with an underscore. Overloaded methods and methods clashing with
+// reservedNames get the number of parameters appended to their Go names.
+func (c *Config) Methods() []Method {
+	var (
+		res    []Method
+		counts = make(map[string]int)
+	)
+	for _, m := range c.Manifest.ABI.Methods {
+		counts[m.Name]++
+	}
+	for _, m := range c.Manifest.ABI.Methods {
+		if strings.HasPrefix(m.Name, "_") {
+			continue
+		}
+		bm := Method{
+			Method: m,
+			GoName: exportedName(m.Name),
+			Params: make([]manifest.Parameter, len(m.Parameters)),
+		}
+		if counts[m.Name] > 1 || reservedNames[bm.GoName] {
+			bm.GoName += strconv.Itoa(len(m.Parameters))
+		}
+		if t, ok := c.TypeHints[m.Name]; ok {
+			bm.ReturnType = t
+		}
+		for i, p := range m.Parameters {
+			if t, ok := c.TypeHints[m.Name+"."+p.Name]; ok {
+				p.Type = t
+			}
+			p.Name = paramName(p.Name, i)
+			bm.Params[i] = p
+		}
+		res = append(res, bm)
+	}
+	return res
+}
+
+// exportedName converts the contract method name into an exported Go
+// identifier.
+func exportedName(name string) string {
+	var sb strings.Builder
+	for _, r := range name {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	res := []rune(sb.String())
+	if len(res) == 0 || !unicode.IsLetter(res[0]) {
+		res = append([]rune("Method"), res...)
+	}
+	res[0] = unicode.ToUpper(res[0])
+	return string(res)
+}
+
+// paramName returns the parameter name that can be used as a Go identifier
+// not clashing with the identifiers used by generated code.
+func paramName(name string, i int) string {
+	if !token.IsIdentifier(name) || name == "_" {
+		return "arg" + strconv.Itoa(i)
+	}
+	switch name {
+	case "c", "acc", "cosigners", "err", "script", "big", "client", "contract",
+		"emit", "interop", "io", "keys", "neogointernal", "stackitem", "unwrap", "util", "wallet":
+		return name + "Arg"
+	}
+	return name
+}
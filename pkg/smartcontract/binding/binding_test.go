@@ -0,0 +1,118 @@
+package binding
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMethods(t *testing.T) {
+	m := manifest.NewManifest("Test")
+	m.ABI.Methods = []manifest.Method{
+		{Name: "_deploy", ReturnType: smartcontract.VoidType},
+		{
+			Name: "get",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("type", smartcontract.IntegerType),
+				manifest.NewParameter("key", smartcontract.ByteArrayType),
+				manifest.NewParameter("util", smartcontract.Hash160Type),
+			},
+			ReturnType: smartcontract.ByteArrayType,
+			Safe:       true,
+		},
+		{Name: "put", ReturnType: smartcontract.VoidType},
+		{
+			Name:       "put",
+			Parameters: []manifest.Parameter{manifest.NewParameter("key", smartcontract.ByteArrayType)},
+			ReturnType: smartcontract.VoidType,
+		},
+		{Name: "1st-method", ReturnType: smartcontract.VoidType},
+		{Name: "hash", ReturnType: smartcontract.Hash160Type, Safe: true},
+	}
+	hints, err := ParseTypeHints(map[string]string{
+		"get":     "String",
+		"get.key": "PublicKey",
+	})
+	require.NoError(t, err)
+
+	cfg := Config{Manifest: m, TypeHints: hints}
+	ms := cfg.Methods()
+	require.Equal(t, 5, len(ms))
+
+	require.Equal(t, "Get", ms[0].GoName)
+	require.Equal(t, smartcontract.StringType, ms[0].ReturnType)
+	require.Equal(t, []manifest.Parameter{
+		manifest.NewParameter("arg0", smartcontract.IntegerType),
+		manifest.NewParameter("key", smartcontract.PublicKeyType),
+		manifest.NewParameter("utilArg", smartcontract.Hash160Type),
+	}, ms[0].Params)
+	// Original ABI is preserved.
+	require.Equal(t, smartcontract.ByteArrayType, m.ABI.Methods[1].ReturnType)
+	require.Equal(t, "type", m.ABI.Methods[1].Parameters[0].Name)
+
+	require.Equal(t, "Put0", ms[1].GoName)
+	require.Equal(t, "Put1", ms[2].GoName)
+	require.Equal(t, "Method1stmethod", ms[3].GoName)
+	// Hash constant is declared by the generated code.
+	require.Equal(t, "Hash0", ms[4].GoName)
+
+	_, err = ParseTypeHints(map[string]string{"get": "unknown"})
+	require.Error(t, err)
+}
+
+func TestGenerate(t *testing.T) {
+	m := manifest.NewManifest("Token")
+	m.ABI.Methods = []manifest.Method{
+		{
+			Name:       "symbol",
+			ReturnType: smartcontract.StringType,
+			Safe:       true,
+		},
+		{
+			Name: "balanceOf",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("account", smartcontract.Hash160Type),
+			},
+			ReturnType: smartcontract.IntegerType,
+			Safe:       true,
+		},
+		{
+			Name: "transfer",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("from", smartcontract.Hash160Type),
+				manifest.NewParameter("to", smartcontract.Hash160Type),
+				manifest.NewParameter("amount", smartcontract.IntegerType),
+				manifest.NewParameter("data", smartcontract.AnyType),
+			},
+			ReturnType: smartcontract.BoolType,
+		},
+		{
+			Name: "setOwner",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("owner", smartcontract.PublicKeyType),
+			},
+			ReturnType: smartcontract.VoidType,
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	cfg := Config{
+		Package:  "token",
+		Manifest: m,
+		Output:   buf,
+	}
+	require.Error(t, Generate(cfg))
+
+	cfg.Hash = util.Uint160{'a', 'b', 'c'}
+	require.NoError(t, Generate(cfg))
+
+	expected, err := ioutil.ReadFile(filepath.Join("testdata", "token", "token.go"))
+	require.NoError(t, err)
+	require.Equal(t, string(expected), buf.String())
+}
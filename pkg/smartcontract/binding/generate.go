@@ -0,0 +1,148 @@
+package binding
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+const srcTmpl = `// Code generated by neo-go contract generate-wrapper. DO NOT EDIT.
+
+// Package {{.Package}} contains wrappers for {{.Name}} contract.
+package {{.Package}}
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/interop"
+	"github.com/nspcc-dev/neo-go/pkg/interop/contract"
+)
+
+// Hash contains the hash of {{.Name}} contract in big-endian form.
+const Hash = "{{.Hash}}"
+{{range .Methods}}
+// {{.GoName}} invokes ` + "`{{.Name}}`" + ` method of {{$.Name}} contract.
+func {{.GoName}}({{params .Params}}){{if not (void .ReturnType)}} {{goType .ReturnType}}{{end}} {
+	{{if not (void .ReturnType)}}return {{end}}contract.Call(interop.Hash160(Hash), "{{.Name}}", {{flags .}}{{args .Params}}){{assert .ReturnType}}
+}
+{{end}}`
+
+var srcTemplate = template.Must(template.New("binding").Funcs(template.FuncMap{
+	"args":   args,
+	"assert": typeAssertion,
+	"flags": func(m Method) string {
+		if m.Safe {
+			return "contract.ReadOnly"
+		}
+		return "contract.All"
+	},
+	"goType": goType,
+	"params": params,
+	"void": func(t smartcontract.ParamType) bool {
+		return t == smartcontract.VoidType
+	},
+}).Parse(srcTmpl))
+
+// Generate writes Go code for the contract package wrapping calls of the
+// contract specified in cfg to cfg.Output. The package can then be imported
+// by other contracts to invoke this one. Calls of safe methods are performed
+// with ReadOnly flags and calls of other methods with All flags.
+func Generate(cfg Config) error {
+	if cfg.Hash.Equals(util.Uint160{}) {
+		return errors.New("contract hash is not specified")
+	}
+	var sb strings.Builder
+	for _, b := range cfg.Hash.BytesBE() {
+		sb.WriteString(fmt.Sprintf("\\x%02x", b))
+	}
+	data := struct {
+		Package string
+		Name    string
+		Hash    string
+		Methods []Method
+	}{
+		Package: cfg.Package,
+		Name:    cfg.Manifest.Name,
+		Hash:    sb.String(),
+		Methods: cfg.Methods(),
+	}
+
+	buf := new(bytes.Buffer)
+	if err := srcTemplate.Execute(buf, data); err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format generated code: %w", err)
+	}
+	_, err = cfg.Output.Write(src)
+	return err
+}
+
+// goType returns the interop Go type used for the values of the specified
+// type.
+func goType(typ smartcontract.ParamType) string {
+	switch typ {
+	case smartcontract.BoolType:
+		return "bool"
+	case smartcontract.IntegerType:
+		return "int"
+	case smartcontract.ByteArrayType:
+		return "[]byte"
+	case smartcontract.StringType:
+		return "string"
+	case smartcontract.Hash160Type:
+		return "interop.Hash160"
+	case smartcontract.Hash256Type:
+		return "interop.Hash256"
+	case smartcontract.PublicKeyType:
+		return "interop.PublicKey"
+	case smartcontract.SignatureType:
+		return "interop.Signature"
+	case smartcontract.ArrayType:
+		return "[]interface{}"
+	case smartcontract.MapType:
+		return "map[string]interface{}"
+	case smartcontract.InteropInterfaceType:
+		return "interop.Interface"
+	default:
+		return "interface{}"
+	}
+}
+
+// typeAssertion returns the type assertion converting the result of
+// contract.Call to the Go type corresponding to typ.
+func typeAssertion(typ smartcontract.ParamType) string {
+	switch typ {
+	case smartcontract.VoidType, smartcontract.AnyType:
+		return ""
+	default:
+		return ".(" + goType(typ) + ")"
+	}
+}
+
+// params returns the list of method parameters with their Go types.
+func params(ps []manifest.Parameter) string {
+	var buf bytes.Buffer
+	for i, p := range ps {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(p.Name + " " + goType(p.Type))
+	}
+	return buf.String()
+}
+
+// args returns the list of method arguments prefixed with a comma.
+func args(ps []manifest.Parameter) string {
+	var buf bytes.Buffer
+	for _, p := range ps {
+		buf.WriteString(", " + p.Name)
+	}
+	return buf.String()
+}
@@ -0,0 +1,262 @@
+package manifest
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Change describes a single difference between two versions of contract
+// manifest.
+type Change struct {
+	// Breaking is set if the change can break contract users.
+	Breaking bool
+	// Description is a human-readable description of the change.
+	Description string
+}
+
+// String implements fmt.Stringer interface.
+func (c Change) String() string {
+	if c.Breaking {
+		return "breaking: " + c.Description
+	}
+	return c.Description
+}
+
+// Diff compares manifest of the deployed contract with the one it's going to
+// be updated with and returns the list of changes. Changes that can break
+// other contracts or clients (like removed methods, changed method or event
+// signatures, removed groups or standards) are marked as breaking. Method
+// offsets, features and extra data are not compared.
+func Diff(old, upd *Manifest) []Change {
+	var d differ
+	if old.Name != upd.Name {
+		d.breaking("name changed from %q to %q, contract can't be updated", old.Name, upd.Name)
+	}
+	d.diffMethods(old.ABI.Methods, upd.ABI)
+	d.diffEvents(old.ABI.Events, upd.ABI)
+	d.diffGroups(old.Groups, upd.Groups)
+	d.diffStandards(old.SupportedStandards, upd.SupportedStandards)
+	d.diffPermissions(old.Permissions, upd.Permissions)
+	if !wildDescsEqual(old.Trusts, upd.Trusts) {
+		d.change("trusts changed from %s to %s", wildDescsString(old.Trusts), wildDescsString(upd.Trusts))
+	}
+	return d.changes
+}
+
+// HasBreaking checks whether there are breaking changes in the list.
+func HasBreaking(changes []Change) bool {
+	for i := range changes {
+		if changes[i].Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) breaking(format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{Breaking: true, Description: fmt.Sprintf(format, args...)})
+}
+
+func (d *differ) change(format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{Description: fmt.Sprintf(format, args...)})
+}
+
+func (d *differ) diffMethods(old []Method, upd ABI) {
+	for i := range old {
+		m := &old[i]
+		desc := fmt.Sprintf("method %s/%d", m.Name, len(m.Parameters))
+		n := upd.GetMethod(m.Name, len(m.Parameters))
+		if n == nil {
+			d.breaking("%s removed", desc)
+			continue
+		}
+		d.diffParameters(desc, m.Parameters, n.Parameters)
+		if m.ReturnType != n.ReturnType {
+			d.breaking("%s return type changed from %s to %s", desc, m.ReturnType, n.ReturnType)
+		}
+		if m.Safe && !n.Safe {
+			d.breaking("%s is not safe anymore", desc)
+		} else if !m.Safe && n.Safe {
+			d.change("%s is safe now", desc)
+		}
+	}
+	for i := range upd.Methods {
+		m := &upd.Methods[i]
+		if (&ABI{Methods: old}).GetMethod(m.Name, len(m.Parameters)) == nil {
+			d.change("method %s/%d added", m.Name, len(m.Parameters))
+		}
+	}
+}
+
+func (d *differ) diffEvents(old []Event, upd ABI) {
+	for i := range old {
+		e := &old[i]
+		desc := fmt.Sprintf("event %s", e.Name)
+		n := upd.GetEvent(e.Name)
+		if n == nil {
+			d.breaking("%s removed", desc)
+			continue
+		}
+		if len(e.Parameters) != len(n.Parameters) {
+			d.breaking("%s parameters count changed from %d to %d", desc, len(e.Parameters), len(n.Parameters))
+			continue
+		}
+		d.diffParameters(desc, e.Parameters, n.Parameters)
+	}
+	for i := range upd.Events {
+		if (&ABI{Events: old}).GetEvent(upd.Events[i].Name) == nil {
+			d.change("event %s added", upd.Events[i].Name)
+		}
+	}
+}
+
+// diffParameters compares parameters of the same number.
+func (d *differ) diffParameters(desc string, old, upd []Parameter) {
+	for i := range old {
+		if old[i].Type != upd[i].Type {
+			d.breaking("%s parameter #%d (%s) type changed from %s to %s",
+				desc, i, old[i].Name, old[i].Type, upd[i].Type)
+		}
+		if old[i].Name != upd[i].Name {
+			d.change("%s parameter #%d renamed from %s to %s", desc, i, old[i].Name, upd[i].Name)
+		}
+	}
+}
+
+func (d *differ) diffGroups(old, upd []Group) {
+	contains := func(gs []Group, g Group) bool {
+		for i := range gs {
+			if gs[i].PublicKey.Equal(g.PublicKey) {
+				return true
+			}
+		}
+		return false
+	}
+	for i := range old {
+		if !contains(upd, old[i]) {
+			d.breaking("group %s removed", hex.EncodeToString(old[i].PublicKey.Bytes()))
+		}
+	}
+	for i := range upd {
+		if !contains(old, upd[i]) {
+			d.change("group %s added", hex.EncodeToString(upd[i].PublicKey.Bytes()))
+		}
+	}
+}
+
+func (d *differ) diffStandards(old, upd []string) {
+	for _, s := range old {
+		if !containsString(upd, s) {
+			d.breaking("standard %s is not supported anymore", s)
+		}
+	}
+	for _, s := range upd {
+		if !containsString(old, s) {
+			d.change("standard %s is supported now", s)
+		}
+	}
+}
+
+// diffPermissions compares permissions of the contract. They only restrict
+// calls made by the contract itself, so changes are never breaking for
+// contract users.
+func (d *differ) diffPermissions(old, upd []Permission) {
+	find := func(ps []Permission, desc PermissionDesc) *Permission {
+		for i := range ps {
+			if ps[i].Contract.Type == desc.Type &&
+				(desc.Type == PermissionWildcard || ps[i].Contract.Equals(desc)) {
+				return &ps[i]
+			}
+		}
+		return nil
+	}
+	for i := range old {
+		p := &old[i]
+		n := find(upd, p.Contract)
+		if n == nil {
+			d.change("permission to call %s removed", descString(p.Contract))
+		} else if !wildStringsEqual(p.Methods, n.Methods) {
+			d.change("permission to call %s changed from %s to %s methods", descString(p.Contract),
+				wildStringsString(p.Methods), wildStringsString(n.Methods))
+		}
+	}
+	for i := range upd {
+		if find(old, upd[i].Contract) == nil {
+			d.change("permission to call %s %s methods added", descString(upd[i].Contract),
+				wildStringsString(upd[i].Methods))
+		}
+	}
+}
+
+func containsString(ss []string, s string) bool {
+	for i := range ss {
+		if ss[i] == s {
+			return true
+		}
+	}
+	return false
+}
+
+func descString(d PermissionDesc) string {
+	switch d.Type {
+	case PermissionHash:
+		return "contract 0x" + d.Hash().StringLE()
+	case PermissionGroup:
+		return "group " + hex.EncodeToString(d.Group().Bytes())
+	default:
+		return "any contract"
+	}
+}
+
+func wildStringsEqual(a, b WildStrings) bool {
+	if a.IsWildcard() || b.IsWildcard() {
+		return a.IsWildcard() == b.IsWildcard()
+	}
+	if len(a.Value) != len(b.Value) {
+		return false
+	}
+	for _, s := range a.Value {
+		if !b.Contains(s) {
+			return false
+		}
+	}
+	return true
+}
+
+func wildStringsString(c WildStrings) string {
+	if c.IsWildcard() {
+		return "all"
+	}
+	return "[" + strings.Join(c.Value, ", ") + "]"
+}
+
+func wildDescsEqual(a, b WildPermissionDescs) bool {
+	if a.IsWildcard() || b.IsWildcard() {
+		return a.IsWildcard() == b.IsWildcard()
+	}
+	if len(a.Value) != len(b.Value) {
+		return false
+	}
+	for _, v := range a.Value {
+		if !b.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func wildDescsString(c WildPermissionDescs) string {
+	if c.IsWildcard() {
+		return "all"
+	}
+	ss := make([]string, len(c.Value))
+	for i := range c.Value {
+		ss[i] = descString(c.Value[i])
+	}
+	return "[" + strings.Join(ss, ", ") + "]"
+}
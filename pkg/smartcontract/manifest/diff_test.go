@@ -0,0 +1,110 @@
+package manifest
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	priv1, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	priv2, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	newManifest := func() *Manifest {
+		m := DefaultManifest("Test")
+		m.ABI.Methods = []Method{
+			{
+				Name:       "transfer",
+				Parameters: []Parameter{NewParameter("from", smartcontract.Hash160Type), NewParameter("amount", smartcontract.IntegerType)},
+				ReturnType: smartcontract.BoolType,
+			},
+			{
+				Name:       "balanceOf",
+				Parameters: []Parameter{NewParameter("account", smartcontract.Hash160Type)},
+				ReturnType: smartcontract.IntegerType,
+				Safe:       true,
+			},
+			{Name: "update", ReturnType: smartcontract.VoidType},
+		}
+		m.ABI.Events = []Event{{
+			Name:       "Transfer",
+			Parameters: []Parameter{NewParameter("from", smartcontract.Hash160Type)},
+		}}
+		m.Groups = []Group{{PublicKey: priv1.PublicKey()}}
+		m.SupportedStandards = []string{NEP17StandardName}
+		return m
+	}
+
+	t.Run("same", func(t *testing.T) {
+		old, upd := newManifest(), newManifest()
+		upd.ABI.Methods[0].Offset = 42
+		require.Empty(t, Diff(old, upd))
+	})
+	t.Run("compatible", func(t *testing.T) {
+		old, upd := newManifest(), newManifest()
+		upd.ABI.Methods[0].Parameters[1].Name = "value"
+		upd.ABI.Methods[2].Safe = true
+		upd.ABI.Methods = append(upd.ABI.Methods, Method{Name: "mint", ReturnType: smartcontract.VoidType})
+		upd.ABI.Events = append(upd.ABI.Events, Event{Name: "Mint"})
+		upd.Groups = append(upd.Groups, Group{PublicKey: priv2.PublicKey()})
+		upd.SupportedStandards = append(upd.SupportedStandards, NEP17Payable)
+		upd.Permissions = []Permission{*NewPermission(PermissionHash, util.Uint160{1, 2, 3})}
+		upd.Permissions[0].Methods.Add("method")
+		upd.Trusts.Add(*newPermissionDesc(PermissionGroup, priv1.PublicKey()))
+
+		changes := Diff(old, upd)
+		require.False(t, HasBreaking(changes))
+		require.Equal(t, []Change{
+			{Description: "method transfer/2 parameter #1 renamed from amount to value"},
+			{Description: "method update/0 is safe now"},
+			{Description: "method mint/0 added"},
+			{Description: "event Mint added"},
+			{Description: "group " + hex.EncodeToString(priv2.PublicKey().Bytes()) + " added"},
+			{Description: "standard NEP-17-Payable is supported now"},
+			{Description: "permission to call any contract removed"},
+			{Description: "permission to call contract 0x" + util.Uint160{1, 2, 3}.StringLE() + " [method] methods added"},
+			{Description: "trusts changed from [] to [group " + hex.EncodeToString(priv1.PublicKey().Bytes()) + "]"},
+		}, changes)
+	})
+	t.Run("breaking", func(t *testing.T) {
+		old, upd := newManifest(), newManifest()
+		upd.Name = "Other"
+		upd.ABI.Methods[0].Parameters[1].Type = smartcontract.StringType
+		upd.ABI.Methods[0].ReturnType = smartcontract.VoidType
+		upd.ABI.Methods[1].Safe = false
+		upd.ABI.Methods = upd.ABI.Methods[:2]
+		upd.ABI.Events[0].Parameters = nil
+		upd.Groups = nil
+		upd.SupportedStandards = nil
+
+		changes := Diff(old, upd)
+		require.True(t, HasBreaking(changes))
+		for _, c := range changes {
+			require.True(t, c.Breaking, c.Description)
+		}
+		require.Equal(t, []string{
+			`breaking: name changed from "Test" to "Other", contract can't be updated`,
+			"breaking: method transfer/2 parameter #1 (amount) type changed from Integer to String",
+			"breaking: method transfer/2 return type changed from Boolean to Void",
+			"breaking: method balanceOf/1 is not safe anymore",
+			"breaking: method update/0 removed",
+			"breaking: event Transfer parameters count changed from 1 to 0",
+			"breaking: group " + hex.EncodeToString(priv1.PublicKey().Bytes()) + " removed",
+			"breaking: standard NEP-17 is not supported anymore",
+		}, changesStrings(changes))
+	})
+}
+
+func changesStrings(changes []Change) []string {
+	res := make([]string, len(changes))
+	for i := range changes {
+		res[i] = changes[i].String()
+	}
+	return res
+}
@@ -0,0 +1,280 @@
+/*
+Package rpcbinding generates Go packages wrapping contract methods for RPC
+clients. Safe contract methods are wrapped into test invocations returning Go
+values of the types specified in the contract ABI, other methods are wrapped
+into functions creating, signing and sending transactions invoking them.
+*/
+package rpcbinding
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/binding"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+const srcTmpl = `// Code generated by neo-go contract generate-rpcwrapper. DO NOT EDIT.
+
+// Package {{.Package}} contains RPC wrappers for {{.Name}} contract.
+package {{.Package}}
+
+import (
+{{range .StdImports}}	"{{.}}"
+{{end}}
+{{range .Imports}}	"{{.}}"
+{{end}})
+{{if .Hash}}
+// Hash contains the hash of {{.Name}} contract.
+var Hash = {{.Hash}}
+{{end}}
+// Client is a wrapper over RPC client providing typed access to {{.Name}}
+// contract methods.
+type Client struct {
+	c    *client.Client
+	hash util.Uint160
+}
+
+// New creates a new Client for the contract with the specified hash using c
+// for RPC calls. c must be initialized to send transactions.
+func New(c *client.Client, hash util.Uint160) *Client {
+	return &Client{c: c, hash: hash}
+}
+
+// script creates a script invoking the specified contract method.
+func (c *Client) script(method string, args ...interface{}) ([]byte, error) {
+	w := io.NewBufBinWriter()
+	emit.AppCall(w.BinWriter, c.hash, method, callflag.All, args...)
+	if w.Err != nil {
+		return nil, w.Err
+	}
+	return w.Bytes(), nil
+}
+{{if .HasSafe}}
+// invoke performs a test invocation of the specified contract method.
+func (c *Client) invoke(method string, args ...interface{}) (*result.Invoke, error) {
+	script, err := c.script(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return c.c.InvokeScript(script, nil)
+}
+{{end}}{{if .HasUnsafe}}
+// send creates a transaction invoking the specified contract method, signs it
+// with the given account and cosigners and sends it to the network.
+func (c *Client) send(acc *wallet.Account, cosigners []client.SignerAccount, method string, args ...interface{}) (util.Uint256, error) {
+	script, err := c.script(method, args...)
+	if err != nil {
+		return util.Uint256{}, err
+	}
+	return c.c.SignAndPushInvocationTx(script, acc, -1, 0, cosigners)
+}
+{{end}}{{range .Methods}}{{if .Safe}}
+// {{.GoName}} invokes ` + "`{{.Name}}`" + ` method of the contract.
+func (c *Client) {{.GoName}}({{params .Params}}) {{if void .ReturnType}}error{{else}}({{goType .ReturnType}}, error){{end}} {
+	return unwrap.{{unwrap .ReturnType}}(c.invoke("{{.Name}}"{{args .Params}}))
+}
+{{else}}
+// {{.GoName}} creates a transaction invoking ` + "`{{.Name}}`" + ` method of the contract,
+// signs it with the given account and cosigners and sends it to the network
+// returning its hash.
+func (c *Client) {{.GoName}}(acc *wallet.Account, cosigners []client.SignerAccount{{if .Params}}, {{params .Params}}{{end}}) (util.Uint256, error) {
+	return c.send(acc, cosigners, "{{.Name}}"{{args .Params}})
+}
+{{end}}{{end}}`
+
+type tmplData struct {
+	Package    string
+	Name       string
+	Hash       string
+	StdImports []string
+	Imports    []string
+	HasSafe    bool
+	HasUnsafe  bool
+	Methods    []binding.Method
+}
+
+var srcTemplate = template.Must(template.New("rpcbinding").Funcs(template.FuncMap{
+	"args":   args,
+	"goType": goType,
+	"params": params,
+	"unwrap": unwrapFunc,
+	"void": func(t smartcontract.ParamType) bool {
+		return t == smartcontract.VoidType
+	},
+}).Parse(srcTmpl))
+
+// Generate writes Go code for the RPC binding of the contract specified in
+// cfg to cfg.Output.
+func Generate(cfg binding.Config) error {
+	data := tmplData{
+		Package: cfg.Package,
+		Name:    cfg.Manifest.Name,
+		Methods: cfg.Methods(),
+	}
+	if !cfg.Hash.Equals(util.Uint160{}) {
+		data.Hash = fmt.Sprintf("%#v", cfg.Hash)
+	}
+	imports := map[string]bool{
+		"github.com/nspcc-dev/neo-go/pkg/io":                     true,
+		"github.com/nspcc-dev/neo-go/pkg/rpc/client":             true,
+		"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag": true,
+		"github.com/nspcc-dev/neo-go/pkg/util":                   true,
+		"github.com/nspcc-dev/neo-go/pkg/vm/emit":                true,
+	}
+	for _, m := range data.Methods {
+		if m.Safe {
+			data.HasSafe = true
+			if m.ReturnType != smartcontract.VoidType {
+				addImport(imports, goType(m.ReturnType))
+			}
+		} else {
+			data.HasUnsafe = true
+		}
+		for _, p := range m.Params {
+			if p.Type == smartcontract.MapType || p.Type == smartcontract.InteropInterfaceType {
+				return fmt.Errorf("method %s: parameter %s of %s type can't be passed via RPC wrapper", m.Name, p.Name, p.Type)
+			}
+			addImport(imports, paramGoType(p.Type))
+		}
+	}
+	if data.HasSafe {
+		imports["github.com/nspcc-dev/neo-go/pkg/rpc/client/unwrap"] = true
+		imports["github.com/nspcc-dev/neo-go/pkg/rpc/response/result"] = true
+	}
+	if data.HasUnsafe {
+		imports["github.com/nspcc-dev/neo-go/pkg/wallet"] = true
+	}
+	for imp := range imports {
+		if strings.Contains(imp, ".") {
+			data.Imports = append(data.Imports, imp)
+		} else {
+			data.StdImports = append(data.StdImports, imp)
+		}
+	}
+	sort.Strings(data.StdImports)
+	sort.Strings(data.Imports)
+
+	buf := new(bytes.Buffer)
+	if err := srcTemplate.Execute(buf, data); err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format generated code: %w", err)
+	}
+	_, err = cfg.Output.Write(src)
+	return err
+}
+
+// addImport marks the package needed for the specified Go type as imported.
+func addImport(imports map[string]bool, typ string) {
+	switch {
+	case strings.Contains(typ, "big."):
+		imports["math/big"] = true
+	case strings.Contains(typ, "keys."):
+		imports["github.com/nspcc-dev/neo-go/pkg/crypto/keys"] = true
+	case strings.Contains(typ, "stackitem."):
+		imports["github.com/nspcc-dev/neo-go/pkg/vm/stackitem"] = true
+	}
+}
+
+// goType returns the Go type used for the values of the specified type.
+func goType(typ smartcontract.ParamType) string {
+	switch typ {
+	case smartcontract.BoolType:
+		return "bool"
+	case smartcontract.IntegerType:
+		return "*big.Int"
+	case smartcontract.ByteArrayType, smartcontract.SignatureType:
+		return "[]byte"
+	case smartcontract.StringType:
+		return "string"
+	case smartcontract.Hash160Type:
+		return "util.Uint160"
+	case smartcontract.Hash256Type:
+		return "util.Uint256"
+	case smartcontract.PublicKeyType:
+		return "*keys.PublicKey"
+	case smartcontract.ArrayType:
+		return "[]stackitem.Item"
+	case smartcontract.MapType:
+		return "*stackitem.Map"
+	default:
+		return "stackitem.Item"
+	}
+}
+
+// paramGoType returns the Go type used for the method parameter of the
+// specified type. Arrays and Any values are passed as values supported by
+// emit.Array, Map and InteropInterface parameters are not supported.
+func paramGoType(typ smartcontract.ParamType) string {
+	switch typ {
+	case smartcontract.ArrayType:
+		return "[]interface{}"
+	case smartcontract.AnyType:
+		return "interface{}"
+	default:
+		return goType(typ)
+	}
+}
+
+// unwrapFunc returns the name of the unwrap package function converting the
+// result of the specified type.
+func unwrapFunc(typ smartcontract.ParamType) string {
+	switch typ {
+	case smartcontract.VoidType:
+		return "Nothing"
+	case smartcontract.BoolType:
+		return "Bool"
+	case smartcontract.IntegerType:
+		return "BigInt"
+	case smartcontract.ByteArrayType, smartcontract.SignatureType:
+		return "Bytes"
+	case smartcontract.StringType:
+		return "String"
+	case smartcontract.Hash160Type:
+		return "Uint160"
+	case smartcontract.Hash256Type:
+		return "Uint256"
+	case smartcontract.PublicKeyType:
+		return "PublicKey"
+	case smartcontract.ArrayType:
+		return "Array"
+	case smartcontract.MapType:
+		return "Map"
+	default:
+		return "Item"
+	}
+}
+
+// params returns the list of method parameters with their Go types.
+func params(ps []manifest.Parameter) string {
+	var buf bytes.Buffer
+	for i, p := range ps {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(p.Name + " " + paramGoType(p.Type))
+	}
+	return buf.String()
+}
+
+// args returns the list of method arguments (prefixed with a comma) converted
+// to the types supported by emit.Array.
+func args(ps []manifest.Parameter) string {
+	var buf bytes.Buffer
+	for _, p := range ps {
+		buf.WriteString(", " + p.Name)
+		if p.Type == smartcontract.PublicKeyType {
+			buf.WriteString(".Bytes()")
+		}
+	}
+	return buf.String()
+}
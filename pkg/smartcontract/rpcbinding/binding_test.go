@@ -0,0 +1,111 @@
+package rpcbinding
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/binding"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func testManifest() *manifest.Manifest {
+	m := manifest.NewManifest("Token")
+	m.ABI.Methods = []manifest.Method{
+		{
+			Name:       "symbol",
+			ReturnType: smartcontract.ByteArrayType,
+			Safe:       true,
+		},
+		{
+			Name: "balanceOf",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("account", smartcontract.Hash160Type),
+			},
+			ReturnType: smartcontract.IntegerType,
+			Safe:       true,
+		},
+		{
+			Name: "transfer",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("from", smartcontract.Hash160Type),
+				manifest.NewParameter("to", smartcontract.Hash160Type),
+				manifest.NewParameter("amount", smartcontract.IntegerType),
+				manifest.NewParameter("data", smartcontract.AnyType),
+			},
+			ReturnType: smartcontract.BoolType,
+		},
+		{
+			Name: "keys",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("type", smartcontract.IntegerType),
+			},
+			ReturnType: smartcontract.ArrayType,
+			Safe:       true,
+		},
+		{
+			Name: "setOwner",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("owner", smartcontract.PublicKeyType),
+			},
+			ReturnType: smartcontract.VoidType,
+		},
+		{
+			Name: "setOwner",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("owner", smartcontract.PublicKeyType),
+				manifest.NewParameter("data", smartcontract.ArrayType),
+			},
+			ReturnType: smartcontract.VoidType,
+		},
+		{
+			Name:       "verify",
+			ReturnType: smartcontract.BoolType,
+			Safe:       true,
+		},
+		{
+			Name: "_deploy",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("data", smartcontract.AnyType),
+				manifest.NewParameter("isUpdate", smartcontract.BoolType),
+			},
+			ReturnType: smartcontract.VoidType,
+		},
+	}
+	return m
+}
+
+func TestGenerate(t *testing.T) {
+	hints, err := binding.ParseTypeHints(map[string]string{"symbol": "String"})
+	require.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	cfg := binding.Config{
+		Package:   "token",
+		Manifest:  testManifest(),
+		Hash:      util.Uint160{1, 2, 3},
+		TypeHints: hints,
+		Output:    buf,
+	}
+	require.NoError(t, Generate(cfg))
+
+	expected, err := ioutil.ReadFile(filepath.Join("testdata", "token", "token.go"))
+	require.NoError(t, err)
+	require.Equal(t, string(expected), buf.String())
+
+	t.Run("bad hint", func(t *testing.T) {
+		_, err := binding.ParseTypeHints(map[string]string{"symbol": "Str"})
+		require.Error(t, err)
+	})
+	t.Run("map parameter", func(t *testing.T) {
+		hints, err := binding.ParseTypeHints(map[string]string{"transfer.data": "Map"})
+		require.NoError(t, err)
+		cfg.TypeHints = hints
+		cfg.Output = new(bytes.Buffer)
+		require.Error(t, Generate(cfg))
+	})
+}
@@ -11,18 +11,24 @@ import (
 	"io/ioutil"
 	"math/big"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/abiosoft/ishell/v2"
 	"github.com/abiosoft/readline"
 	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/util/slice"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
@@ -30,14 +36,17 @@ import (
 )
 
 const (
-	vmKey       = "vm"
-	manifestKey = "manifest"
-	boolType    = "bool"
-	boolFalse   = "false"
-	boolTrue    = "true"
-	intType     = "int"
-	stringType  = "string"
-	exitFunc    = "exitFunc"
+	vmKey        = "vm"
+	manifestKey  = "manifest"
+	debugInfoKey = "debugInfo"
+	chainKey     = "chain"
+	finalizeKey  = "finalize"
+	boolType     = "bool"
+	boolFalse    = "false"
+	boolTrue     = "true"
+	intType      = "int"
+	stringType   = "string"
+	exitFunc     = "exitFunc"
 )
 
 var commands = []*ishell.Cmd{
@@ -56,11 +65,38 @@ var commands = []*ishell.Cmd{
 	{
 		Name: "break",
 		Help: "Place a breakpoint",
-		LongHelp: `Usage: break <ip>
-<ip> is mandatory parameter, example:
-> break 12`,
+		LongHelp: `Usage: break <ip> | <file>:<line> | <method>
+<ip> is an instruction number, <file>:<line> and <method> can only be used
+        if the script is loaded with debug information (via loadgo or loadnef
+        with debug information file); <file> can be either a full path or a
+        file name, <method> is a method name from the manifest or from the
+        source code. Example:
+> break 12
+> break contract.go:42
+> break transfer`,
 		Func: handleBreak,
 	},
+	{
+		Name: "list",
+		Help: "Show source code around the current instruction",
+		LongHelp: `Usage: list [<n>]
+<n> is optional parameter to specify number of lines to show before and after
+        the current one (5 by default). Requires debug information, example:
+> list 10`,
+		Func: handleList,
+	},
+	{
+		Name:     "locals",
+		Help:     "Show local variables of the current method",
+		LongHelp: "Show local variables of the current method with their names and types (requires debug information)",
+		Func:     handleVariables,
+	},
+	{
+		Name:     "args",
+		Help:     "Show arguments of the current method",
+		LongHelp: "Show arguments of the current method with their names and types (requires debug information)",
+		Func:     handleVariables,
+	},
 	{
 		Name:     "estack",
 		Help:     "Show evaluation stack contents",
@@ -94,9 +130,10 @@ var commands = []*ishell.Cmd{
 	{
 		Name: "loadnef",
 		Help: "Load a NEF-consistent script into the VM",
-		LongHelp: `Usage: loadnef <file> <manifest>
-both parameters are mandatory, example:
-> loadnef /path/to/script.nef /path/to/manifest.json`,
+		LongHelp: `Usage: loadnef <file> <manifest> [<debug>]
+<file> and <manifest> parameters are mandatory, <debug> is an optional
+        debug information file, example:
+> loadnef /path/to/script.nef /path/to/manifest.json /path/to/script.debug.json`,
 		Func: handleLoadNEF,
 	},
 	{
@@ -123,6 +160,17 @@ both parameters are mandatory, example:
 > loadgo /path/to/file.go`,
 		Func: handleLoadGo,
 	},
+	{
+		Name: "loadtx",
+		Help: "Load a script of the transaction from the chain into the VM",
+		LongHelp: `Usage: loadtx <hash>
+<hash> is mandatory parameter, the transaction is loaded with the chain state
+        it was executed with (if historic states are available), example:
+> loadtx 0xc54bb6e7ddaee4e8f1bfe3c1fbd1f9c0c4f2e1ab8d2c4e7f4cd9b4a1ce2f0b7d
+
+Requires VM to be started with the node configuration.`,
+		Func: handleLoadTx,
+	},
 	{
 		Name: "parse",
 		Help: "Parse provided argument and convert it into other possible formats",
@@ -211,6 +259,8 @@ var (
 type VMCLI struct {
 	vm    *vm.VM
 	shell *ishell.Shell
+	// finalize releases the interop context of the current VM.
+	finalize func()
 	// printLogo specifies if logo is printed.
 	printLogo bool
 }
@@ -224,13 +274,34 @@ func New() *VMCLI {
 
 // NewWithConfig returns new VMCLI instance using provided config.
 func NewWithConfig(printLogo bool, onExit func(int), c *readline.Config) *VMCLI {
+	return newVMCLI(printLogo, onExit, c, vm.New(), nil, func() {})
+}
+
+// NewWithChain returns new VMCLI instance using provided config that executes
+// scripts with the interop context over the given chain state. The chain is
+// never changed, all state modifications made by scripts are discarded.
+func NewWithChain(printLogo bool, onExit func(int), c *readline.Config, chain *core.Blockchain) (*VMCLI, error) {
+	b, err := getFakeNextBlock(chain)
+	if err != nil {
+		return nil, fmt.Errorf("can't create next block: %w", err)
+	}
+	v, finalize := chain.GetTestVM(trigger.Application, nil, b)
+	return newVMCLI(printLogo, onExit, c, v, chain, finalize), nil
+}
+
+func newVMCLI(printLogo bool, onExit func(int), c *readline.Config, v *vm.VM, chain *core.Blockchain, finalize func()) *VMCLI {
 	vmcli := VMCLI{
-		vm:        vm.New(),
+		vm:        v,
 		shell:     ishell.NewWithConfig(c),
+		finalize:  finalize,
 		printLogo: printLogo,
 	}
-	vmcli.shell.Set(vmKey, vmcli.vm)
+	// Load commands replace the VM and its finalizer, so pointers are stored.
+	vmcli.shell.Set(vmKey, &vmcli.vm)
+	vmcli.shell.Set(chainKey, chain)
+	vmcli.shell.Set(finalizeKey, &vmcli.finalize)
 	vmcli.shell.Set(manifestKey, new(manifest.Manifest))
+	vmcli.shell.Set(debugInfoKey, new(*compiler.DebugInfo))
 	vmcli.shell.Set(exitFunc, onExit)
 	for _, c := range commands {
 		vmcli.shell.AddCmd(c)
@@ -240,7 +311,7 @@ func NewWithConfig(printLogo bool, onExit func(int), c *readline.Config) *VMCLI
 }
 
 func getVMFromContext(c *ishell.Context) *vm.VM {
-	return c.Get(vmKey).(*vm.VM)
+	return *c.Get(vmKey).(**vm.VM)
 }
 
 func getManifestFromContext(c *ishell.Context) *manifest.Manifest {
@@ -252,6 +323,72 @@ func setManifestInContext(c *ishell.Context, m *manifest.Manifest) {
 	*old = *m
 }
 
+func getChainFromContext(c *ishell.Context) *core.Blockchain {
+	return c.Get(chainKey).(*core.Blockchain)
+}
+
+// resetVM returns VM to load a new script into. If the shell works over a
+// chain, it's a new VM with interop context created for the given transaction
+// and block (the state after the previous block is used then if it's
+// available) or for the fake next block if b is nil (with the latest state),
+// the previous interop context is finalized. Otherwise the current VM is
+// returned.
+func resetVM(c *ishell.Context, tx *transaction.Transaction, b *block.Block) (*vm.VM, error) {
+	chain := getChainFromContext(c)
+	if chain == nil {
+		return getVMFromContext(c), nil
+	}
+	var (
+		v        *vm.VM
+		finalize func()
+		err      error
+	)
+	if b == nil {
+		b, err = getFakeNextBlock(chain)
+		if err != nil {
+			return nil, fmt.Errorf("can't create next block: %w", err)
+		}
+		v, finalize = chain.GetTestVM(trigger.Application, tx, b)
+	} else if chain.GetConfig().KeepOnlyLatestState || b.Index == 0 {
+		c.Println("historic state is not available, using the latest one")
+		v, finalize = chain.GetTestVM(trigger.Application, tx, b)
+	} else {
+		v, finalize, err = chain.GetTestHistoricVM(trigger.Application, tx, b)
+		if err != nil {
+			return nil, fmt.Errorf("can't create VM: %w", err)
+		}
+	}
+	old := c.Get(finalizeKey).(*func())
+	(*old)()
+	*old = finalize
+	*c.Get(vmKey).(**vm.VM) = v
+	return v, nil
+}
+
+// getFakeNextBlock returns a block following the current chain's top one to
+// be used as a persisting block for script execution.
+func getFakeNextBlock(chain *core.Blockchain) (*block.Block, error) {
+	cfg := chain.GetConfig()
+	b := block.New(cfg.StateRootInHeader)
+	b.Index = chain.BlockHeight() + 1
+	hdr, err := chain.GetHeader(chain.GetHeaderHash(int(b.Index - 1)))
+	if err != nil {
+		return nil, err
+	}
+	b.Timestamp = hdr.Timestamp + uint64(cfg.SecondsPerBlock*int(time.Second/time.Millisecond))
+	return b, nil
+}
+
+func getDebugInfoFromContext(c *ishell.Context) *compiler.DebugInfo {
+	return *c.Get(debugInfoKey).(**compiler.DebugInfo)
+}
+
+// setDebugInfoInContext updates the debug info stored in the shell. Every
+// command gets a copy of the shell values, so it's kept by pointer.
+func setDebugInfoInContext(c *ishell.Context, di *compiler.DebugInfo) {
+	*c.Get(debugInfoKey).(**compiler.DebugInfo) = di
+}
+
 func checkVMIsReady(c *ishell.Context) bool {
 	v := getVMFromContext(c)
 	if v == nil || !v.Ready() {
@@ -291,8 +428,16 @@ func handleBreak(c *ishell.Context) {
 	}
 	n, err := strconv.Atoi(c.Args[0])
 	if err != nil {
-		c.Err(fmt.Errorf("%w: %v", ErrInvalidParameter, err))
-		return
+		di := getDebugInfoFromContext(c)
+		if di == nil {
+			c.Err(fmt.Errorf("%w: %v", ErrInvalidParameter, err))
+			return
+		}
+		n, err = getBreakpointOffset(di, c.Args[0])
+		if err != nil {
+			c.Err(fmt.Errorf("%w: %v", ErrInvalidParameter, err))
+			return
+		}
 	}
 
 	v.AddBreakPoint(n)
@@ -337,11 +482,24 @@ func handleSlots(c *ishell.Context) {
 }
 
 func handleLoadNEF(c *ishell.Context) {
-	v := getVMFromContext(c)
 	if len(c.Args) < 2 {
 		c.Err(fmt.Errorf("%w: <file> <manifest>", ErrMissingParameter))
 		return
 	}
+	var di *compiler.DebugInfo
+	if len(c.Args) > 2 {
+		var err error
+		di, err = getDebugInfoFromFile(c.Args[2])
+		if err != nil {
+			c.Err(err)
+			return
+		}
+	}
+	v, err := resetVM(c, nil, nil)
+	if err != nil {
+		c.Err(err)
+		return
+	}
 	if err := v.LoadFileWithFlags(c.Args[0], callflag.All); err != nil {
 		c.Err(err)
 		return
@@ -353,11 +511,11 @@ func handleLoadNEF(c *ishell.Context) {
 	}
 	c.Printf("READY: loaded %d instructions\n", v.Context().LenInstr())
 	setManifestInContext(c, m)
+	setDebugInfoInContext(c, di)
 	changePrompt(c, v)
 }
 
 func handleLoadBase64(c *ishell.Context) {
-	v := getVMFromContext(c)
 	if len(c.Args) < 1 {
 		c.Err(fmt.Errorf("%w: <string>", ErrMissingParameter))
 		return
@@ -367,13 +525,18 @@ func handleLoadBase64(c *ishell.Context) {
 		c.Err(fmt.Errorf("%w: %v", ErrInvalidParameter, err))
 		return
 	}
+	v, err := resetVM(c, nil, nil)
+	if err != nil {
+		c.Err(err)
+		return
+	}
 	v.LoadWithFlags(b, callflag.All)
 	c.Printf("READY: loaded %d instructions\n", v.Context().LenInstr())
+	setDebugInfoInContext(c, nil)
 	changePrompt(c, v)
 }
 
 func handleLoadHex(c *ishell.Context) {
-	v := getVMFromContext(c)
 	if len(c.Args) < 1 {
 		c.Err(fmt.Errorf("%w: <string>", ErrMissingParameter))
 		return
@@ -383,13 +546,18 @@ func handleLoadHex(c *ishell.Context) {
 		c.Err(fmt.Errorf("%w: %v", ErrInvalidParameter, err))
 		return
 	}
+	v, err := resetVM(c, nil, nil)
+	if err != nil {
+		c.Err(err)
+		return
+	}
 	v.LoadWithFlags(b, callflag.All)
 	c.Printf("READY: loaded %d instructions\n", v.Context().LenInstr())
+	setDebugInfoInContext(c, nil)
 	changePrompt(c, v)
 }
 
 func handleLoadGo(c *ishell.Context) {
-	v := getVMFromContext(c)
 	if len(c.Args) < 1 {
 		c.Err(fmt.Errorf("%w: <file>", ErrMissingParameter))
 		return
@@ -406,13 +574,57 @@ func handleLoadGo(c *ishell.Context) {
 		c.Err(fmt.Errorf("can't create manifest: %w", err))
 		return
 	}
+	v, err := resetVM(c, nil, nil)
+	if err != nil {
+		c.Err(err)
+		return
+	}
 	setManifestInContext(c, m)
+	setDebugInfoInContext(c, di)
 
 	v.LoadWithFlags(b, callflag.All)
 	c.Printf("READY: loaded %d instructions\n", v.Context().LenInstr())
 	changePrompt(c, v)
 }
 
+func handleLoadTx(c *ishell.Context) {
+	chain := getChainFromContext(c)
+	if chain == nil {
+		c.Err(errors.New("no chain available, start VM with the node configuration to use it"))
+		return
+	}
+	if len(c.Args) < 1 {
+		c.Err(fmt.Errorf("%w: <hash>", ErrMissingParameter))
+		return
+	}
+	h, err := util.Uint256DecodeStringLE(strings.TrimPrefix(c.Args[0], "0x"))
+	if err != nil {
+		c.Err(fmt.Errorf("%w: %v", ErrInvalidParameter, err))
+		return
+	}
+	tx, height, err := chain.GetTransaction(h)
+	if err != nil {
+		c.Err(fmt.Errorf("can't get transaction: %w", err))
+		return
+	}
+	b, err := chain.GetBlock(chain.GetHeaderHash(int(height)))
+	if err != nil {
+		c.Err(fmt.Errorf("can't get block %d: %w", height, err))
+		return
+	}
+	v, err := resetVM(c, tx, b)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+	v.LoadWithFlags(tx.Script, callflag.All)
+	v.GasLimit = tx.SystemFee
+	c.Printf("READY: loaded %d instructions\n", v.Context().LenInstr())
+	setManifestInContext(c, new(manifest.Manifest))
+	setDebugInfoInContext(c, nil)
+	changePrompt(c, v)
+}
+
 func getManifestFromFile(name string) (*manifest.Manifest, error) {
 	bs, err := ioutil.ReadFile(name)
 	if err != nil {
@@ -426,6 +638,196 @@ func getManifestFromFile(name string) (*manifest.Manifest, error) {
 	return &m, nil
 }
 
+func getDebugInfoFromFile(name string) (*compiler.DebugInfo, error) {
+	bs, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't read debug info", ErrInvalidParameter)
+	}
+
+	var di compiler.DebugInfo
+	if err := json.Unmarshal(bs, &di); err != nil {
+		return nil, fmt.Errorf("%w: can't unmarshal debug info", ErrInvalidParameter)
+	}
+	return &di, nil
+}
+
+// getBreakpointOffset returns instruction offset for <file>:<line> or <method>
+// breakpoint specification using the given debug information.
+func getBreakpointOffset(di *compiler.DebugInfo, spec string) (int, error) {
+	if i := strings.LastIndexByte(spec, ':'); i >= 0 {
+		line, err := strconv.Atoi(spec[i+1:])
+		if err != nil {
+			return 0, fmt.Errorf("invalid line: %w", err)
+		}
+		file := filepath.Clean(spec[:i])
+		docs := make(map[int]bool)
+		for j, doc := range di.Documents {
+			doc = filepath.Clean(doc)
+			if doc == file || strings.HasSuffix(doc, string(filepath.Separator)+file) {
+				docs[j] = true
+			}
+		}
+		if len(docs) == 0 {
+			return 0, fmt.Errorf("unknown file %s", spec[:i])
+		}
+		offset := -1
+		for _, m := range di.Methods {
+			for _, sp := range m.SeqPoints {
+				if docs[sp.Document] && sp.StartLine == line && (offset < 0 || sp.Opcode < offset) {
+					offset = sp.Opcode
+				}
+			}
+		}
+		if offset < 0 {
+			return 0, fmt.Errorf("no code at %s", spec)
+		}
+		return offset, nil
+	}
+	for _, m := range di.Methods {
+		if m.ID == spec || m.Name.Name == spec {
+			return int(m.Range.Start), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown method %s", spec)
+}
+
+// getCurrentMethod returns debug information of the method containing the
+// current instruction along with the last sequence point preceding it (if any).
+func getCurrentMethod(c *ishell.Context) (*compiler.MethodDebugInfo, *compiler.DebugSeqPoint, error) {
+	di := getDebugInfoFromContext(c)
+	if di == nil {
+		return nil, nil, errors.New("no debug information available")
+	}
+	ip := getVMFromContext(c).Context().NextIP()
+	for i := range di.Methods {
+		m := &di.Methods[i]
+		if ip < int(m.Range.Start) || ip > int(m.Range.End) {
+			continue
+		}
+		var sp *compiler.DebugSeqPoint
+		for j := range m.SeqPoints {
+			if m.SeqPoints[j].Opcode <= ip && (sp == nil || m.SeqPoints[j].Opcode >= sp.Opcode) {
+				sp = &m.SeqPoints[j]
+			}
+		}
+		return m, sp, nil
+	}
+	return nil, nil, fmt.Errorf("no method found for instruction %d", ip)
+}
+
+func handleList(c *ishell.Context) {
+	if !checkVMIsReady(c) {
+		return
+	}
+	n := 5
+	if len(c.Args) > 0 {
+		var err error
+		n, err = strconv.Atoi(c.Args[0])
+		if err != nil || n < 0 {
+			c.Err(fmt.Errorf("%w: invalid number of lines", ErrInvalidParameter))
+			return
+		}
+	}
+	m, sp, err := getCurrentMethod(c)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+	di := getDebugInfoFromContext(c)
+	if sp == nil || sp.Document >= len(di.Documents) {
+		c.Err(fmt.Errorf("no source code available for method %s", m.ID))
+		return
+	}
+	doc := di.Documents[sp.Document]
+	src, err := ioutil.ReadFile(doc)
+	if err != nil {
+		c.Err(fmt.Errorf("can't read source file: %w", err))
+		return
+	}
+	lines := strings.Split(string(src), "\n")
+	c.Printf("%s:%d (%s)\n", doc, sp.StartLine, m.ID)
+	for i := sp.StartLine - n; i <= sp.StartLine+n; i++ {
+		if i < 1 || i > len(lines) {
+			continue
+		}
+		marker := "  "
+		if i >= sp.StartLine && i <= sp.EndLine {
+			marker = "=>"
+		}
+		c.Printf("%s %4d\t%s\n", marker, i, lines[i-1])
+	}
+}
+
+func handleVariables(c *ishell.Context) {
+	if !checkVMIsReady(c) {
+		return
+	}
+	m, _, err := getCurrentMethod(c)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+	ctx := getVMFromContext(c).Context()
+	var (
+		names  []string
+		types  []string
+		slots  []int
+		values []stackitem.Item
+	)
+	switch c.Cmd.Name {
+	case "locals":
+		values = ctx.LocalSlotItems()
+		for i, v := range m.Variables {
+			// Variables are stored as "name,type[,index]", slot index
+			// can be missing in debug information produced by older
+			// compiler versions.
+			parts := strings.Split(v, ",")
+			idx := i
+			if len(parts) > 2 {
+				idx, err = strconv.Atoi(parts[2])
+				if err != nil {
+					c.Err(fmt.Errorf("invalid variable %s: %w", v, err))
+					return
+				}
+			}
+			var typ string
+			if len(parts) > 1 {
+				typ = parts[1]
+			}
+			names = append(names, parts[0])
+			types = append(types, typ)
+			slots = append(slots, idx)
+		}
+	case "args":
+		values = ctx.ArgumentsSlotItems()
+		var offset int
+		if !m.IsFunction && len(values) > len(m.Parameters) {
+			// Method receiver is passed as the first argument.
+			offset = 1
+		}
+		for i, p := range m.Parameters {
+			names = append(names, p.Name)
+			types = append(types, p.Type)
+			slots = append(slots, i+offset)
+		}
+	default:
+		c.Err(errors.New("unknown variables kind"))
+		return
+	}
+	for i := range names {
+		var value = "<not initialized>"
+		if slots[i] < len(values) {
+			data, err := stackitem.ToJSONWithTypes(values[slots[i]])
+			if err != nil {
+				value = fmt.Sprintf("<%v>", err)
+			} else {
+				value = string(data)
+			}
+		}
+		c.Printf("%s (%s): %s\n", names[i], types[i], value)
+	}
+}
+
 func handleRun(c *ishell.Context) {
 	v := getVMFromContext(c)
 	m := getManifestFromContext(c)
@@ -19,9 +19,14 @@ import (
 	"github.com/nspcc-dev/neo-go/internal/random"
 	"github.com/nspcc-dev/neo-go/pkg/compiler"
 	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
@@ -81,6 +86,20 @@ func newTestVMCLIWithLogo(t *testing.T, printLogo bool) *executor {
 	return e
 }
 
+func newTestVMCLIWithChain(t *testing.T, bc *core.Blockchain) *executor {
+	e := newTestVMCLI(t)
+	var err error
+	e.cli, err = NewWithChain(false,
+		func(int) { e.exit.Store(true) },
+		&readline.Config{
+			Prompt: "",
+			Stdin:  e.in,
+			Stdout: e.out,
+		}, bc)
+	require.NoError(t, err)
+	return e
+}
+
 func (e *executor) runProg(t *testing.T, commands ...string) {
 	cmd := strings.Join(commands, "\n") + "\n"
 	e.in.WriteString(cmd + "\n")
@@ -343,6 +362,40 @@ func TestRunWithDifferentArguments(t *testing.T) {
 	e.checkStack(t, 42)
 }
 
+func TestLoadTx(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	ex := neotest.NewExecutor(t, bc, acc, acc)
+	txHash := ex.CommitteeInvoker(ex.NativeHash(t, nativenames.Gas)).Invoke(t, 8, "decimals")
+
+	w := io.NewBufBinWriter()
+	emit.AppCall(w.BinWriter, ex.NativeHash(t, nativenames.Ledger), "currentIndex", callflag.All)
+	require.NoError(t, w.Err)
+
+	t.Run("no chain", func(t *testing.T) {
+		e := newTestVMCLI(t)
+		e.runProg(t, "loadtx "+txHash.StringLE())
+		e.checkNextLine(t, "Error: no chain available")
+	})
+
+	e := newTestVMCLIWithChain(t, bc)
+	e.runProg(t,
+		"loadtx",
+		"loadtx qwerty",
+		"loadtx "+util.Uint256{1, 2, 3}.StringLE(),
+		"loadtx 0x"+txHash.StringLE(),
+		"run",
+		"loadhex "+hex.EncodeToString(w.Bytes()),
+		"run")
+
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLine(t, "Error: can't get transaction")
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkStack(t, 8)
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkStack(t, bc.BlockHeight())
+}
+
 func TestPrintOps(t *testing.T) {
 	w := io.NewBufBinWriter()
 	emit.String(w.BinWriter, "log")
@@ -410,6 +463,58 @@ func TestBreakpoint(t *testing.T) {
 	e.checkStack(t, 9)
 }
 
+func TestSourceBreakpoints(t *testing.T) {
+	src := `package kek
+func Main(a, b int) int {
+	var c = a + 1
+	return sum(c, b)
+}
+func sum(x, y int) int {
+	s := x + y
+	return s
+}`
+	filename := path.Join(t.TempDir(), "vmtestcontract.go")
+	require.NoError(t, ioutil.WriteFile(filename, []byte(src), os.ModePerm))
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadgo "+filename,
+		"break unknown",
+		"break another.go:3",
+		"break vmtestcontract.go:100",
+		"break sum",
+		"break vmtestcontract.go:8",
+		"run main 3 5",
+		"args",
+		"cont",
+		"locals",
+		"args",
+		"list 1",
+		"cont",
+	)
+
+	e.checkNextLine(t, "READY: loaded \\d* instructions")
+	e.checkError(t, ErrInvalidParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLine(t, "breakpoint added at instruction \\d+")
+	e.checkNextLine(t, "breakpoint added at instruction \\d+")
+
+	e.checkNextLine(t, "at breakpoint \\d+ \\(INITSLOT\\)")
+	e.checkNextLine(t, "x \\(Integer\\): <not initialized>")
+	e.checkNextLine(t, "y \\(Integer\\): <not initialized>")
+
+	e.checkNextLine(t, "at breakpoint \\d+")
+	e.checkNextLine(t, `s \(Integer\): .*"value":"9"`)
+	e.checkNextLine(t, `x \(Integer\): .*"value":"4"`)
+	e.checkNextLine(t, `y \(Integer\): .*"value":"5"`)
+	e.checkNextLine(t, "vmtestcontract.go:8 \\(sum\\)")
+	e.checkNextLine(t, "^\\s+7\\s+s := x \\+ y")
+	e.checkNextLine(t, "^=>\\s+8\\s+return s")
+	e.checkNextLine(t, "^\\s+9\\s+}")
+	e.checkStack(t, 9)
+}
+
 func TestDumpSSlot(t *testing.T) {
 	w := io.NewBufBinWriter()
 	emit.Opcodes(w.BinWriter, opcode.INITSSLOT, 2, // init static slot with size=2
@@ -294,6 +294,37 @@ func (c *Context) DumpArgumentsSlot() string {
 	return dumpSlot(c.arguments)
 }
 
+// StaticSlotItems returns items of the static slot, nil is returned if
+// the slot is not initialized.
+func (c *Context) StaticSlotItems() []stackitem.Item {
+	return slotItems(c.static)
+}
+
+// LocalSlotItems returns items of the local slot, nil is returned if
+// the slot is not initialized.
+func (c *Context) LocalSlotItems() []stackitem.Item {
+	return slotItems(c.local)
+}
+
+// ArgumentsSlotItems returns items of the arguments slot, nil is returned if
+// the slot is not initialized.
+func (c *Context) ArgumentsSlotItems() []stackitem.Item {
+	return slotItems(c.arguments)
+}
+
+// slotItems returns a copy of the given slot contents with stackitem.Null
+// used for unset items.
+func slotItems(s *Slot) []stackitem.Item {
+	if s == nil || s.storage == nil {
+		return nil
+	}
+	res := make([]stackitem.Item, len(s.storage))
+	for i := range res {
+		res[i] = s.Get(i)
+	}
+	return res
+}
+
 // dumpSlot returns json formatted representation of the given slot.
 func dumpSlot(s *Slot) string {
 	b, _ := json.MarshalIndent(s, "", "    ")
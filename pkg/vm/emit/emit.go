@@ -63,6 +63,16 @@ func Int(w *io.BinWriter, i int64) {
 	}
 }
 
+// BigInt emits big integer to the given buffer using the shortest possible
+// instruction.
+func BigInt(w *io.BinWriter, n *big.Int) {
+	if n.IsInt64() {
+		Int(w, n.Int64())
+		return
+	}
+	bigInt(w, n)
+}
+
 func bigInt(w *io.BinWriter, n *big.Int) {
 	buf := bigint.ToPreallocatedBytes(n, make([]byte, 0, 32))
 	if len(buf) == 0 {
@@ -95,6 +95,24 @@ func getSlice(n int) []byte {
 	return data
 }
 
+func TestEmitBigInt(t *testing.T) {
+	t.Run("small", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		BigInt(buf.BinWriter, big.NewInt(7))
+		assert.Equal(t, []byte{byte(opcode.PUSH7)}, buf.Bytes())
+	})
+
+	t.Run("huge", func(t *testing.T) {
+		num := new(big.Int).Lsh(big.NewInt(1), 100)
+		buf := io.NewBufBinWriter()
+		BigInt(buf.BinWriter, num)
+		result := buf.Bytes()
+		assert.Equal(t, 17, len(result))
+		assert.EqualValues(t, opcode.PUSHINT128, result[0])
+		assert.Equal(t, num, bigint.FromBytes(result[1:]))
+	})
+}
+
 func TestBytes(t *testing.T) {
 	t.Run("small slice", func(t *testing.T) {
 		buf := io.NewBufBinWriter()
@@ -0,0 +1,278 @@
+/*
+Package profile implements collecting of contract execution statistics.
+
+Profile gathers the number of executions and the amount of GAS consumed for
+every instruction of every executed script via VM execution hook (see
+Profile.Hook). This data
+can then be mapped through compiler debug information of the contract to
+produce per-line and per-method reports and coverage profiles in the format
+used by standard Go tooling ('go tool cover').
+*/
+package profile
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// Profile contains execution statistics for a set of scripts. It's safe for
+// concurrent use, so the same Profile can be attached to several VMs.
+type Profile struct {
+	lock    sync.RWMutex
+	scripts map[util.Uint160]*scriptStat
+}
+
+// scriptStat contains statistics for a single script.
+type scriptStat struct {
+	counts map[int]int
+	gas    map[int]int64
+}
+
+// InstrStat contains statistics for a single instruction.
+type InstrStat struct {
+	// Count is the number of times instruction was executed.
+	Count int
+	// GAS is the total amount of GAS consumed by the instruction.
+	GAS int64
+}
+
+// LineStat contains statistics for a single source line.
+type LineStat struct {
+	Document string
+	Line     int
+	// Count is the number of times statements starting at this line were
+	// executed.
+	Count int
+	// GAS is the total amount of GAS consumed by instructions corresponding
+	// to statements starting at this line.
+	GAS int64
+}
+
+// MethodStat contains statistics for a single method.
+type MethodStat struct {
+	// Name is the method name in "namespace.ID" form.
+	Name string
+	// Calls is the number of method invocations.
+	Calls int
+	// GAS is the total amount of GAS consumed by method instructions,
+	// calls made from the method are not included.
+	GAS int64
+}
+
+// Report contains execution statistics of a single contract mapped to its
+// sources.
+type Report struct {
+	// Lines contains per-line statistics ordered by document and line.
+	Lines []LineStat
+	// Methods contains per-method statistics ordered by the method offset.
+	Methods []MethodStat
+	// GAS is the total amount of GAS consumed by the contract.
+	GAS int64
+}
+
+// New returns an empty Profile.
+func New() *Profile {
+	return &Profile{
+		scripts: make(map[util.Uint160]*scriptStat),
+	}
+}
+
+// Hook is a vm.OnExecHook collecting statistics, install it into VM with
+// SetOnExecHook to profile its execution.
+func (p *Profile) Hook(ctx *vm.Context, ip int, _ opcode.Opcode, gas int64) {
+	h := ctx.ScriptHash()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	s, ok := p.scripts[h]
+	if !ok {
+		s = &scriptStat{
+			counts: make(map[int]int),
+			gas:    make(map[int]int64),
+		}
+		p.scripts[h] = s
+	}
+	s.counts[ip]++
+	s.gas[ip] += gas
+}
+
+// Instruction returns the statistics for the instruction at the offset ip of
+// the script with the hash h.
+func (p *Profile) Instruction(h util.Uint160, ip int) InstrStat {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	s, ok := p.scripts[h]
+	if !ok {
+		return InstrStat{}
+	}
+	return InstrStat{Count: s.counts[ip], GAS: s.gas[ip]}
+}
+
+// Report maps statistics of the script with the hash h through its debug
+// information di.
+func (p *Profile) Report(h util.Uint160, di *compiler.DebugInfo) *Report {
+	type lineKey struct {
+		doc  int
+		line int
+	}
+	var (
+		r     = new(Report)
+		lines = make(map[lineKey]*LineStat)
+	)
+
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	s, ok := p.scripts[h]
+	if !ok {
+		s = &scriptStat{}
+	}
+	for _, gas := range s.gas {
+		r.GAS += gas
+	}
+
+	methods := sortedMethods(di)
+	for _, m := range methods {
+		ms := MethodStat{
+			Name:  m.Name.Namespace + "." + m.ID,
+			Calls: s.counts[int(m.Range.Start)],
+		}
+		for ip := int(m.Range.Start); ip <= int(m.Range.End); ip++ {
+			ms.GAS += s.gas[ip]
+		}
+		r.Methods = append(r.Methods, ms)
+
+		sps := sortedSeqPoints(m)
+		for i, sp := range sps {
+			k := lineKey{sp.Document, sp.StartLine}
+			ls, ok := lines[k]
+			if !ok {
+				ls = &LineStat{Line: sp.StartLine}
+				if sp.Document < len(di.Documents) {
+					ls.Document = di.Documents[sp.Document]
+				}
+				lines[k] = ls
+			}
+			if c := s.counts[sp.Opcode]; c > ls.Count {
+				ls.Count = c
+			}
+			// GAS of instructions shared by several sequence points is
+			// accounted for the last one.
+			if i+1 < len(sps) && sps[i+1].Opcode == sp.Opcode {
+				continue
+			}
+			end := int(m.Range.End) + 1
+			if i+1 < len(sps) {
+				end = sps[i+1].Opcode
+			}
+			for ip := sp.Opcode; ip < end; ip++ {
+				ls.GAS += s.gas[ip]
+			}
+		}
+	}
+	for _, ls := range lines {
+		r.Lines = append(r.Lines, *ls)
+	}
+	sort.Slice(r.Lines, func(i, j int) bool {
+		if r.Lines[i].Document != r.Lines[j].Document {
+			return r.Lines[i].Document < r.Lines[j].Document
+		}
+		return r.Lines[i].Line < r.Lines[j].Line
+	})
+	return r
+}
+
+// WriteCoverProfile writes coverage profile of the script with the hash h
+// to w in the format used by 'go test -coverprofile' ("count" mode). Every
+// sequence point of di is a separate block. Sources are read to convert
+// offsets stored in the debug information into columns, so all documents
+// must be available at their paths.
+func (p *Profile) WriteCoverProfile(w io.Writer, h util.Uint160, di *compiler.DebugInfo) error {
+	docs := make(map[int][]byte)
+	if _, err := io.WriteString(w, "mode: count\n"); err != nil {
+		return err
+	}
+
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	s, ok := p.scripts[h]
+	if !ok {
+		s = &scriptStat{}
+	}
+	for _, m := range sortedMethods(di) {
+		for _, sp := range sortedSeqPoints(m) {
+			if sp.Document >= len(di.Documents) {
+				return fmt.Errorf("invalid document index %d", sp.Document)
+			}
+			src, ok := docs[sp.Document]
+			if !ok {
+				var err error
+				src, err = ioutil.ReadFile(di.Documents[sp.Document])
+				if err != nil {
+					return fmt.Errorf("can't read document: %w", err)
+				}
+				docs[sp.Document] = src
+			}
+			_, err := fmt.Fprintf(w, "%s:%d.%d,%d.%d 1 %d\n", di.Documents[sp.Document],
+				sp.StartLine, column(src, sp.StartCol), sp.EndLine, column(src, sp.EndCol),
+				s.counts[sp.Opcode])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Print writes human-readable representation of r to w.
+func (r *Report) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "Total GAS: %s\n\n", fixedn.Fixed8(r.GAS))
+	_, _ = fmt.Fprintf(tw, "GAS\tCALLS\tMETHOD\n")
+	for _, m := range r.Methods {
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%s\n", fixedn.Fixed8(m.GAS), m.Calls, m.Name)
+	}
+	_, _ = fmt.Fprintf(tw, "\nGAS\tCOUNT\tLINE\n")
+	for _, l := range r.Lines {
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%s:%d\n", fixedn.Fixed8(l.GAS), l.Count, l.Document, l.Line)
+	}
+	return tw.Flush()
+}
+
+// sortedMethods returns the methods of di ordered by their offsets.
+func sortedMethods(di *compiler.DebugInfo) []compiler.MethodDebugInfo {
+	ms := make([]compiler.MethodDebugInfo, len(di.Methods))
+	copy(ms, di.Methods)
+	sort.Slice(ms, func(i, j int) bool {
+		return ms[i].Range.Start < ms[j].Range.Start
+	})
+	return ms
+}
+
+// sortedSeqPoints returns the sequence points of m ordered by their offsets.
+func sortedSeqPoints(m compiler.MethodDebugInfo) []compiler.DebugSeqPoint {
+	sps := make([]compiler.DebugSeqPoint, len(m.SeqPoints))
+	copy(sps, m.SeqPoints)
+	sort.SliceStable(sps, func(i, j int) bool {
+		return sps[i].Opcode < sps[j].Opcode
+	})
+	return sps
+}
+
+// column converts the offset in src into 1-based column number.
+func column(src []byte, offset int) int {
+	if offset > len(src) {
+		offset = len(src)
+	}
+	return offset - (bytes.LastIndexByte(src[:offset], '\n') + 1) + 1
+}
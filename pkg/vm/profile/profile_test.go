@@ -0,0 +1,90 @@
+package profile
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/core/fee"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+)
+
+const src = `package foo
+func Main(n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		s += double(i)
+	}
+	if s > 100 {
+		return 0
+	}
+	return s
+}
+func double(a int) int {
+	return a * 2
+}`
+
+func TestProfile(t *testing.T) {
+	srcFile := filepath.Join(t.TempDir(), "foo.go")
+	require.NoError(t, ioutil.WriteFile(srcFile, []byte(src), 0644))
+	script, di, err := compiler.CompileWithDebugInfo(srcFile, nil)
+	require.NoError(t, err)
+	h := hash.Hash160(script)
+
+	p := New()
+	v := vm.New()
+	v.GasLimit = -1
+	v.SetPriceGetter(func(op opcode.Opcode, _ []byte) int64 {
+		return fee.Opcode(30, op)
+	})
+	v.SetOnExecHook(p.Hook)
+	v.LoadScriptWithHash(script, h, 0)
+	v.Estack().PushVal(3)
+	require.NoError(t, v.Run())
+	require.Equal(t, int64(6), v.Estack().Pop().BigInt().Int64())
+
+	r := p.Report(h, di)
+	require.Equal(t, v.GasConsumed(), r.GAS)
+	require.Equal(t, 2, len(r.Methods))
+	require.Equal(t, MethodStat{Name: "foo.Main", Calls: 1, GAS: r.Methods[0].GAS}, r.Methods[0])
+	require.Equal(t, MethodStat{Name: "foo.double", Calls: 3, GAS: r.Methods[1].GAS}, r.Methods[1])
+	require.Equal(t, r.GAS, r.Methods[0].GAS+r.Methods[1].GAS)
+
+	counts := make(map[int]int)
+	var linesGAS int64
+	for _, l := range r.Lines {
+		require.Equal(t, srcFile, l.Document)
+		counts[l.Line] = l.Count
+		linesGAS += l.GAS
+	}
+	require.Equal(t, map[int]int{3: 1, 4: 1, 5: 3, 8: 0, 10: 1, 13: 3}, counts)
+	require.True(t, linesGAS <= r.GAS)
+	for _, m := range di.Methods {
+		if m.ID == "double" {
+			require.Equal(t, 3, p.Instruction(h, int(m.Range.Start)).Count)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, r.Print(buf))
+	require.True(t, strings.Contains(buf.String(), "foo.double"))
+
+	buf.Reset()
+	require.NoError(t, p.WriteCoverProfile(buf, h, di))
+	cover := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Equal(t, "mode: count", cover[0])
+	require.Contains(t, cover, srcFile+":13.2,13.14 1 3")
+	require.Contains(t, cover, srcFile+":8.3,8.11 1 0")
+
+	t.Run("unknown script", func(t *testing.T) {
+		r := New().Report(h, di)
+		require.Equal(t, int64(0), r.GAS)
+		require.Equal(t, 0, r.Methods[0].Calls)
+	})
+}
@@ -68,6 +68,9 @@ type VM struct {
 	// callback to get interop price
 	getPrice func(opcode.Opcode, []byte) int64
 
+	// callback invoked after each executed instruction
+	onExec OnExecHook
+
 	istack Stack  // invocation stack.
 	estack *Stack // execution stack.
 
@@ -116,6 +119,19 @@ func (v *VM) SetPriceGetter(f func(opcode.Opcode, []byte) int64) {
 	v.getPrice = f
 }
 
+// OnExecHook is a callback invoked after the execution of every instruction.
+// It receives the context the instruction was executed in, its offset and
+// opcode and the amount of GAS consumed by the instruction itself (that
+// includes syscall prices, but not the GAS spent in the contexts it calls).
+// It's invoked for faulted instructions too.
+type OnExecHook func(ctx *Context, ip int, op opcode.Opcode, gas int64)
+
+// SetOnExecHook installs the hook invoked after every executed instruction,
+// nil removes it.
+func (v *VM) SetOnExecHook(h OnExecHook) {
+	v.onExec = h
+}
+
 // GasConsumed returns the amount of GAS consumed during execution.
 func (v *VM) GasConsumed() int64 {
 	return v.gasConsumed
@@ -250,7 +266,7 @@ func (v *VM) LoadFileWithFlags(path string, f callflag.CallFlag) error {
 	if err != nil {
 		return err
 	}
-	v.Load(nef.Script)
+	v.LoadWithFlags(nef.Script, f)
 	return nil
 }
 
@@ -535,6 +551,11 @@ func (v *VM) execute(ctx *Context, op opcode.Opcode, parameter []byte) (err erro
 		}
 	}()
 
+	if v.onExec != nil && ctx != nil && ctx.ip < len(ctx.prog) {
+		ip, gas := ctx.ip, v.gasConsumed
+		defer func() { v.onExec(ctx, ip, op, v.gasConsumed-gas) }()
+	}
+
 	if v.getPrice != nil && ctx.ip < len(ctx.prog) {
 		v.gasConsumed += v.getPrice(op, parameter)
 		if v.GasLimit >= 0 && v.gasConsumed > v.GasLimit {
@@ -95,6 +95,58 @@ func TestVM_SetPriceGetter(t *testing.T) {
 	})
 }
 
+func TestVM_SetOnExecHook(t *testing.T) {
+	type execInfo struct {
+		ip  int
+		op  opcode.Opcode
+		gas int64
+	}
+	var execs []execInfo
+
+	v := newTestVM()
+	v.SyscallHandler = fooInteropHandler
+	v.SetPriceGetter(func(op opcode.Opcode, p []byte) int64 {
+		if op == opcode.SYSCALL {
+			return 2
+		}
+		return 1
+	})
+	v.SetOnExecHook(func(ctx *Context, ip int, op opcode.Opcode, gas int64) {
+		execs = append(execs, execInfo{ip, op, gas})
+	})
+
+	buf := io.NewBufBinWriter()
+	emit.Opcodes(buf.BinWriter, opcode.PUSH1)
+	emit.Syscall(buf.BinWriter, "foo")
+	emit.Opcodes(buf.BinWriter, opcode.ADD, opcode.RET)
+	v.Load(buf.Bytes())
+	runVM(t, v)
+	require.Equal(t, []execInfo{
+		{0, opcode.PUSH1, 1},
+		{1, opcode.SYSCALL, 3}, // Syscall price is included.
+		{6, opcode.ADD, 1},
+		{7, opcode.RET, 1},
+	}, execs)
+
+	t.Run("fault", func(t *testing.T) {
+		execs = execs[:0]
+		v.Load([]byte{byte(opcode.PUSH1), byte(opcode.THROW)})
+		checkVMFailed(t, v)
+		require.Equal(t, []execInfo{
+			{0, opcode.PUSH1, 1},
+			{1, opcode.THROW, 1},
+		}, execs)
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		execs = execs[:0]
+		v.SetOnExecHook(nil)
+		v.Load([]byte{byte(opcode.PUSH1), byte(opcode.RET)})
+		runVM(t, v)
+		require.Equal(t, 0, len(execs))
+	})
+}
+
 func TestAddGas(t *testing.T) {
 	v := newTestVM()
 	v.GasLimit = 10